@@ -5,22 +5,51 @@ import "github.com/sjengpho/tin/grpc"
 // SystemCommander is the interface implemented by an object that can
 // output system related info.
 //
-// SystemUpdates outputs the available update count.
+// SystemUpdates outputs the available or security update count.
 // SystemInstalled outputs the installed packages.
+// SystemHistory outputs the package history.
+// SystemLastUpgrade outputs how long ago the last full system upgrade was.
+// SystemRebootRequired outputs whether a reboot is required and why.
+// SystemMaintenance outputs the orphans, foreign packages and cache size.
+// SystemPackage outputs the details of an installed package.
 // SystemTemperatureCelsius outputs the temperature in celsius format.
 // SystemTemperatureFahrenheit outputs the temperature in fahrenheit format.
 type SystemCommander interface {
-	SystemUpdates(c *grpc.Client)
+	SystemUpdates(c *grpc.Client, flags SystemUpdatesFlags)
 	SystemInstalled(c *grpc.Client, flags SystemInstalledFlags)
+	SystemHistory(c *grpc.Client, flags SystemHistoryFlags)
+	SystemLastUpgrade(c *grpc.Client)
+	SystemRebootRequired(c *grpc.Client)
+	SystemMaintenance(c *grpc.Client, flags SystemMaintenanceFlags)
+	SystemPackage(c *grpc.Client, name string)
 	SystemTemperatureCelsius(c *grpc.Client)
 	SystemTemperatureFahrenheit(c *grpc.Client)
 }
 
+// SystemUpdatesFlags represents the flags.
+type SystemUpdatesFlags struct {
+	Security bool
+}
+
 // SystemInstalledFlags represents the flags.
 type SystemInstalledFlags struct {
 	Subscribe  bool
+	Changes    bool
 	Export     bool
 	ExportPath string
+	Sort       string
+}
+
+// SystemHistoryFlags represents the flags.
+type SystemHistoryFlags struct {
+	From    string
+	To      string
+	Package string
+}
+
+// SystemMaintenanceFlags represents the flags.
+type SystemMaintenanceFlags struct {
+	List bool
 }
 
 // NetworkCommander is the interface implemented by an object that can
@@ -28,17 +57,73 @@ type SystemInstalledFlags struct {
 //
 // ESSID outputs the network name.
 // IP outputs the public IP address.
+// Interfaces outputs the network interfaces, default routes and DNS servers.
+// Throughput outputs the traffic of the network interfaces.
+// WiFi outputs the link of the connected wireless interface.
+// Status outputs the connectivity and the latency of the probed targets.
+// VPN outputs the active VPN.
 type NetworkCommander interface {
 	ESSID(c *grpc.Client)
-	IP(c *grpc.Client)
+	IP(c *grpc.Client, flags NetworkIPFlags)
+	Interfaces(c *grpc.Client)
+	Throughput(c *grpc.Client, flags NetworkThroughputFlags)
+	WiFi(c *grpc.Client)
+	Status(c *grpc.Client)
+	VPN(c *grpc.Client)
+}
+
+// NetworkIPFlags represents the flags.
+type NetworkIPFlags struct {
+	All     bool
+	Details bool
+}
+
+// NetworkThroughputFlags represents the flags.
+type NetworkThroughputFlags struct {
+	Subscribe bool
 }
 
 // GmailCommander is the interface implemented by an object that can
 // output gmail related info.
 //
 // Login attempts to authorize the user at Gmail.
-// Unread outputs the unread mail count.
+// Unread outputs the unread mail count, or the count of a named query.
+// List outputs a preview of the unread mails grouped by thread.
 type GmailCommander interface {
-	Login(c *grpc.Client)
-	Unread(c *grpc.Client)
+	Login(c *grpc.Client, flags GmailLoginFlags)
+	Unread(c *grpc.Client, flags GmailUnreadFlags)
+	List(c *grpc.Client, flags GmailListFlags)
+}
+
+// GmailLoginFlags represents the flags.
+type GmailLoginFlags struct {
+	Account string
+	Manual  bool
+}
+
+// GmailUnreadFlags represents the flags.
+type GmailUnreadFlags struct {
+	Account string
+	Query   string
+}
+
+// GmailListFlags represents the flags.
+type GmailListFlags struct {
+	Account string
+	Limit   int
+}
+
+// MailCommander is the interface implemented by an object that can
+// output mail related info of all mail accounts.
+//
+// Unread outputs the unread mail count.
+// Accounts outputs the unread mail count of every account.
+type MailCommander interface {
+	Unread(c *grpc.Client, flags MailUnreadFlags)
+	Accounts(c *grpc.Client)
+}
+
+// MailUnreadFlags represents the flags.
+type MailUnreadFlags struct {
+	Account string
 }
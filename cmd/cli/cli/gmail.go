@@ -2,11 +2,19 @@ package cli
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"os/exec"
+	"strings"
+	"time"
 
 	"github.com/sjengpho/tin/grpc"
+	"github.com/sjengpho/tin/proto/pb"
 )
 
 // NewGmailCommander returns a cli.GmailCommander.
@@ -17,32 +25,74 @@ func NewGmailCommander() GmailCommander {
 // gmailCommander implements cli.GmailCommander.
 type gmailCommander struct{}
 
+// loginTimeout is the maximum duration of waiting for the redirect of the consent page.
+const loginTimeout = 5 * time.Minute
+
 // Login attempts to authorize the user.
 //
-// It generates an auth URL and asks the user to enter the authorization code.
-func (s *gmailCommander) Login(c *grpc.Client) {
-	authURL, err := c.GmailAuthURL()
+// It generates an auth URL that redirects to a temporary loopback listener,
+// opens it in the browser and completes the authorization when the redirect
+// arrives. With the manual flag it asks the user to enter the authorization
+// code instead, for machines without a browser.
+// The account can be omitted when there is a single Gmail account.
+func (s *gmailCommander) Login(c *grpc.Client, flags GmailLoginFlags) {
+	if flags.Manual {
+		s.manualLogin(c, flags)
+		return
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
-		credentialsSuggestion := "Place the credentials.json at ~/.config/tin/gmail/credentials.json."
-		if r, err := c.Config(); err == nil {
-			fmt.Println(c)
-			credentialsSuggestion = fmt.Sprintf("Place the credentials.json at %v", r.Config.GetGmailCredentials())
-		}
+		log.Printf("failed listening on a loopback address, try --manual: %v", err)
+		return
+	}
+	defer l.Close()
+
+	authURL, state, err := c.GmailAuthURL(flags.Account, fmt.Sprintf("http://%v/", l.Addr()))
+	if err != nil {
+		printAuthURLError(c, err)
+		return
+	}
 
-		fmt.Println("Failed generating the authorization url")
-		fmt.Println("")
-		fmt.Println("- Enable the Gmail API (https://support.google.com/googleapi/answer/6158841?hl=en)")
-		fmt.Printf("- %v\n", credentialsSuggestion)
+	if err := openBrowser(authURL); err != nil {
+		fmt.Printf("Visit the link below to authorize tin\n\n%v\n\n", authURL)
+	} else {
+		fmt.Printf("Authorize tin in the browser, or visit the link below\n\n%v\n\n", authURL)
+	}
+
+	code, err := waitAuthCode(l, state, loginTimeout)
+	if err != nil {
+		log.Printf("failed receiving the authorization code: %v", err)
+		return
+	}
+
+	if !c.GmailAuthCode(code, flags.Account, state) {
+		log.Printf("failed authorizing using code: %v", code)
+		return
+	}
+	fmt.Println("Login success")
+}
 
+// manualLogin generates an auth URL and asks the user to enter the
+// authorization code, or the URL the consent page redirected to.
+func (s *gmailCommander) manualLogin(c *grpc.Client, flags GmailLoginFlags) {
+	authURL, state, err := c.GmailAuthURL(flags.Account, "")
+	if err != nil {
+		printAuthURLError(c, err)
 		return
 	}
 
 	fmt.Printf("Visit the link below to retrieve an authorization code\n\n%v\n\n", authURL)
 	reader := bufio.NewReader(os.Stdin)
 	fmt.Print("Authorization code: ")
-	code, _ := reader.ReadString('\n')
+	input, _ := reader.ReadString('\n')
 
-	success := c.GmailAuthCode(code)
+	code := strings.TrimSpace(input)
+	if u, err := url.Parse(code); err == nil && u.Query().Get("code") != "" {
+		code = u.Query().Get("code")
+	}
+
+	success := c.GmailAuthCode(code, flags.Account, state)
 	if !success {
 		log.Printf("failed authorizing using code: %v", code)
 		return
@@ -50,12 +100,151 @@ func (s *gmailCommander) Login(c *grpc.Client) {
 	fmt.Println("\nLogin success")
 }
 
-// Unread outputs the unread mail count.
-func (s *gmailCommander) Unread(c *grpc.Client) {
-	unread, err := c.GmailUnread()
+// printAuthURLError outputs the error of generating the auth URL with suggestions.
+func printAuthURLError(c *grpc.Client, err error) {
+	credentialsSuggestion := "Place the credentials.json at ~/.config/tin/gmail/credentials.json."
+	if r, err := c.Config(); err == nil {
+		credentialsSuggestion = fmt.Sprintf("Place the credentials.json at %v", r.Config.GetGmailCredentials())
+	}
+
+	fmt.Printf("Failed generating the authorization url: %v\n", err)
+	fmt.Println("")
+	fmt.Println("- Enable the Gmail API (https://support.google.com/googleapi/answer/6158841?hl=en)")
+	fmt.Printf("- %v\n", credentialsSuggestion)
+}
+
+// waitAuthCode serves the listener until the consent page redirects to it
+// with the state, and returns the authorization code of the redirect.
+//
+// Requests with another state are rejected, the redirect might not be ours.
+func waitAuthCode(l net.Listener, state string, timeout time.Duration) (string, error) {
+	type result struct {
+		code string
+		err  error
+	}
+	ch := make(chan result, 1)
+
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/" || q.Get("state") != state {
+			http.NotFound(w, r)
+			return
+		}
+
+		res := result{code: q.Get("code")}
+		switch {
+		case q.Get("error") != "":
+			res.err = fmt.Errorf("authorization denied: %v", q.Get("error"))
+		case res.code == "":
+			res.err = errors.New("authorization code missing")
+		}
+
+		if res.err != nil {
+			fmt.Fprintf(w, "Login failed: %v\n", res.err)
+		} else {
+			fmt.Fprintln(w, "Login success, you can close this window.")
+		}
+
+		select {
+		case ch <- res:
+		default:
+		}
+	})}
+	go server.Serve(l)
+	defer server.Close()
+
+	select {
+	case res := <-ch:
+		return res.code, res.err
+	case <-time.After(timeout):
+		return "", errors.New("timed out")
+	}
+}
+
+// openBrowser opens the URL with xdg-open.
+//
+// An error is returned when there is no graphical session, xdg-open would
+// fall back to a text browser in the terminal.
+func openBrowser(u string) error {
+	if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		return errors.New("no graphical session")
+	}
+
+	path, err := exec.LookPath("xdg-open")
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(path, u)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// Unread outputs the unread mail count, or the count of the query.
+func (s *gmailCommander) Unread(c *grpc.Client, flags GmailUnreadFlags) {
+	unread, err := c.GmailUnread(flags.Account, flags.Query)
 	if err != nil {
 		log.Printf("failed getting the unread mail count: %v", err)
 		return
 	}
 	fmt.Println(unread)
 }
+
+// List outputs a preview of the unread mails, newest first.
+//
+// The mails of a thread are combined into one line with the number of mails.
+// Example of the output:
+// 2020-01-01 12:00 Alice (2) Re: Lunch
+// 2020-01-01 11:00 bob@example.com Report
+func (s *gmailCommander) List(c *grpc.Client, flags GmailListFlags) {
+	r, err := c.UnreadMails(flags.Account, flags.Limit)
+	if err != nil {
+		log.Printf("failed getting the unread mails: %v", err)
+		return
+	}
+
+	for _, t := range groupThreads(r.GetMails()) {
+		m := t[0]
+		date := "-"
+		if m.GetDate() > 0 {
+			date = time.Unix(m.GetDate(), 0).Format("2006-01-02 15:04")
+		}
+
+		from := m.GetFrom()
+		if len(t) > 1 {
+			from = fmt.Sprintf("%v (%v)", from, len(t))
+		}
+
+		subject := m.GetSubject()
+		if subject == "" {
+			subject = "(no subject)"
+		}
+		fmt.Printf("%v %v %v\n", date, from, subject)
+	}
+}
+
+// groupThreads returns the mails grouped by thread in the order of the first mail of every thread.
+//
+// Mails without thread are a thread of their own.
+func groupThreads(mails []*pb.Mail) [][]*pb.Mail {
+	threads := [][]*pb.Mail{}
+	index := map[string]int{}
+	for _, m := range mails {
+		if m.GetThreadId() == "" {
+			threads = append(threads, []*pb.Mail{m})
+			continue
+		}
+
+		key := m.GetAccount() + "/" + m.GetThreadId()
+		if i, ok := index[key]; ok {
+			threads[i] = append(threads[i], m)
+			continue
+		}
+		index[key] = len(threads)
+		threads = append(threads, []*pb.Mail{m})
+	}
+	return threads
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/sjengpho/tin/grpc"
+)
+
+// NewMailCommander returns a cli.MailCommander.
+func NewMailCommander() MailCommander {
+	return &mailCommander{}
+}
+
+// mailCommander implements cli.MailCommander.
+type mailCommander struct{}
+
+// Unread outputs the unread mail count of the account, or of all accounts.
+func (s *mailCommander) Unread(c *grpc.Client, flags MailUnreadFlags) {
+	unread, err := c.MailUnread(flags.Account)
+	if err != nil {
+		log.Printf("failed getting the unread mail count: %v", err)
+		return
+	}
+	fmt.Println(unread)
+}
+
+// Accounts outputs the unread mail count of every account and the total.
+//
+// Example of the output:
+// personal 3
+// work 12 (failed: dial tcp: i/o timeout)
+// total 15
+func (s *mailCommander) Accounts(c *grpc.Client) {
+	r, err := c.MailAccounts()
+	if err != nil {
+		log.Printf("failed getting the mail accounts: %v", err)
+		return
+	}
+
+	for _, a := range r.GetAccounts() {
+		if a.GetError() != "" {
+			fmt.Printf("%v %v (failed: %v)\n", a.GetName(), a.GetUnread(), a.GetError())
+			continue
+		}
+		fmt.Printf("%v %v\n", a.GetName(), a.GetUnread())
+	}
+	fmt.Printf("total %v\n", r.GetTotal())
+}
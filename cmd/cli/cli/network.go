@@ -3,8 +3,11 @@ package cli
 import (
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/sjengpho/tin/grpc"
+	"github.com/sjengpho/tin/proto/pb"
 )
 
 // NewNetworkCommander returns a cli.NetworkCommander.
@@ -26,11 +29,248 @@ func (s *networkCommander) ESSID(c *grpc.Client) {
 }
 
 // IP outputs the IP address.
-func (s *networkCommander) IP(c *grpc.Client) {
-	v, err := c.IPAddress()
+//
+// Example of the output with all flag:
+// ipv4 203.0.113.7 https://ifconfig.me/ip https://ifconfig.co/ip
+// ipv6 2001:db8::7 https://ifconfig.co/ip
+//
+// Example of the output with details flag:
+// 203.0.113.7 Amsterdam, Netherlands (NL) AS64500 Example ISP
+func (s *networkCommander) IP(c *grpc.Client, flags NetworkIPFlags) {
+	if flags.Details {
+		s.outputIPDetails(c)
+		return
+	}
+
+	if !flags.All {
+		v, err := c.IPAddress()
+		if err != nil {
+			log.Printf("failed getting the IP address: %v", err)
+			return
+		}
+		fmt.Println(v)
+		return
+	}
+
+	r, err := c.PublicIP()
 	if err != nil {
 		log.Printf("failed getting the IP address: %v", err)
 		return
 	}
-	fmt.Println(v)
+	if r.GetIpv4() != "" {
+		fmt.Println(strings.Join(append([]string{"ipv4", r.GetIpv4()}, r.GetIpv4Sources()...), " "))
+	}
+	if r.GetIpv6() != "" {
+		fmt.Println(strings.Join(append([]string{"ipv6", r.GetIpv6()}, r.GetIpv6Sources()...), " "))
+	}
+}
+
+// outputIPDetails prints the IP address with its location and ISP to standard output.
+func (s *networkCommander) outputIPDetails(c *grpc.Client) {
+	r, err := c.PublicIP()
+	if err != nil {
+		log.Printf("failed getting the IP address: %v", err)
+		return
+	}
+
+	d := r.GetDetails()
+	fields := []string{r.GetValue()}
+	location := []string{}
+	for _, v := range []string{d.GetCity(), d.GetCountry()} {
+		if v != "" {
+			location = append(location, v)
+		}
+	}
+	if len(location) > 0 {
+		fields = append(fields, strings.Join(location, ", "))
+	}
+	if d.GetCountryCode() != "" {
+		fields = append(fields, fmt.Sprintf("(%v)", d.GetCountryCode()))
+	}
+	if d.GetAsn() > 0 {
+		fields = append(fields, fmt.Sprintf("AS%v", d.GetAsn()))
+	}
+	if d.GetIsp() != "" {
+		fields = append(fields, d.GetIsp())
+	}
+	fmt.Println(strings.Join(fields, " "))
+}
+
+// Interfaces outputs the network interfaces, default routes and DNS servers.
+//
+// Example of the output:
+// wlan0 up 3c:22:fb:12:34:56 mtu 1500 192.168.1.10/24
+// default via 192.168.1.1 dev wlan0
+// nameserver 192.168.1.1
+func (s *networkCommander) Interfaces(c *grpc.Client) {
+	interfaces, err := c.Interfaces()
+	if err != nil {
+		log.Printf("failed getting the interfaces: %v", err)
+		return
+	}
+
+	routes, err := c.Routes()
+	if err != nil {
+		log.Printf("failed getting the routes: %v", err)
+		return
+	}
+
+	for _, i := range interfaces.GetInterfaces() {
+		fields := []string{i.GetName(), i.GetState()}
+		if i.GetMac() != "" {
+			fields = append(fields, i.GetMac())
+		}
+		fields = append(fields, fmt.Sprintf("mtu %v", i.GetMtu()))
+		fields = append(fields, i.GetAddresses()...)
+		fmt.Println(strings.Join(fields, " "))
+	}
+
+	for _, r := range routes.GetRoutes() {
+		if !r.GetDefaultRoute() {
+			continue
+		}
+		if r.GetGateway() == "" {
+			fmt.Printf("default dev %v\n", r.GetInterface())
+			continue
+		}
+		fmt.Printf("default via %v dev %v\n", r.GetGateway(), r.GetInterface())
+	}
+
+	for _, d := range routes.GetDnsServers() {
+		fmt.Printf("nameserver %v\n", d)
+	}
+}
+
+// Throughput outputs the traffic of the network interfaces.
+func (s *networkCommander) Throughput(c *grpc.Client, flags NetworkThroughputFlags) {
+	if flags.Subscribe {
+		if err := c.ThroughputSubscribe(s.outputThroughput); err != nil {
+			log.Printf("failed getting the throughput: %v", err)
+		}
+		return
+	}
+
+	r, err := c.Throughput()
+	if err != nil {
+		log.Printf("failed getting the throughput: %v", err)
+		return
+	}
+	s.outputThroughput(r)
+}
+
+// outputThroughput prints the throughput to standard output.
+//
+// Example of a line: wlan0 rx 1.2 MiB/s tx 35.0 KiB/s total rx 1.4 GiB tx 61.2 MiB session rx 2.0 MiB tx 1.0 MiB
+func (s *networkCommander) outputThroughput(r *pb.ThroughputResponse) {
+	for _, i := range r.GetInterfaces() {
+		fmt.Printf("%v rx %v/s tx %v/s total rx %v tx %v session rx %v tx %v\n",
+			i.GetInterface(),
+			formatBytes(int64(i.GetRxRate())),
+			formatBytes(int64(i.GetTxRate())),
+			formatBytes(int64(i.GetRxTotal())),
+			formatBytes(int64(i.GetTxTotal())),
+			formatBytes(int64(i.GetRxSession())),
+			formatBytes(int64(i.GetTxSession())),
+		)
+	}
+}
+
+// WiFi outputs the link of the connected wireless interface.
+//
+// Example of the output:
+// network-name 3c:22:fb:12:34:56 on wlan0
+// channel 36 (5180 MHz) signal -50 dBm (85%) tx bitrate 866.7 Mbit/s
+// connected for 1h0m0s
+func (s *networkCommander) WiFi(c *grpc.Client) {
+	r, err := c.WirelessLink()
+	if err != nil {
+		log.Printf("failed getting the wireless link: %v", err)
+		return
+	}
+
+	l := r.GetLink()
+	if !l.GetConnected() {
+		fmt.Println("not connected")
+		return
+	}
+
+	fmt.Printf("%v %v on %v\n", l.GetSsid(), l.GetBssid(), l.GetInterface())
+	fmt.Printf("channel %v (%v MHz) signal %v dBm (%v%%) tx bitrate %v Mbit/s\n",
+		l.GetChannel(),
+		l.GetFrequency(),
+		l.GetSignal(),
+		l.GetQuality(),
+		l.GetTxBitrate(),
+	)
+	if l.GetConnectedSince() > 0 {
+		since := time.Unix(l.GetConnectedSince(), 0)
+		fmt.Printf("connected for %v\n", time.Since(since).Round(time.Second))
+	}
+}
+
+// Status outputs the connectivity and the latency of the probed targets.
+//
+// Example of the output:
+// online
+// tcp://1.1.1.1:443 12ms 100%
+// dns://archlinux.org failed 80%
+func (s *networkCommander) Status(c *grpc.Client) {
+	r, err := c.Connectivity()
+	if err != nil {
+		log.Printf("failed getting the connectivity: %v", err)
+		return
+	}
+
+	status := r.GetStatus()
+	if status == "" {
+		status = "unknown"
+	}
+	fmt.Println(status)
+
+	for _, p := range r.GetProbes() {
+		latency := "failed"
+		if p.GetSuccess() {
+			latency = fmt.Sprintf("%vms", p.GetLatency())
+		}
+		fmt.Printf("%v %v %.0f%%\n", p.GetTarget(), latency, p.GetSuccessRatio()*100)
+	}
+}
+
+// VPN outputs the active VPN.
+//
+// Example of the output:
+// WireGuard wg0 198.51.100.1:51820 default route
+// up for 2h3m0s, latest handshake 1m12s ago
+func (s *networkCommander) VPN(c *grpc.Client) {
+	r, err := c.VPN()
+	if err != nil {
+		log.Printf("failed getting the vpn: %v", err)
+		return
+	}
+
+	v := r.GetVpn()
+	if !v.GetActive() {
+		fmt.Println("no vpn")
+		return
+	}
+
+	fields := []string{v.GetName(), v.GetInterface()}
+	if v.GetEndpoint() != "" {
+		fields = append(fields, v.GetEndpoint())
+	}
+	if v.GetDefaultRoute() {
+		fields = append(fields, "default route")
+	}
+	fmt.Println(strings.Join(fields, " "))
+
+	details := []string{}
+	if v.GetSince() > 0 {
+		details = append(details, fmt.Sprintf("up for %v", time.Since(time.Unix(v.GetSince(), 0)).Round(time.Second)))
+	}
+	if v.GetLatestHandshake() > 0 {
+		details = append(details, fmt.Sprintf("latest handshake %v ago", time.Since(time.Unix(v.GetLatestHandshake(), 0)).Round(time.Second)))
+	}
+	if len(details) > 0 {
+		fmt.Println(strings.Join(details, ", "))
+	}
 }
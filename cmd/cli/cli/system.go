@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/sjengpho/tin/grpc"
 	"github.com/sjengpho/tin/proto/pb"
@@ -18,8 +20,18 @@ func NewSystemCommander() SystemCommander {
 // systemCommander implements cli.SystemCommander.
 type systemCommander struct{}
 
-// SystemUpdates outputs the available update count.
-func (s *systemCommander) SystemUpdates(c *grpc.Client) {
+// SystemUpdates outputs the available or security update count.
+func (s *systemCommander) SystemUpdates(c *grpc.Client, flags SystemUpdatesFlags) {
+	if flags.Security {
+		v, err := c.SecurityUpdates()
+		if err != nil {
+			log.Printf("failed getting the security updates: %v", err)
+			return
+		}
+		fmt.Println(v)
+		return
+	}
+
 	v, err := c.AvailableUpdates()
 	if err != nil {
 		log.Printf("failed getting the available updates: %v", err)
@@ -50,6 +62,18 @@ func (s *systemCommander) SystemTemperatureFahrenheit(c *grpc.Client) {
 
 // SystemInstalled outputs or exports the installed packages.
 func (s *systemCommander) SystemInstalled(c *grpc.Client, flags SystemInstalledFlags) {
+	if flags.Changes && flags.Export {
+		c.InstalledPackagesChanges(func(r *pb.InstalledPackagesChangesResponse) {
+			s.exportPackageChanges(flags.ExportPath, r)
+		})
+		return
+	}
+
+	if flags.Changes {
+		c.InstalledPackagesChanges(s.outputPackageChanges)
+		return
+	}
+
 	if flags.Subscribe && flags.Export {
 		c.InstalledPackagesSubscribe(func(r *pb.InstalledPackagesResponse) {
 			s.exportPackages(flags.ExportPath, r)
@@ -57,7 +81,7 @@ func (s *systemCommander) SystemInstalled(c *grpc.Client, flags SystemInstalledF
 	}
 
 	if !flags.Subscribe && flags.Export {
-		r, err := c.InstalledPackages()
+		r, err := c.InstalledPackages(flags.Sort)
 		if err != nil {
 			log.Printf("failed getting installed packages: %v", err)
 			return
@@ -70,7 +94,7 @@ func (s *systemCommander) SystemInstalled(c *grpc.Client, flags SystemInstalledF
 		c.InstalledPackagesSubscribe(s.outputPackages)
 	}
 
-	r, err := c.InstalledPackages()
+	r, err := c.InstalledPackages(flags.Sort)
 	if err != nil {
 		log.Printf("failed getting installed packages: %v", err)
 		return
@@ -79,9 +103,18 @@ func (s *systemCommander) SystemInstalled(c *grpc.Client, flags SystemInstalledF
 }
 
 // outputPackages prints the packages to standard output.
+//
+// The installed size and the source are appended when they are known.
 func (s *systemCommander) outputPackages(r *pb.InstalledPackagesResponse) {
 	for _, p := range r.Packages {
-		fmt.Printf("%v %v\n", p.GetName(), p.GetVersion())
+		line := fmt.Sprintf("%v %v", p.GetName(), p.GetVersion())
+		if p.GetInstalledSize() > 0 {
+			line += " " + formatBytes(p.GetInstalledSize())
+		}
+		if p.GetSource() != "" {
+			line += fmt.Sprintf(" [%v]", p.GetSource())
+		}
+		fmt.Println(line)
 	}
 }
 
@@ -111,3 +144,228 @@ func (s *systemCommander) exportPackages(path string, r *pb.InstalledPackagesRes
 	}
 	writer.Flush()
 }
+
+// outputPackageChanges prints the package changes to standard output.
+//
+// Every line is prefixed with the time and a + for added, - for removed
+// and ~ for upgraded packages.
+func (s *systemCommander) outputPackageChanges(r *pb.InstalledPackagesChangesResponse) {
+	t := time.Unix(r.GetTimestamp(), 0).Format(time.RFC3339)
+	for _, p := range r.GetAdded() {
+		fmt.Printf("%v + %v %v\n", t, p.GetName(), p.GetVersion())
+	}
+	for _, p := range r.GetRemoved() {
+		fmt.Printf("%v - %v %v\n", t, p.GetName(), p.GetVersion())
+	}
+	for _, p := range r.GetUpgraded() {
+		fmt.Printf("%v ~ %v %v -> %v\n", t, p.GetName(), p.GetFromVersion(), p.GetToVersion())
+	}
+}
+
+// exportPackageChanges appends the package changes to a CSV file.
+//
+// The header is only written when the file is empty.
+func (s *systemCommander) exportPackageChanges(path string, r *pb.InstalledPackagesChangesResponse) {
+	name := path
+	if name == "" {
+		name = "installed_packages_changes.csv"
+	}
+
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		log.Printf("failed opening file: %v", err)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		log.Printf("failed opening file: %v", err)
+		return
+	}
+
+	records := [][]string{}
+	if info.Size() == 0 {
+		records = append(records, []string{"Time", "Change", "Name", "From", "To"})
+	}
+
+	t := time.Unix(r.GetTimestamp(), 0).Format(time.RFC3339)
+	for _, p := range r.GetAdded() {
+		records = append(records, []string{t, "added", p.GetName(), "", p.GetVersion()})
+	}
+	for _, p := range r.GetRemoved() {
+		records = append(records, []string{t, "removed", p.GetName(), p.GetVersion(), ""})
+	}
+	for _, p := range r.GetUpgraded() {
+		records = append(records, []string{t, "upgraded", p.GetName(), p.GetFromVersion(), p.GetToVersion()})
+	}
+
+	if err := csv.NewWriter(file).WriteAll(records); err != nil {
+		log.Printf("failed writing to file: %v", err)
+	}
+}
+
+// SystemHistory outputs the package history.
+//
+// A date without a time is interpreted as the start of that day for --from
+// and as the end of that day for --to.
+func (s *systemCommander) SystemHistory(c *grpc.Client, flags SystemHistoryFlags) {
+	from, err := s.parseTime(flags.From, false)
+	if err != nil {
+		log.Printf("failed parsing from: %v", err)
+		return
+	}
+
+	to, err := s.parseTime(flags.To, true)
+	if err != nil {
+		log.Printf("failed parsing to: %v", err)
+		return
+	}
+
+	r, err := c.PackageHistory(from, to, flags.Package)
+	if err != nil {
+		log.Printf("failed getting the package history: %v", err)
+		return
+	}
+
+	for _, e := range r.GetEvents() {
+		t := time.Unix(e.GetTimestamp(), 0).Format(time.RFC3339)
+		switch {
+		case e.GetFromVersion() == "":
+			fmt.Printf("%v %v %v %v\n", t, e.GetAction(), e.GetName(), e.GetToVersion())
+		case e.GetToVersion() == "":
+			fmt.Printf("%v %v %v %v\n", t, e.GetAction(), e.GetName(), e.GetFromVersion())
+		default:
+			fmt.Printf("%v %v %v %v -> %v\n", t, e.GetAction(), e.GetName(), e.GetFromVersion(), e.GetToVersion())
+		}
+	}
+}
+
+// SystemLastUpgrade outputs how long ago the last full system upgrade was.
+func (s *systemCommander) SystemLastUpgrade(c *grpc.Client) {
+	v, err := c.LastFullUpgrade()
+	if err != nil {
+		log.Printf("failed getting the last full upgrade: %v", err)
+		return
+	}
+
+	if v.IsZero() {
+		fmt.Println("Unknown")
+		return
+	}
+
+	switch days := int(time.Since(v).Hours() / 24); days {
+	case 0:
+		fmt.Println("today")
+	case 1:
+		fmt.Println("1 day ago")
+	default:
+		fmt.Printf("%v days ago\n", days)
+	}
+}
+
+// SystemRebootRequired outputs yes or no, followed by the reasons.
+func (s *systemCommander) SystemRebootRequired(c *grpc.Client) {
+	v, err := c.RebootRequired()
+	if err != nil {
+		log.Printf("failed getting whether a reboot is required: %v", err)
+		return
+	}
+
+	if !v.GetRequired() {
+		fmt.Println("no")
+		return
+	}
+
+	fmt.Println("yes")
+	for _, r := range v.GetReasons() {
+		fmt.Printf("- %v\n", r)
+	}
+}
+
+// SystemPackage outputs the details of an installed package.
+func (s *systemCommander) SystemPackage(c *grpc.Client, name string) {
+	r, err := c.PackageInfo(name)
+	if err != nil {
+		log.Printf("failed getting the package details: %v", err)
+		return
+	}
+
+	d := r.GetDetails()
+	reason := "Dependency"
+	if d.GetExplicit() {
+		reason = "Explicit"
+	}
+	installDate := "Unknown"
+	if d.GetInstallDate() != 0 {
+		installDate = time.Unix(d.GetInstallDate(), 0).Format(time.RFC3339)
+	}
+
+	fmt.Printf("Name:           %v\n", d.GetName())
+	fmt.Printf("Version:        %v\n", d.GetVersion())
+	fmt.Printf("Description:    %v\n", d.GetDescription())
+	fmt.Printf("Installed size: %v\n", formatBytes(d.GetInstalledSize()))
+	fmt.Printf("Install date:   %v\n", installDate)
+	fmt.Printf("Install reason: %v\n", reason)
+	fmt.Printf("Depends on:     %v\n", strings.Join(d.GetDependencies(), " "))
+	fmt.Printf("Required by:    %v\n", strings.Join(d.GetRequiredBy(), " "))
+}
+
+// SystemMaintenance outputs the orphans, foreign packages and cache size.
+func (s *systemCommander) SystemMaintenance(c *grpc.Client, flags SystemMaintenanceFlags) {
+	v, err := c.Maintenance()
+	if err != nil {
+		log.Printf("failed getting the maintenance summary: %v", err)
+		return
+	}
+
+	fmt.Printf("Orphans: %v\n", len(v.GetOrphans()))
+	if flags.List {
+		for _, p := range v.GetOrphans() {
+			fmt.Printf("  %v %v\n", p.GetName(), p.GetVersion())
+		}
+	}
+
+	fmt.Printf("Foreign: %v\n", len(v.GetForeign()))
+	if flags.List {
+		for _, p := range v.GetForeign() {
+			fmt.Printf("  %v %v\n", p.GetName(), p.GetVersion())
+		}
+	}
+
+	fmt.Printf("Cache: %v\n", formatBytes(v.GetCacheSize()))
+}
+
+// parseTime parses a date or a RFC3339 formatted time.
+//
+// An empty string returns a zero time.Time.
+func (s *systemCommander) parseTime(v string, endOfDay bool) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", v, time.Local); err == nil {
+		if endOfDay {
+			return t.AddDate(0, 0, 1).Add(-time.Second), nil
+		}
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, v)
+}
+
+// formatBytes returns the size in a human-readable format using binary prefixes.
+func formatBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -18,6 +18,7 @@ func main() {
 	c.AddCommand(NewCmdSystem(cli.NewSystemCommander(), &config))
 	c.AddCommand(NewCmdNetwork(cli.NewNetworkCommander(), &config))
 	c.AddCommand(NewCmdGmail(cli.NewGmailCommander(), &config))
+	c.AddCommand(NewCmdMail(cli.NewMailCommander(), &config))
 	c.SetHelpCommand((&cobra.Command{
 		Use:    "no-help",
 		Hidden: true,
@@ -33,14 +34,17 @@ func NewCmdSystem(s cli.SystemCommander, c *config) *cobra.Command {
 		Long:  `System info`,
 	}
 
-	cmd.AddCommand(&cobra.Command{
+	systemUpdatesFlags := cli.SystemUpdatesFlags{}
+	updatesCmd := &cobra.Command{
 		Use:   "updates",
 		Short: "Available updates",
 		Long:  `Available updates`,
 		Run: func(cmd *cobra.Command, args []string) {
-			s.SystemUpdates(cli.NewClient(c.port))
+			s.SystemUpdates(cli.NewClient(c.port), systemUpdatesFlags)
 		},
-	})
+	}
+	updatesCmd.PersistentFlags().BoolVar(&systemUpdatesFlags.Security, "security", false, "Security updates only")
+	cmd.AddCommand(updatesCmd)
 
 	systemInstalledFlags := cli.SystemInstalledFlags{}
 	installedPackagesCmd := &cobra.Command{
@@ -53,10 +57,66 @@ func NewCmdSystem(s cli.SystemCommander, c *config) *cobra.Command {
 		},
 	}
 	installedPackagesCmd.PersistentFlags().BoolVar(&systemInstalledFlags.Subscribe, "subscribe", false, "Automatically process changes")
+	installedPackagesCmd.PersistentFlags().BoolVar(&systemInstalledFlags.Changes, "changes", false, "Automatically process added, removed and upgraded packages")
 	installedPackagesCmd.PersistentFlags().BoolVar(&systemInstalledFlags.Export, "export", false, "Creates a CSV export")
 	installedPackagesCmd.PersistentFlags().StringVar(&systemInstalledFlags.ExportPath, "exportPath", "", "CSV export path")
+	installedPackagesCmd.PersistentFlags().StringVar(&systemInstalledFlags.Sort, "sort", "name", "Sorts the packages by name or size")
 	cmd.AddCommand(installedPackagesCmd)
 
+	cmd.AddCommand(&cobra.Command{
+		Use:   "package <name>",
+		Short: "Package details",
+		Long:  `Details of an installed package`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			s.SystemPackage(cli.NewClient(c.port), args[0])
+		},
+	})
+
+	systemHistoryFlags := cli.SystemHistoryFlags{}
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "Package history",
+		Long:  `Installed, upgraded, downgraded and removed packages`,
+		Run: func(cmd *cobra.Command, args []string) {
+			s.SystemHistory(cli.NewClient(c.port), systemHistoryFlags)
+		},
+	}
+	historyCmd.PersistentFlags().StringVar(&systemHistoryFlags.From, "from", "", "Start of the time range (YYYY-MM-DD or RFC3339)")
+	historyCmd.PersistentFlags().StringVar(&systemHistoryFlags.To, "to", "", "End of the time range (YYYY-MM-DD or RFC3339)")
+	historyCmd.PersistentFlags().StringVar(&systemHistoryFlags.Package, "package", "", "Package name")
+	cmd.AddCommand(historyCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "last-upgrade",
+		Short: "Last full system upgrade",
+		Long:  `Last full system upgrade`,
+		Run: func(cmd *cobra.Command, args []string) {
+			s.SystemLastUpgrade(cli.NewClient(c.port))
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "reboot-required",
+		Short: "Reboot required",
+		Long:  `Whether a reboot is required after upgrades and why`,
+		Run: func(cmd *cobra.Command, args []string) {
+			s.SystemRebootRequired(cli.NewClient(c.port))
+		},
+	})
+
+	systemMaintenanceFlags := cli.SystemMaintenanceFlags{}
+	maintenanceCmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Maintenance summary",
+		Long:  `Orphan packages, foreign packages and package cache size`,
+		Run: func(cmd *cobra.Command, args []string) {
+			s.SystemMaintenance(cli.NewClient(c.port), systemMaintenanceFlags)
+		},
+	}
+	maintenanceCmd.PersistentFlags().BoolVar(&systemMaintenanceFlags.List, "list", false, "Lists the orphan and foreign packages")
+	cmd.AddCommand(maintenanceCmd)
+
 	cmd.AddCommand(&cobra.Command{
 		Use:   "celsius",
 		Short: "Temperature celsius",
@@ -95,12 +155,64 @@ func NewCmdNetwork(s cli.NetworkCommander, c *config) *cobra.Command {
 		},
 	})
 
-	cmd.AddCommand(&cobra.Command{
+	ipFlags := cli.NetworkIPFlags{}
+	ipCmd := &cobra.Command{
 		Use:   "ip",
 		Short: "IP address",
 		Long:  `IP address`,
 		Run: func(cmd *cobra.Command, args []string) {
-			s.IP(cli.NewClient(c.port))
+			s.IP(cli.NewClient(c.port), ipFlags)
+		},
+	}
+	ipCmd.PersistentFlags().BoolVar(&ipFlags.All, "all", false, "IPv4 and IPv6 addresses and the sources that answered them")
+	ipCmd.PersistentFlags().BoolVar(&ipFlags.Details, "details", false, "Location and ISP of the IP address")
+	cmd.AddCommand(ipCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "interfaces",
+		Short: "Network interfaces",
+		Long:  `Network interfaces, default routes and DNS servers`,
+		Run: func(cmd *cobra.Command, args []string) {
+			s.Interfaces(cli.NewClient(c.port))
+		},
+	})
+
+	throughputFlags := cli.NetworkThroughputFlags{}
+	throughputCmd := &cobra.Command{
+		Use:   "throughput",
+		Short: "Network throughput",
+		Long:  `Network throughput and traffic per interface`,
+		Run: func(cmd *cobra.Command, args []string) {
+			s.Throughput(cli.NewClient(c.port), throughputFlags)
+		},
+	}
+	throughputCmd.PersistentFlags().BoolVar(&throughputFlags.Subscribe, "subscribe", false, "Automatically process changes")
+	cmd.AddCommand(throughputCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "wifi",
+		Short: "Wi-Fi link",
+		Long:  `Wi-Fi link quality: signal strength, bitrate, frequency and access point`,
+		Run: func(cmd *cobra.Command, args []string) {
+			s.WiFi(cli.NewClient(c.port))
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Connectivity",
+		Long:  `Connectivity, latency and captive portal detection`,
+		Run: func(cmd *cobra.Command, args []string) {
+			s.Status(cli.NewClient(c.port))
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "vpn",
+		Short: "VPN",
+		Long:  `Active VPN or tunnel: WireGuard, TUN and TAP interfaces`,
+		Run: func(cmd *cobra.Command, args []string) {
+			s.VPN(cli.NewClient(c.port))
 		},
 	})
 
@@ -115,21 +227,74 @@ func NewCmdGmail(s cli.GmailCommander, c *config) *cobra.Command {
 		Long:  `Gmail info`,
 	}
 
-	cmd.AddCommand(&cobra.Command{
+	gmailLoginFlags := cli.GmailLoginFlags{}
+	loginCmd := &cobra.Command{
 		Use:   "login",
 		Short: "Gmail authorization",
 		Long:  `Gmail authorization`,
 		Run: func(cmd *cobra.Command, args []string) {
-			s.Login(cli.NewClient(c.port))
+			s.Login(cli.NewClient(c.port), gmailLoginFlags)
 		},
-	})
+	}
+	loginCmd.PersistentFlags().StringVar(&gmailLoginFlags.Account, "account", "", "The Gmail account, when there are several")
+	loginCmd.PersistentFlags().BoolVar(&gmailLoginFlags.Manual, "manual", false, "Enter the authorization code instead of receiving it in the browser, for headless machines")
+	cmd.AddCommand(loginCmd)
 
-	cmd.AddCommand(&cobra.Command{
+	gmailUnreadFlags := cli.GmailUnreadFlags{}
+	unreadCmd := &cobra.Command{
+		Use:   "unread",
+		Short: "Unread mail count",
+		Long:  `Unread mail count, or the mail count of a query of the configuration`,
+		Run: func(cmd *cobra.Command, args []string) {
+			s.Unread(cli.NewClient(c.port), gmailUnreadFlags)
+		},
+	}
+	unreadCmd.PersistentFlags().StringVar(&gmailUnreadFlags.Account, "account", "", "The Gmail account")
+	unreadCmd.PersistentFlags().StringVar(&gmailUnreadFlags.Query, "query", "", "The name of the query, for example inbox")
+	cmd.AddCommand(unreadCmd)
+
+	gmailListFlags := cli.GmailListFlags{}
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Unread mail preview",
+		Long:  `Unread mails grouped by thread, newest first`,
+		Run: func(cmd *cobra.Command, args []string) {
+			s.List(cli.NewClient(c.port), gmailListFlags)
+		},
+	}
+	listCmd.PersistentFlags().StringVar(&gmailListFlags.Account, "account", "", "The mail account")
+	listCmd.PersistentFlags().IntVar(&gmailListFlags.Limit, "limit", 20, "The maximum number of mails")
+	cmd.AddCommand(listCmd)
+
+	return cmd
+}
+
+// NewCmdMail returns a cobra.Command.
+func NewCmdMail(s cli.MailCommander, c *config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mail",
+		Short: "Mail info",
+		Long:  `Mail info of all mail accounts`,
+	}
+
+	mailUnreadFlags := cli.MailUnreadFlags{}
+	unreadCmd := &cobra.Command{
 		Use:   "unread",
 		Short: "Unread mail count",
-		Long:  `Unread mail count`,
+		Long:  `Unread mail count of all accounts`,
+		Run: func(cmd *cobra.Command, args []string) {
+			s.Unread(cli.NewClient(c.port), mailUnreadFlags)
+		},
+	}
+	unreadCmd.PersistentFlags().StringVar(&mailUnreadFlags.Account, "account", "", "The mail account")
+	cmd.AddCommand(unreadCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "accounts",
+		Short: "Mail accounts",
+		Long:  `Unread mail count of every mail account`,
 		Run: func(cmd *cobra.Command, args []string) {
-			s.Unread(cli.NewClient(c.port))
+			s.Accounts(cli.NewClient(c.port))
 		},
 	})
 
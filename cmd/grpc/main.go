@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"log"
 	"os"
 	"strings"
 
@@ -13,20 +14,28 @@ import (
 const help = `
 Usage: tin-server --FLAG VALUE
 
-FLAG		DEFAULT			DESCRIPTION
-port		8717			Server port
+FLAG		DEFAULT				DESCRIPTION
+port		8717				Server port
+config		~/.config/tin/config.json	Path of the JSON configuration file
 `
 
 func main() {
-	flag.Usage = func() { fmt.Fprintf(os.Stderr, help) }
+	flag.Usage = func() { fmt.Fprint(os.Stderr, help) }
 	port := flag.Int("port", 8717, "The server port")
+	path := flag.String("config", tin.DefaultConfigPath(), "The path of the configuration file")
 	flag.Parse()
 
 	if strings.ToLower(flag.Arg(0)) == "help" {
-		fmt.Println(help)
+		fmt.Print(help)
 		return
 	}
 
-	s := grpc.NewServer(tin.DefaultConfig())
+	// The default configuration is used when the default file doesn't exist.
+	c, err := tin.LoadConfig(*path)
+	if err != nil && !(os.IsNotExist(err) && *path == tin.DefaultConfigPath()) {
+		log.Fatal(err)
+	}
+
+	s := grpc.NewServer(c)
 	s.ListenAndServe(*port)
 }
@@ -40,6 +40,80 @@ func (c *Client) AvailableUpdates() (int, error) {
 	return int(resp.GetValue()), nil
 }
 
+// PackageHistory returns a pb.PackageHistoryResponse.
+func (c *Client) PackageHistory(from time.Time, to time.Time, name string) (*pb.PackageHistoryResponse, error) {
+	request := &pb.PackageHistoryRequest{Name: name}
+	if !from.IsZero() {
+		request.From = from.Unix()
+	}
+	if !to.IsZero() {
+		request.To = to.Unix()
+	}
+
+	resp, err := c.client.PackageHistory(context.Background(), request)
+	if err != nil {
+		return &pb.PackageHistoryResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// LastFullUpgrade returns a time.Time.
+//
+// A zero time.Time means the last full upgrade is unknown.
+func (c *Client) LastFullUpgrade() (time.Time, error) {
+	resp, err := c.client.LastFullUpgrade(context.Background(), &pb.LastFullUpgradeRequest{})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if resp.GetTimestamp() == 0 {
+		return time.Time{}, nil
+	}
+
+	return time.Unix(resp.GetTimestamp(), 0), nil
+}
+
+// RebootRequired returns a pb.RebootRequiredResponse.
+func (c *Client) RebootRequired() (*pb.RebootRequiredResponse, error) {
+	resp, err := c.client.RebootRequired(context.Background(), &pb.RebootRequiredRequest{})
+	if err != nil {
+		return &pb.RebootRequiredResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// SecurityUpdates returns a integer.
+func (c *Client) SecurityUpdates() (int, error) {
+	resp, err := c.client.AvailableUpdates(context.Background(), &pb.AvailableUpdatesRequest{})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(resp.GetSecurity()), nil
+}
+
+// Maintenance returns a pb.MaintenanceResponse.
+func (c *Client) Maintenance() (*pb.MaintenanceResponse, error) {
+	resp, err := c.client.Maintenance(context.Background(), &pb.MaintenanceRequest{})
+	if err != nil {
+		return &pb.MaintenanceResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// PackageInfo returns a pb.PackageInfoResponse.
+func (c *Client) PackageInfo(name string) (*pb.PackageInfoResponse, error) {
+	resp, err := c.client.PackageInfo(context.Background(), &pb.PackageInfoRequest{Name: name})
+	if err != nil {
+		return &pb.PackageInfoResponse{}, err
+	}
+
+	return resp, nil
+}
+
 // Temperature returns a pb.TemperatureResponse.
 func (c *Client) Temperature() (*pb.TemperatureResponse, error) {
 	resp, err := c.client.Temperature(context.Background(), &pb.TemperatureRequest{})
@@ -51,8 +125,11 @@ func (c *Client) Temperature() (*pb.TemperatureResponse, error) {
 }
 
 // GmailUnread returns a integer.
-func (c *Client) GmailUnread() (int, error) {
-	response, err := c.client.GmailUnread(context.Background(), &pb.GmailUnreadRequest{})
+//
+// The account can be omitted to count all Gmail accounts, the query can be
+// omitted to count the unread mails instead of the mails of a named query.
+func (c *Client) GmailUnread(account, query string) (int, error) {
+	response, err := c.client.GmailUnread(context.Background(), &pb.GmailUnreadRequest{Account: account, Query: query})
 	if err != nil {
 		return 0, err
 	}
@@ -60,24 +137,58 @@ func (c *Client) GmailUnread() (int, error) {
 	return int(response.GetValue()), nil
 }
 
-// GmailAuthURL returns a string.
-func (c *Client) GmailAuthURL() (string, error) {
-	response, err := c.client.GmailAuthURL(context.Background(), &pb.GmailAuthURLRequest{})
+// GmailAuthURL returns the auth URL and its state.
+//
+// The account can be omitted when there is a single Gmail account, the
+// redirect URL can be omitted to use the one of the credentials file.
+func (c *Client) GmailAuthURL(account string, redirectURL string) (string, string, error) {
+	request := &pb.GmailAuthURLRequest{Account: account, RedirectURL: redirectURL}
+	response, err := c.client.GmailAuthURL(context.Background(), request)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
-	return response.GetAuthURL(), nil
+	return response.GetAuthURL(), response.GetState(), nil
 }
 
 // GmailAuthCode returns a boolean.
-func (c *Client) GmailAuthCode(code string) bool {
-	request := &pb.GmailAuthCodeRequest{AuthCode: code}
+func (c *Client) GmailAuthCode(code string, account string, state string) bool {
+	request := &pb.GmailAuthCodeRequest{AuthCode: code, Account: account, State: state}
 	_, err := c.client.GmailAuthCode(context.Background(), request)
 
 	return err == nil
 }
 
+// MailUnread returns the unread mail count of the account, or of all accounts when it's empty.
+func (c *Client) MailUnread(account string) (int, error) {
+	response, err := c.client.MailUnread(context.Background(), &pb.MailUnreadRequest{Account: account})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(response.GetValue()), nil
+}
+
+// MailAccounts returns a pb.MailAccountsResponse.
+func (c *Client) MailAccounts() (*pb.MailAccountsResponse, error) {
+	resp, err := c.client.MailAccounts(context.Background(), &pb.MailAccountsRequest{})
+	if err != nil {
+		return &pb.MailAccountsResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// UnreadMails returns a pb.UnreadMailsResponse.
+func (c *Client) UnreadMails(account string, limit int) (*pb.UnreadMailsResponse, error) {
+	resp, err := c.client.UnreadMails(context.Background(), &pb.UnreadMailsRequest{Account: account, Limit: int32(limit)})
+	if err != nil {
+		return &pb.UnreadMailsResponse{}, err
+	}
+
+	return resp, nil
+}
+
 // ESSID returns a string.
 func (c *Client) ESSID() (string, error) {
 	resp, err := c.client.ESSID(context.Background(), &pb.ESSIDRequest{})
@@ -98,6 +209,95 @@ func (c *Client) IPAddress() (string, error) {
 	return resp.Value, nil
 }
 
+// PublicIP returns a pb.IPAddressResponse.
+func (c *Client) PublicIP() (*pb.IPAddressResponse, error) {
+	resp, err := c.client.IPAddress(context.Background(), &pb.IPAddressRequest{})
+	if err != nil {
+		return &pb.IPAddressResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// Interfaces returns a pb.InterfacesResponse.
+func (c *Client) Interfaces() (*pb.InterfacesResponse, error) {
+	resp, err := c.client.Interfaces(context.Background(), &pb.InterfacesRequest{})
+	if err != nil {
+		return &pb.InterfacesResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// Routes returns a pb.RoutesResponse.
+func (c *Client) Routes() (*pb.RoutesResponse, error) {
+	resp, err := c.client.Routes(context.Background(), &pb.RoutesRequest{})
+	if err != nil {
+		return &pb.RoutesResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// Throughput returns a pb.ThroughputResponse.
+func (c *Client) Throughput() (*pb.ThroughputResponse, error) {
+	resp, err := c.client.Throughput(context.Background(), &pb.ThroughputRequest{})
+	if err != nil {
+		return &pb.ThroughputResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// ThroughputSubscribe executes the process function when it receives a message.
+func (c *Client) ThroughputSubscribe(process func(r *pb.ThroughputResponse)) error {
+	stream, err := c.client.ThroughputSubscribe(context.Background(), &pb.ThroughputRequest{})
+	if err != nil {
+		return err
+	}
+	for {
+		t, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		process(t)
+	}
+	return nil
+}
+
+// WirelessLink returns a pb.WirelessLinkResponse.
+func (c *Client) WirelessLink() (*pb.WirelessLinkResponse, error) {
+	resp, err := c.client.WirelessLink(context.Background(), &pb.WirelessLinkRequest{})
+	if err != nil {
+		return &pb.WirelessLinkResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// VPN returns a pb.VPNResponse.
+func (c *Client) VPN() (*pb.VPNResponse, error) {
+	resp, err := c.client.VPN(context.Background(), &pb.VPNRequest{})
+	if err != nil {
+		return &pb.VPNResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// Connectivity returns a pb.ConnectivityResponse.
+func (c *Client) Connectivity() (*pb.ConnectivityResponse, error) {
+	resp, err := c.client.Connectivity(context.Background(), &pb.ConnectivityRequest{})
+	if err != nil {
+		return &pb.ConnectivityResponse{}, err
+	}
+
+	return resp, nil
+}
+
 // Config returns a pb.Config.
 func (c *Client) Config() (*pb.ConfigResponse, error) {
 	resp, err := c.client.Config(context.Background(), &pb.ConfigRequest{})
@@ -109,8 +309,10 @@ func (c *Client) Config() (*pb.ConfigResponse, error) {
 }
 
 // InstalledPackages returns a pb.InstalledPackagesResponse.
-func (c *Client) InstalledPackages() (*pb.InstalledPackagesResponse, error) {
-	resp, err := c.client.InstalledPackages(context.Background(), &pb.InstalledPackagesRequest{})
+//
+// The packages are sorted by installed size when sort is size.
+func (c *Client) InstalledPackages(sort string) (*pb.InstalledPackagesResponse, error) {
+	resp, err := c.client.InstalledPackages(context.Background(), &pb.InstalledPackagesRequest{Sort: sort})
 	if err != nil {
 		return &pb.InstalledPackagesResponse{}, err
 	}
@@ -136,3 +338,22 @@ func (c *Client) InstalledPackagesSubscribe(process func(r *pb.InstalledPackages
 	}
 	return nil
 }
+
+// InstalledPackagesChanges executes the process function when it receives a message.
+func (c *Client) InstalledPackagesChanges(process func(r *pb.InstalledPackagesChangesResponse)) error {
+	stream, err := c.client.InstalledPackagesChanges(context.Background(), &pb.InstalledPackagesChangesRequest{})
+	if err != nil {
+		return err
+	}
+	for {
+		t, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		process(t)
+	}
+	return nil
+}
@@ -2,12 +2,16 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
 	"os"
+	"time"
 
 	"github.com/sjengpho/tin/mail/gmail"
+	"github.com/sjengpho/tin/mail/imap"
+	"github.com/sjengpho/tin/mail/local"
 	"github.com/sjengpho/tin/os/network"
 	"github.com/sjengpho/tin/os/packagemanager"
 	"github.com/sjengpho/tin/os/temperature"
@@ -25,8 +29,10 @@ type Server struct {
 	packageManagerService *tin.PackageManagerService
 	temperatureService    *tin.TemperatureService
 	networkService        *tin.NetworkService
+	throughputService     *tin.ThroughputService
+	connectivityService   *tin.ConnectivityService
 	mailService           *tin.MailService
-	gmail                 *gmail.Service
+	gmail                 map[string]*gmail.Service
 }
 
 // logger returns a log.Logger with the given prefix.
@@ -36,18 +42,118 @@ func logger(prefix string) *log.Logger {
 
 // NewServer creates workers and initializes and returns a grpc.Server.
 func NewServer(c tin.Config) Server {
+	if c.PackageManagerTimeout > 0 {
+		packagemanager.CommandTimeout = c.PackageManagerTimeout
+	}
+
+	providers, gmailServices := mailProviders(c)
 	server := Server{
 		config:                &c,
-		gmail:                 gmail.NewService(c.GmailCredentials, c.GmailToken),
-		mailService:           tin.NewMailService(gmail.NewService(c.GmailCredentials, c.GmailToken), logger("MailService")),
-		networkService:        tin.NewNetworkService(network.NewNameLookup(), network.NewPublicIPLookup(), logger("NetworkService")),
-		packageManagerService: tin.NewPackageManagerService(packagemanager.New(), logger("PackageManagerService")),
+		gmail:                 gmailServices,
+		mailService:           tin.NewMailService(providers, logger("MailService")),
+		networkService:        tin.NewNetworkService(network.NewNameLookup(c.ESSIDBackends), tin.NewEnrichedIPLookup(network.NewPublicIPLookup(c.PublicIPSources, c.PublicIPQuorum), ipEnricher(c)), network.NewLocalNetworkLookup(), network.NewWirelessLinkLookup(), network.NewVPNLookup(), logger("NetworkService")),
+		packageManagerService: tin.NewPackageManagerService(packagemanager.New(c.ArchSecurityFeed, c.AURHelper), packagemanager.NewHistoryReader(), packagemanager.NewRebootChecker(c.RebootCheckLibraries), logger("PackageManagerService")),
 		temperatureService:    tin.NewTemperatureService(temperature.NewReader(), logger("TemperatureService")),
+		throughputService:     tin.NewThroughputService(network.NewTrafficCounterReader(), logger("ThroughputService")),
+		connectivityService:   tin.NewConnectivityService(network.NewConnectivityChecker(c.ConnectivityTargets), logger("ConnectivityService")),
 	}
+	server.networkService.PauseWhileOffline(server.connectivityService)
+	server.mailService.PauseWhileOffline(server.connectivityService)
+	server.networkService.Watch(network.NewEventSource())
+	server.connectivityService.Watch(network.NewEventSource())
 
 	return server
 }
 
+// mailProviders returns the tin.MailProvider of every mail account of the
+// configuration by name, and the Gmail services of the Gmail accounts.
+//
+// Without accounts the Gmail account of the credentials and token is used.
+func mailProviders(c tin.Config) (map[string]tin.MailProvider, map[string]*gmail.Service) {
+	accounts := c.MailAccounts
+	if len(accounts) == 0 {
+		accounts = []tin.MailAccount{{
+			Provider:         tin.MailProviderGmail,
+			GmailCredentials: c.GmailCredentials,
+			GmailToken:       c.GmailToken,
+			GmailQuery:       c.GmailQuery,
+			GmailQueries:     c.GmailQueries,
+		}}
+	}
+
+	providers := map[string]tin.MailProvider{}
+	gmailServices := map[string]*gmail.Service{}
+	for _, a := range accounts {
+		name := a.AccountName()
+		if _, ok := providers[name]; ok {
+			log.Printf("duplicate mail account %v ignored", name)
+			continue
+		}
+
+		switch a.Provider {
+		case tin.MailProviderGmail:
+			s := gmail.NewService(a.GmailCredentials, a.GmailToken, gmail.Queries{
+				Unread:   a.GmailQuery,
+				Counters: a.GmailQueries,
+			})
+			providers[name] = s
+			gmailServices[name] = s
+		case tin.MailProviderIMAP:
+			account := imap.Account{
+				Address:   a.IMAPAddress,
+				Security:  a.IMAPSecurity,
+				Username:  a.IMAPUsername,
+				Password:  a.IMAPPassword,
+				Mailboxes: a.IMAPMailboxes,
+			}
+			if a.IMAPToken != "" {
+				ts, err := imap.NewTokenSource(a.IMAPCredentials, a.IMAPToken)
+				if err != nil {
+					log.Printf("mail account %v ignored: %v", name, err)
+					continue
+				}
+				account.TokenSource = ts
+			}
+			providers[name] = imap.NewService(account)
+		case tin.MailProviderLocal:
+			providers[name] = local.NewService(a.LocalMail)
+		default:
+			log.Printf("mail account %v has unknown provider %q", name, a.Provider)
+		}
+	}
+	return providers, gmailServices
+}
+
+// gmailService returns the gmail.Service of the account.
+//
+// The name can be omitted when there is a single Gmail account.
+func (s *Server) gmailService(name string) (*gmail.Service, error) {
+	if name == "" && len(s.gmail) == 1 {
+		for _, v := range s.gmail {
+			return v, nil
+		}
+	}
+	if name == "" {
+		return nil, errors.New("gmail account required")
+	}
+
+	v, ok := s.gmail[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", tin.ErrUnknownMailAccount, name)
+	}
+	return v, nil
+}
+
+// ipEnricher returns the tin.IPEnricher of the configuration.
+//
+// The databases are preferred over the endpoint, they don't send the IP address elsewhere.
+func ipEnricher(c tin.Config) tin.IPEnricher {
+	if len(c.GeoIPDatabases) > 0 {
+		return network.NewMMDBEnricher(c.GeoIPDatabases)
+	}
+	return network.NewHTTPEnricher(c.GeoIPEndpoint)
+}
+
 // ListenAndServe starts the server.
 func (s *Server) ListenAndServe(port int) error {
 	address := fmt.Sprintf("0.0.0.0:%v", port)
@@ -67,17 +173,27 @@ func (s *Server) ListenAndServe(port int) error {
 
 // GmailAuthURL returns a pb.GmailAuthURLResponse.
 func (s *Server) GmailAuthURL(c context.Context, r *pb.GmailAuthURLRequest) (*pb.GmailAuthURLResponse, error) {
-	authURL, err := s.gmail.AuthURL()
+	service, err := s.gmailService(r.GetAccount())
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, state, err := service.AuthURL(r.GetRedirectURL())
 	if err != nil {
 		return nil, err
 	}
 
-	return &pb.GmailAuthURLResponse{AuthURL: authURL}, nil
+	return &pb.GmailAuthURLResponse{AuthURL: authURL, State: state}, nil
 }
 
 // GmailAuthCode returns a pb.GmailAuthCodeResponse.
 func (s *Server) GmailAuthCode(c context.Context, r *pb.GmailAuthCodeRequest) (*pb.GmailAuthCodeResponse, error) {
-	err := s.gmail.ExchangeAuthCode(r.GetAuthCode())
+	service, err := s.gmailService(r.GetAccount())
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.ExchangeAuthCode(r.GetAuthCode(), r.GetState())
 	if err != nil {
 		return nil, err
 	}
@@ -86,15 +202,195 @@ func (s *Server) GmailAuthCode(c context.Context, r *pb.GmailAuthCodeRequest) (*
 }
 
 // GmailUnread returns a pb.GmailUnreadResponse.
+//
+// The value is the unread mail count of the Gmail accounts, or of the account
+// when it is set. When the query is set the value is the count of the named
+// query instead.
 func (s *Server) GmailUnread(c context.Context, r *pb.GmailUnreadRequest) (*pb.GmailUnreadResponse, error) {
-	m := s.mailService.UnreadMailCount()
+	account := r.GetAccount()
+	if account != "" {
+		if _, err := s.gmailService(account); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.GetQuery() != "" {
+		m, err := s.mailService.QueryCount(account, r.GetQuery())
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", err, r.GetQuery())
+		}
+		return &pb.GmailUnreadResponse{Value: int32(m)}, nil
+	}
+
+	m := tin.MailCount(0)
+	for name := range s.gmail {
+		if account != "" && name != account {
+			continue
+		}
+		if a, err := s.mailService.MailAccount(name); err == nil {
+			m += a.Unread
+		}
+	}
 	return &pb.GmailUnreadResponse{Value: int32(m)}, nil
 }
 
+// MailUnread returns a pb.MailUnreadResponse.
+//
+// The value is the unread mail count of the account, or of all accounts when it's omitted.
+func (s *Server) MailUnread(c context.Context, r *pb.MailUnreadRequest) (*pb.MailUnreadResponse, error) {
+	if r.GetAccount() == "" {
+		return &pb.MailUnreadResponse{Value: int32(s.mailService.UnreadMailCount())}, nil
+	}
+
+	a, err := s.mailService.MailAccount(r.GetAccount())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", err, r.GetAccount())
+	}
+	return &pb.MailUnreadResponse{Value: int32(a.Unread)}, nil
+}
+
+// MailAccounts returns a pb.MailAccountsResponse.
+func (s *Server) MailAccounts(c context.Context, r *pb.MailAccountsRequest) (*pb.MailAccountsResponse, error) {
+	accounts := s.mailService.MailAccounts()
+	resp := &pb.MailAccountsResponse{Accounts: []*pb.MailAccount{}, Total: int32(accounts.Total())}
+	for _, a := range accounts {
+		resp.Accounts = append(resp.Accounts, &pb.MailAccount{
+			Name:   a.Name,
+			Unread: int32(a.Unread),
+			Error:  a.Err,
+		})
+	}
+	return resp, nil
+}
+
+// UnreadMails returns a pb.UnreadMailsResponse.
+//
+// The mails are of the account, or of all accounts when it's omitted, newest first.
+func (s *Server) UnreadMails(c context.Context, r *pb.UnreadMailsRequest) (*pb.UnreadMailsResponse, error) {
+	mails, err := s.mailService.UnreadMails(r.GetAccount(), int(r.GetLimit()))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", err, r.GetAccount())
+	}
+
+	resp := &pb.UnreadMailsResponse{Mails: []*pb.Mail{}}
+	for _, m := range mails {
+		mail := &pb.Mail{
+			Account:  m.Account,
+			Id:       m.ID,
+			ThreadId: m.ThreadID,
+			From:     m.From,
+			Subject:  m.Subject,
+			Labels:   m.Labels,
+			Snippet:  m.Snippet,
+		}
+		if !m.Date.IsZero() {
+			mail.Date = m.Date.Unix()
+		}
+		resp.Mails = append(resp.Mails, mail)
+	}
+	return resp, nil
+}
+
 // AvailableUpdates returns a pb.AvailableUpdatesResponse.
 func (s *Server) AvailableUpdates(c context.Context, r *pb.AvailableUpdatesRequest) (*pb.AvailableUpdatesResponse, error) {
 	u := s.packageManagerService.AvailableUpdatesCount()
-	return &pb.AvailableUpdatesResponse{Value: int32(u)}, nil
+	classified := s.packageManagerService.ClassifiedUpdates()
+	return &pb.AvailableUpdatesResponse{
+		Value:       int32(u),
+		Security:    int32(s.packageManagerService.SecurityUpdatesCount()),
+		Bugfix:      int32(classified.Count(tin.UpdateBugfix)),
+		Enhancement: int32(classified.Count(tin.UpdateEnhancement)),
+	}, nil
+}
+
+// PackageHistory returns a pb.PackageHistoryResponse.
+//
+// A zero from or to means the range is unbounded on that side.
+func (s *Server) PackageHistory(c context.Context, r *pb.PackageHistoryRequest) (*pb.PackageHistoryResponse, error) {
+	var from, to time.Time
+	if r.GetFrom() != 0 {
+		from = time.Unix(r.GetFrom(), 0)
+	}
+	if r.GetTo() != 0 {
+		to = time.Unix(r.GetTo(), 0)
+	}
+
+	history, err := s.packageManagerService.History(from, to, r.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	events := []*pb.PackageEvent{}
+	for _, e := range history {
+		events = append(events, &pb.PackageEvent{
+			Timestamp:   e.Time.Unix(),
+			Action:      string(e.Action),
+			Name:        e.Name,
+			FromVersion: e.From,
+			ToVersion:   e.To,
+		})
+	}
+
+	return &pb.PackageHistoryResponse{Events: events}, nil
+}
+
+// LastFullUpgrade returns a pb.LastFullUpgradeResponse.
+//
+// The timestamp is zero when the last full upgrade is unknown.
+func (s *Server) LastFullUpgrade(c context.Context, r *pb.LastFullUpgradeRequest) (*pb.LastFullUpgradeResponse, error) {
+	u := s.packageManagerService.LastFullUpgrade()
+	if u.Time.IsZero() {
+		return &pb.LastFullUpgradeResponse{}, nil
+	}
+
+	return &pb.LastFullUpgradeResponse{Timestamp: u.Time.Unix()}, nil
+}
+
+// RebootRequired returns a pb.RebootRequiredResponse.
+func (s *Server) RebootRequired(c context.Context, r *pb.RebootRequiredRequest) (*pb.RebootRequiredResponse, error) {
+	v := s.packageManagerService.RebootRequired()
+	return &pb.RebootRequiredResponse{Required: v.Required, Reasons: v.Reasons}, nil
+}
+
+// Maintenance returns a pb.MaintenanceResponse.
+func (s *Server) Maintenance(c context.Context, r *pb.MaintenanceRequest) (*pb.MaintenanceResponse, error) {
+	m := s.packageManagerService.Maintenance()
+	resp := &pb.MaintenanceResponse{
+		Orphans:   []*pb.Package{},
+		Foreign:   []*pb.Package{},
+		CacheSize: m.CacheSize,
+	}
+	for _, p := range m.Orphans {
+		resp.Orphans = append(resp.Orphans, &pb.Package{Name: p.Name, Version: p.Version})
+	}
+	for _, p := range m.Foreign {
+		resp.Foreign = append(resp.Foreign, &pb.Package{Name: p.Name, Version: p.Version})
+	}
+
+	return resp, nil
+}
+
+// PackageInfo returns a pb.PackageInfoResponse.
+func (s *Server) PackageInfo(c context.Context, r *pb.PackageInfoRequest) (*pb.PackageInfoResponse, error) {
+	d, err := s.packageManagerService.PackageDetails(r.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	details := &pb.PackageDetails{
+		Name:          d.Name,
+		Version:       d.Version,
+		Description:   d.Description,
+		InstalledSize: d.InstalledSize,
+		Explicit:      d.Explicit,
+		Dependencies:  d.Dependencies,
+		RequiredBy:    d.RequiredBy,
+	}
+	if !d.InstallDate.IsZero() {
+		details.InstallDate = d.InstallDate.Unix()
+	}
+
+	return &pb.PackageInfoResponse{Details: details}, nil
 }
 
 // Temperature returns a pb.TemperatureResponse.
@@ -115,8 +411,147 @@ func (s *Server) ESSID(c context.Context, r *pb.ESSIDRequest) (*pb.ESSIDResponse
 
 // IPAddress returns a pb.IPAddressResponse.
 func (s *Server) IPAddress(c context.Context, r *pb.IPAddressRequest) (*pb.IPAddressResponse, error) {
-	v := s.networkService.IP()
-	return &pb.IPAddressResponse{Value: v}, nil
+	ip := s.networkService.PublicIP()
+	resp := &pb.IPAddressResponse{
+		Value:       s.networkService.IP(),
+		Ipv4Sources: ip.IPv4Sources,
+		Ipv6Sources: ip.IPv6Sources,
+		Details: &pb.IPDetails{
+			Country:     ip.Details.Country,
+			CountryCode: ip.Details.CountryCode,
+			City:        ip.Details.City,
+			Asn:         uint32(ip.Details.ASN),
+			Isp:         ip.Details.ISP,
+		},
+	}
+	if ip.IPv4 != nil {
+		resp.Ipv4 = ip.IPv4.String()
+	}
+	if ip.IPv6 != nil {
+		resp.Ipv6 = ip.IPv6.String()
+	}
+	return resp, nil
+}
+
+// Interfaces returns a pb.InterfacesResponse.
+func (s *Server) Interfaces(c context.Context, r *pb.InterfacesRequest) (*pb.InterfacesResponse, error) {
+	interfaces := []*pb.NetworkInterface{}
+	for _, i := range s.networkService.LocalNetwork().Interfaces {
+		interfaces = append(interfaces, &pb.NetworkInterface{
+			Name:      i.Name,
+			State:     i.State,
+			Mac:       i.MAC,
+			Mtu:       int32(i.MTU),
+			Addresses: i.Addresses,
+		})
+	}
+	return &pb.InterfacesResponse{Interfaces: interfaces}, nil
+}
+
+// Routes returns a pb.RoutesResponse.
+func (s *Server) Routes(c context.Context, r *pb.RoutesRequest) (*pb.RoutesResponse, error) {
+	n := s.networkService.LocalNetwork()
+	routes := []*pb.Route{}
+	for _, r := range n.Routes {
+		routes = append(routes, &pb.Route{
+			Destination:  r.Destination,
+			Gateway:      r.Gateway,
+			Interface:    r.Interface,
+			Metric:       int32(r.Metric),
+			DefaultRoute: r.Default(),
+		})
+	}
+	return &pb.RoutesResponse{Routes: routes, DnsServers: n.DNSServers}, nil
+}
+
+// Throughput returns a pb.ThroughputResponse.
+func (s *Server) Throughput(c context.Context, r *pb.ThroughputRequest) (*pb.ThroughputResponse, error) {
+	return throughputResponse(s.throughputService.Throughput()), nil
+}
+
+// ThroughputSubscribe returns a stream of pb.ThroughputResponse.
+func (s *Server) ThroughputSubscribe(r *pb.ThroughputRequest, stream pb.TinService_ThroughputSubscribeServer) error {
+	subscription := s.throughputService.Subscribe()
+	for v := range subscription.Channel {
+		if t, ok := v.(tin.Throughput); ok {
+			if err := stream.Send(throughputResponse(t)); err != nil {
+				subscription.Close()
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// throughputResponse returns the tin.Throughput as pb.ThroughputResponse.
+func throughputResponse(t tin.Throughput) *pb.ThroughputResponse {
+	interfaces := []*pb.InterfaceThroughput{}
+	for _, i := range t {
+		interfaces = append(interfaces, &pb.InterfaceThroughput{
+			Interface: i.Interface,
+			RxRate:    i.RxRate,
+			TxRate:    i.TxRate,
+			RxTotal:   i.RxTotal,
+			TxTotal:   i.TxTotal,
+			RxSession: i.RxSession,
+			TxSession: i.TxSession,
+		})
+	}
+	return &pb.ThroughputResponse{Interfaces: interfaces}
+}
+
+// WirelessLink returns a pb.WirelessLinkResponse.
+func (s *Server) WirelessLink(c context.Context, r *pb.WirelessLinkRequest) (*pb.WirelessLinkResponse, error) {
+	w := s.networkService.WirelessLink()
+	link := &pb.WirelessLink{
+		Interface: w.Interface,
+		Ssid:      w.SSID,
+		Bssid:     w.BSSID,
+		Frequency: int32(w.Frequency),
+		Channel:   int32(w.Channel()),
+		Signal:    int32(w.Signal),
+		Quality:   int32(w.Quality),
+		TxBitrate: w.TxBitrate,
+		Connected: w.Connected(),
+	}
+	if !w.ConnectedSince.IsZero() {
+		link.ConnectedSince = w.ConnectedSince.Unix()
+	}
+	return &pb.WirelessLinkResponse{Link: link}, nil
+}
+
+// VPN returns a pb.VPNResponse.
+func (s *Server) VPN(c context.Context, r *pb.VPNRequest) (*pb.VPNResponse, error) {
+	v := s.networkService.VPN()
+	vpn := &pb.VPN{
+		Active:       v.Active(),
+		Name:         v.Name,
+		Interface:    v.Interface,
+		Endpoint:     v.Endpoint,
+		DefaultRoute: v.DefaultRoute,
+	}
+	if !v.Since.IsZero() {
+		vpn.Since = v.Since.Unix()
+	}
+	if !v.LatestHandshake.IsZero() {
+		vpn.LatestHandshake = v.LatestHandshake.Unix()
+	}
+	return &pb.VPNResponse{Vpn: vpn}, nil
+}
+
+// Connectivity returns a pb.ConnectivityResponse.
+func (s *Server) Connectivity(c context.Context, r *pb.ConnectivityRequest) (*pb.ConnectivityResponse, error) {
+	n := s.connectivityService.Connectivity()
+	probes := []*pb.Probe{}
+	for _, p := range n.Probes {
+		probes = append(probes, &pb.Probe{
+			Target:       p.Target,
+			Success:      p.Success,
+			Latency:      p.Latency.Milliseconds(),
+			SuccessRatio: p.SuccessRatio,
+		})
+	}
+	return &pb.ConnectivityResponse{Status: string(n.Status), Probes: probes}, nil
 }
 
 // Config returns a pb.Config.
@@ -131,12 +566,32 @@ func (s *Server) Config(c context.Context, r *pb.ConfigRequest) (*pb.ConfigRespo
 }
 
 // InstalledPackages returns a pb.InstalledInstalledPackagesResponse.
+//
+// The packages are sorted by installed size, largest first, when sort is size.
 func (s *Server) InstalledPackages(c context.Context, r *pb.InstalledPackagesRequest) (*pb.InstalledPackagesResponse, error) {
 	packages := []*pb.Package{}
+	if r.GetSort() == "size" {
+		details, err := s.packageManagerService.InstalledBySize()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range details {
+			packages = append(packages, &pb.Package{
+				Name:          d.Name,
+				Version:       d.Version,
+				InstalledSize: d.InstalledSize,
+			})
+		}
+
+		return &pb.InstalledPackagesResponse{Packages: packages}, nil
+	}
+
 	for _, p := range s.packageManagerService.Installed() {
 		packages = append(packages, &pb.Package{
 			Name:    p.Name,
 			Version: p.Version,
+			Source:  p.Source,
 		})
 	}
 
@@ -153,6 +608,7 @@ func (s *Server) InstalledPackagesSubscribe(r *pb.InstalledPackagesRequest, stre
 				packages = append(packages, &pb.Package{
 					Name:    p.Name,
 					Version: p.Version,
+					Source:  p.Source,
 				})
 			}
 
@@ -164,3 +620,37 @@ func (s *Server) InstalledPackagesSubscribe(r *pb.InstalledPackagesRequest, stre
 	}
 	return nil
 }
+
+// InstalledPackagesChanges returns a stream of pb.InstalledPackagesChangesResponse.
+func (s *Server) InstalledPackagesChanges(r *pb.InstalledPackagesChangesRequest, stream pb.TinService_InstalledPackagesChangesServer) error {
+	subscription := s.packageManagerService.Subscribe()
+	for v := range subscription.Channel {
+		if c, ok := v.(tin.PackageChanges); ok {
+			resp := &pb.InstalledPackagesChangesResponse{
+				Timestamp: c.Time.Unix(),
+				Added:     []*pb.Package{},
+				Removed:   []*pb.Package{},
+				Upgraded:  []*pb.PackageUpgrade{},
+			}
+			for _, p := range c.Added {
+				resp.Added = append(resp.Added, &pb.Package{Name: p.Name, Version: p.Version})
+			}
+			for _, p := range c.Removed {
+				resp.Removed = append(resp.Removed, &pb.Package{Name: p.Name, Version: p.Version})
+			}
+			for _, u := range c.Upgraded {
+				resp.Upgraded = append(resp.Upgraded, &pb.PackageUpgrade{
+					Name:        u.Name,
+					FromVersion: u.From,
+					ToVersion:   u.To,
+				})
+			}
+
+			if err := stream.Send(resp); err != nil {
+				subscription.Close()
+				return err
+			}
+		}
+	}
+	return nil
+}
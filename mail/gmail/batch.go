@@ -0,0 +1,126 @@
+package gmail
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// batchSize is the maximum number of requests of a batch request, Gmail
+// advises against larger batches because of the rate limits.
+const batchSize = 50
+
+// metadataHeaders are the headers of the messages in the metadata format.
+var metadataHeaders = []string{"From", "Subject", "Date"}
+
+// batchGetMetadata returns the messages in the metadata format.
+//
+// The messages are requested with batch requests, each of which combines
+// the requests of up to batchSize messages into one multipart/mixed HTTP
+// request. Messages of which the request failed are omitted.
+// See https://developers.google.com/gmail/api/guides/batch.
+func (s *Service) batchGetMetadata(client *http.Client, ids []string) ([]*gmail.Message, error) {
+	msgs := []*gmail.Message{}
+	for len(ids) > 0 {
+		n := len(ids)
+		if n > batchSize {
+			n = batchSize
+		}
+
+		batch, err := s.batch(client, ids[:n])
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, batch...)
+		ids = ids[n:]
+	}
+	return msgs, nil
+}
+
+// batch sends a batch request of the messages.
+func (s *Service) batch(client *http.Client, ids []string) ([]*gmail.Message, error) {
+	query := url.Values{"format": {"metadata"}, "metadataHeaders": metadataHeaders}
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	for _, id := range ids {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", "application/http")
+		header.Set("Content-ID", "<"+id+">")
+		part, err := w.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(part, "GET /gmail/v1/users/me/messages/%v?%v HTTP/1.1\r\n\r\n", url.PathEscape(id), query.Encode())
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	endpoint := strings.TrimSuffix(s.endpoint, "/") + "/batch/gmail/v1"
+	req, err := http.NewRequest(http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "multipart/mixed; boundary="+w.Boundary())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %v", resp.Status)
+	}
+
+	return parseBatchResponse(resp.Header.Get("Content-Type"), resp.Body)
+}
+
+// parseBatchResponse returns the messages of the successful responses of a batch response.
+//
+// Every part of the multipart/mixed body is a HTTP response.
+func parseBatchResponse(contentType string, body io.Reader) ([]*gmail.Message, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return nil, fmt.Errorf("unexpected content type %v", mediaType)
+	}
+
+	msgs := []*gmail.Message{}
+	r := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			return msgs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := http.ReadResponse(bufio.NewReader(part), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			msg := &gmail.Message{}
+			if err := json.NewDecoder(resp.Body).Decode(msg); err == nil {
+				msgs = append(msgs, msg)
+			}
+		}
+		resp.Body.Close()
+	}
+}
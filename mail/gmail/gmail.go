@@ -2,12 +2,20 @@ package gmail
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"sync"
+	"time"
 
+	"github.com/sjengpho/tin/mail"
 	"github.com/sjengpho/tin/tin"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
@@ -17,76 +25,264 @@ import (
 
 var readFile = ioutil.ReadFile
 
-// Service implements tin.UnreadMailCounter.
+// ErrInvalidState means the state doesn't match the one of the last auth URL.
+var ErrInvalidState = errors.New("invalid authorization state")
+
+// defaultEndpoint is the base URL of the Gmail API.
+const defaultEndpoint = "https://www.googleapis.com/"
+
+// defaultQuery is the search query of the unread mails.
+const defaultQuery = "is:unread"
+
+// detailLimit is the maximum number of the newest unread mails of which the details are fetched.
+const detailLimit = 100
+
+// Queries represents the Gmail search queries of a Service.
+type Queries struct {
+	Unread   string            // Query of the unread mails, is:unread when it is empty.
+	Counters map[string]string // Queries by name of which the mails are counted.
+}
+
+// Service implements tin.MailProvider and tin.MailQueryCounter.
 type Service struct {
 	tokenPath  string
 	configPath string
-	client     *http.Client
+	endpoint   string
+	queries    Queries
+
+	// The client of the token, it is reset when a new token is saved.
+	clientMutex sync.Mutex
+	client      *http.Client
+
+	// The unread messages, newest first, and the history ID of the mailbox
+	// they are synced to. The history isn't used when the ID is 0.
+	syncMutex    sync.Mutex
+	unread       []*gmail.Message
+	unreadLabels []string
+	historyID    uint64
+
+	// The pending authorization of the last auth URL.
+	authMutex sync.Mutex
+	auth      *authRequest
+
+	// The details of the messages by ID, a message doesn't change except for its labels.
+	mutex   sync.Mutex
+	details map[string]tin.Mail
+}
+
+// authRequest represents the state, the PKCE code verifier and the redirect
+// URL of an auth URL.
+type authRequest struct {
+	state       string
+	verifier    string
+	redirectURL string
 }
 
 // NewService returns a new Service.
-func NewService(c string, t string) *Service {
+func NewService(c string, t string, q Queries) *Service {
+	if q.Unread == "" {
+		q.Unread = defaultQuery
+	}
+
 	return &Service{
 		configPath: c,
 		tokenPath:  t,
+		endpoint:   defaultEndpoint,
+		queries:    q,
+		details:    map[string]tin.Mail{},
 	}
 }
 
 // UnreadMails fetches unread messages from Gmail.
 //
-// It assumes setting "is:unread" will filter unread mails only.
-// Because of the pagination it will keep requesting messages
-// until NextPageToken is empty.
+// The unread messages are synced incrementally with the history of the
+// mailbox, see unreadMessages.
+//
+// The details of the newest messages are fetched in batches with the
+// metadata format, and are cached. The other messages only have an ID.
 func (s *Service) UnreadMails() ([]tin.Mail, error) {
-	client, err := s.getUsersMessagesService()
-	if client == nil {
+	users, err := s.getUsersService()
+	if users == nil {
 		return []tin.Mail{}, err
 	}
 
-	resp, err := client.List("me").Q("is:unread").Do()
+	msgs, err := s.unreadMessages(users)
 	if err != nil {
 		return []tin.Mail{}, fmt.Errorf("Failed fetching unread mails: %w", err)
 	}
 
-	msgs := resp.Messages
-	for resp.NextPageToken != "" {
-		resp, err = client.List("me").Q("is:unread").PageToken(resp.NextPageToken).Do()
-		if err != nil {
-			return []tin.Mail{}, fmt.Errorf("Failed fetching unread mails: %w", err)
-		}
-		msgs = append(msgs, resp.Messages...)
+	details, err := s.messageDetails(users, msgs)
+	if err != nil {
+		return []tin.Mail{}, fmt.Errorf("Failed fetching unread mail details: %w", err)
 	}
 
 	mm := []tin.Mail{}
 	for _, msg := range msgs {
-		mm = append(mm, tin.Mail{
-			Snippet: msg.Snippet,
-		})
+		m, ok := details[msg.Id]
+		if !ok {
+			m = tin.Mail{ID: msg.Id, ThreadID: msg.ThreadId}
+		}
+		mm = append(mm, m)
 	}
 
 	return mm, nil
 }
 
-// AuthURL uses oauth2.Config to return a URL to the consent page.
-func (s *Service) AuthURL() (string, error) {
+// messageDetails returns the details of the newest messages by ID.
+//
+// The messages that aren't cached are fetched, the cache is reduced to the messages.
+func (s *Service) messageDetails(users *gmail.UsersService, msgs []*gmail.Message) (map[string]tin.Mail, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(msgs) > detailLimit {
+		msgs = msgs[:detailLimit]
+	}
+
+	missing := []string{}
+	details := map[string]tin.Mail{}
+	for _, msg := range msgs {
+		if m, ok := s.details[msg.Id]; ok {
+			details[msg.Id] = m
+		} else {
+			missing = append(missing, msg.Id)
+		}
+	}
+
+	if len(missing) > 0 {
+		labels, err := s.labelNames(users)
+		if err != nil {
+			return nil, err
+		}
+
+		client, err := s.getClient()
+		if err != nil {
+			return nil, err
+		}
+
+		fetched, err := s.batchGetMetadata(client, missing)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range fetched {
+			details[msg.Id] = newMail(msg, labels)
+		}
+	}
+
+	s.details = details
+	return details, nil
+}
+
+// labelNames returns the names of the labels by ID.
+func (s *Service) labelNames(users *gmail.UsersService) (map[string]string, error) {
+	labels, err := listLabels(users)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]string{}
+	for _, l := range labels {
+		names[l.Id] = l.Name
+	}
+	return names, nil
+}
+
+// listLabels returns the labels of the mailbox.
+func listLabels(users *gmail.UsersService) ([]*gmail.Label, error) {
+	resp, err := users.Labels.List("me").Do()
+	if err != nil {
+		return nil, err
+	}
+	return resp.Labels, nil
+}
+
+// newMail returns the tin.Mail of a message in the metadata format.
+func newMail(msg *gmail.Message, labels map[string]string) tin.Mail {
+	m := tin.Mail{
+		ID:       msg.Id,
+		ThreadID: msg.ThreadId,
+		Date:     time.Unix(0, msg.InternalDate*int64(time.Millisecond)),
+		Labels:   []string{},
+		Snippet:  msg.Snippet,
+	}
+
+	for _, id := range msg.LabelIds {
+		if name, ok := labels[id]; ok {
+			m.Labels = append(m.Labels, name)
+		} else {
+			m.Labels = append(m.Labels, id)
+		}
+	}
+
+	if msg.Payload != nil {
+		for _, h := range msg.Payload.Headers {
+			switch h.Name {
+			case "From":
+				m.From = mail.Sender(h.Value)
+			case "Subject":
+				m.Subject = h.Value
+			}
+		}
+	}
+	return m
+}
+
+// AuthURL uses oauth2.Config to return a URL to the consent page and its state.
+//
+// The redirect URL overrides the one of the credentials file when it is set,
+// for example the loopback address of a temporary listener. The state is
+// random and the authorization code is protected with PKCE, see RFC 7636.
+// Only the last auth URL can be completed with ExchangeAuthCode.
+func (s *Service) AuthURL(redirectURL string) (string, string, error) {
 	config, err := s.getConfig()
 	if err != nil {
-		return "", err
+		return "", "", err
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := randomString()
+	if err != nil {
+		return "", "", err
+	}
+
+	if redirectURL != "" {
+		config.RedirectURL = redirectURL
 	}
 
-	return config.AuthCodeURL("state-token", oauth2.AccessTypeOffline), nil
+	s.authMutex.Lock()
+	s.auth = &authRequest{state: state, verifier: verifier, redirectURL: config.RedirectURL}
+	s.authMutex.Unlock()
+
+	authURL := config.AuthCodeURL(state, oauth2.AccessTypeOffline,
+		oauth2.SetAuthURLParam("code_challenge", codeChallenge(verifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
+	return authURL, state, nil
 }
 
-// ExchangeAuthCode attempts to exchange the authorization code.
+// ExchangeAuthCode attempts to exchange the authorization code of the auth
+// URL with the state.
 //
 // The token will be stored as JSON for future uses.
-func (s *Service) ExchangeAuthCode(code string) error {
+func (s *Service) ExchangeAuthCode(code string, state string) error {
+	s.authMutex.Lock()
+	auth := s.auth
+	s.authMutex.Unlock()
+
+	if auth == nil || subtle.ConstantTimeCompare([]byte(auth.state), []byte(state)) != 1 {
+		return ErrInvalidState
+	}
+
 	config, err := s.getConfig()
 	if err != nil {
 		return err
 	}
+	config.RedirectURL = auth.redirectURL
 
-	token, err := config.Exchange(context.TODO(), code)
+	token, err := config.Exchange(context.TODO(), code, oauth2.SetAuthURLParam("code_verifier", auth.verifier))
 	if err != nil {
 		return fmt.Errorf("failed getting the token: %w", err)
 	}
@@ -95,28 +291,62 @@ func (s *Service) ExchangeAuthCode(code string) error {
 		return fmt.Errorf("failed saving the token: %w", err)
 	}
 
+	// The next request uses the new token, the account may have changed
+	// so the unread messages are synced again.
+	s.clientMutex.Lock()
+	s.client = nil
+	s.clientMutex.Unlock()
+
+	s.syncMutex.Lock()
+	s.historyID = 0
+	s.syncMutex.Unlock()
+
+	s.authMutex.Lock()
+	if s.auth == auth {
+		s.auth = nil
+	}
+	s.authMutex.Unlock()
+
 	return nil
 }
 
-// getUsersMessagesService returns gmail.UsersMessagesService.
-func (s *Service) getUsersMessagesService() (*gmail.UsersMessagesService, error) {
+// randomString returns 32 random bytes encoded as unpadded base64url.
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// codeChallenge returns the S256 code challenge of the PKCE code verifier.
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// getUsersService returns gmail.UsersService.
+func (s *Service) getUsersService() (*gmail.UsersService, error) {
 	client, err := s.getClient()
 	if err != nil {
 		return nil, err
 	}
 
-	service, err := gmail.NewService(context.Background(), option.WithHTTPClient(client))
+	service, err := gmail.NewService(context.Background(), option.WithHTTPClient(client), option.WithEndpoint(s.endpoint))
 	if err != nil {
 		return nil, err
 	}
 
-	return gmail.NewUsersMessagesService(service), nil
+	return gmail.NewUsersService(service), nil
 }
 
 // getClient creates and returns a http.Client.
 //
 // The client will be cached for future uses.
 func (s *Service) getClient() (*http.Client, error) {
+	s.clientMutex.Lock()
+	defer s.clientMutex.Unlock()
+
 	if s.client != nil {
 		return s.client, nil
 	}
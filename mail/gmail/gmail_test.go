@@ -0,0 +1,613 @@
+package gmail
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+	"google.golang.org/api/gmail/v1"
+)
+
+// testMessages are the unread messages of the fake Gmail server, newest first.
+var testMessages = []*gmail.Message{
+	{
+		Id:           "m3",
+		ThreadId:     "t1",
+		InternalDate: 1577880000000,
+		LabelIds:     []string{"INBOX", "UNREAD", "Label_1"},
+		Snippet:      "See you tomorrow",
+		Payload: &gmail.MessagePart{Headers: []*gmail.MessagePartHeader{
+			{Name: "From", Value: "Alice <alice@example.com>"},
+			{Name: "Subject", Value: "Re: Lunch"},
+		}},
+	},
+	{
+		Id:           "m2",
+		ThreadId:     "t2",
+		InternalDate: 1577876400000,
+		LabelIds:     []string{"INBOX", "UNREAD"},
+		Snippet:      "The report",
+		Payload: &gmail.MessagePart{Headers: []*gmail.MessagePartHeader{
+			{Name: "From", Value: "bob@example.com"},
+			{Name: "Subject", Value: "Report"},
+		}},
+	},
+	{
+		Id:       "m1",
+		ThreadId: "t1",
+	},
+}
+
+// testLabelsUnread are the unread counts of the labels of the fake Gmail server.
+var testLabelsUnread = map[string]int64{"INBOX": 2, "Label_2": 7}
+
+// testPrimaryQuery is a query of the fake Gmail server that isn't of a single label.
+const testPrimaryQuery = "in:inbox category:primary is:unread"
+
+// testSizeQuery is a query of the unread mails of the fake Gmail server that isn't of labels only.
+const testSizeQuery = "is:unread larger:1M"
+
+// testSpamQuery is a query of the unread mails of the fake Gmail server of an excluded label.
+const testSpamQuery = "in:spam is:unread"
+
+// testHistoryID is the history ID of the mailbox of the fake Gmail server.
+const testHistoryID = 100
+
+// testGmailServer is a fake Gmail API that serves the testMessages, the
+// messages of which the ID is in missing fail in batch requests.
+//
+// The history since testHistoryID is history, it has expired when expired is set.
+type testGmailServer struct {
+	*httptest.Server
+	batches int32
+	lists   int32
+	missing map[string]bool
+
+	mutex   sync.Mutex
+	history []*gmail.History
+	expired bool
+}
+
+func newTestGmailServer(t *testing.T) *testGmailServer {
+	s := &testGmailServer{missing: map[string]bool{"m1": true}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/gmail/v1/users/me/messages", func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query().Get("q"); q == testPrimaryQuery {
+			json.NewEncoder(w).Encode(&gmail.ListMessagesResponse{Messages: []*gmail.Message{{Id: "m2"}}})
+			return
+		} else if q != "is:unread" && q != testSizeQuery && q != testSpamQuery {
+			t.Errorf("want %v, got %v", "is:unread", q)
+		}
+		atomic.AddInt32(&s.lists, 1)
+
+		// Two pages.
+		resp := &gmail.ListMessagesResponse{}
+		for _, m := range testMessages {
+			resp.Messages = append(resp.Messages, &gmail.Message{Id: m.Id, ThreadId: m.ThreadId})
+		}
+		if r.URL.Query().Get("pageToken") == "" {
+			resp.Messages = resp.Messages[:2]
+			resp.NextPageToken = "next"
+		} else {
+			resp.Messages = resp.Messages[2:]
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("/gmail/v1/users/me/labels", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&gmail.ListLabelsResponse{Labels: []*gmail.Label{
+			{Id: "INBOX", Name: "INBOX"},
+			{Id: "SPAM", Name: "SPAM"},
+			{Id: "Label_1", Name: "Friends"},
+			{Id: "Label_2", Name: "Work/Alerts"},
+		}})
+	})
+	mux.HandleFunc("/gmail/v1/users/me/labels/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/gmail/v1/users/me/labels/")
+		json.NewEncoder(w).Encode(&gmail.Label{Id: id, MessagesUnread: testLabelsUnread[id]})
+	})
+	mux.HandleFunc("/gmail/v1/users/me/profile", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&gmail.Profile{HistoryId: testHistoryID})
+	})
+	mux.HandleFunc("/gmail/v1/users/me/history", s.historyList(t))
+	mux.HandleFunc("/batch/gmail/v1", s.batch(t))
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// historyList handles a request of the history since testHistoryID.
+func (s *testGmailServer) historyList(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		if s.expired {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"code": 404, "message": "Requested entity was not found."}}`)
+			return
+		}
+
+		if got := r.URL.Query().Get("startHistoryId"); got != fmt.Sprint(testHistoryID) {
+			t.Errorf("want %v, got %v", testHistoryID, got)
+		}
+		json.NewEncoder(w).Encode(&gmail.ListHistoryResponse{History: s.history, HistoryId: testHistoryID})
+	}
+}
+
+// setHistory sets the history of the server.
+func (s *testGmailServer) setHistory(history []*gmail.History, expired bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.history = history
+	s.expired = expired
+}
+
+// batch handles a batch request of which every part is a request of a message.
+func (s *testGmailServer) batch(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.batches, 1)
+
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body := &bytes.Buffer{}
+		mw := multipart.NewWriter(body)
+		mr := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req, err := http.ReadRequest(bufio.NewReader(part))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if req.URL.Query().Get("format") != "metadata" {
+				t.Errorf("want %v, got %v", "metadata", req.URL.Query().Get("format"))
+			}
+
+			id := strings.TrimPrefix(req.URL.Path, "/gmail/v1/users/me/messages/")
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Type", "application/http")
+			header.Set("Content-ID", "<response-"+id+">")
+			pw, _ := mw.CreatePart(header)
+
+			msg := testMessage(id)
+			if s.missing[id] || msg == nil {
+				fmt.Fprint(pw, "HTTP/1.1 404 Not Found\r\nContent-Type: application/json\r\n\r\n{}")
+				continue
+			}
+			b, _ := json.Marshal(msg)
+			fmt.Fprintf(pw, "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: %v\r\n\r\n%s", len(b), b)
+		}
+		mw.Close()
+
+		w.Header().Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+		w.Write(body.Bytes())
+	}
+}
+
+// testMessage returns the test message with the ID, or nil.
+func testMessage(id string) *gmail.Message {
+	for _, m := range testMessages {
+		if m.Id == id {
+			return m
+		}
+	}
+	return nil
+}
+
+func newTestService(s *testGmailServer) *Service {
+	service := NewService("", "", Queries{Counters: map[string]string{
+		"inbox":   "is:unread in:inbox",
+		"alerts":  "label:work-alerts is:unread",
+		"primary": testPrimaryQuery,
+	}})
+	service.client = s.Client()
+	service.endpoint = s.URL + "/"
+	return service
+}
+
+func TestUnreadMails(t *testing.T) {
+	server := newTestGmailServer(t)
+	defer server.Close()
+	s := newTestService(server)
+
+	want := []tin.Mail{
+		{
+			ID:       "m3",
+			ThreadID: "t1",
+			From:     "Alice",
+			Subject:  "Re: Lunch",
+			Date:     time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC),
+			Labels:   []string{"INBOX", "UNREAD", "Friends"},
+			Snippet:  "See you tomorrow",
+		},
+		{
+			ID:       "m2",
+			ThreadID: "t2",
+			From:     "bob@example.com",
+			Subject:  "Report",
+			Date:     time.Date(2020, 1, 1, 11, 0, 0, 0, time.UTC),
+			Labels:   []string{"INBOX", "UNREAD"},
+			Snippet:  "The report",
+		},
+		{ID: "m1", ThreadID: "t1"},
+	}
+
+	// The details are cached, except for the failed message.
+	for i := 0; i < 2; i++ {
+		got, err := s.UnreadMails()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range got {
+			got[i].Date = got[i].Date.UTC()
+		}
+		want[2].Date = time.Time{}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("want %+v, got %+v", want, got)
+		}
+	}
+
+	if got := atomic.LoadInt32(&server.batches); got != 2 {
+		t.Errorf("want %v, got %v", 2, got)
+	}
+}
+
+func TestUnreadMailsHistory(t *testing.T) {
+	server := newTestGmailServer(t)
+	defer server.Close()
+	s := newTestService(server)
+
+	ids := func() []string {
+		t.Helper()
+
+		mails, err := s.UnreadMails()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := []string{}
+		for _, m := range mails {
+			got = append(got, m.ID)
+		}
+		return got
+	}
+
+	tt := []struct {
+		history []*gmail.History
+		expired bool
+		want    []string
+		lists   int32
+	}{
+		{
+			// The initial sync lists the two pages.
+			want:  []string{"m3", "m2", "m1"},
+			lists: 2,
+		},
+		{
+			history: []*gmail.History{
+				{MessagesAdded: []*gmail.HistoryMessageAdded{{Message: &gmail.Message{Id: "m4", ThreadId: "t3", LabelIds: []string{"INBOX", "UNREAD"}}}}},
+				{MessagesAdded: []*gmail.HistoryMessageAdded{{Message: &gmail.Message{Id: "m5", ThreadId: "t4", LabelIds: []string{"SPAM", "UNREAD"}}}}},
+				{LabelsRemoved: []*gmail.HistoryLabelRemoved{{Message: &gmail.Message{Id: "m2", LabelIds: []string{"INBOX"}}, LabelIds: []string{"UNREAD"}}}},
+				{MessagesDeleted: []*gmail.HistoryMessageDeleted{{Message: &gmail.Message{Id: "m1"}}}},
+			},
+			want:  []string{"m4", "m3"},
+			lists: 2,
+		},
+		{
+			history: []*gmail.History{
+				{LabelsAdded: []*gmail.HistoryLabelAdded{{Message: &gmail.Message{Id: "m2", LabelIds: []string{"INBOX", "UNREAD"}}, LabelIds: []string{"UNREAD"}}}},
+				{LabelsAdded: []*gmail.HistoryLabelAdded{{Message: &gmail.Message{Id: "m3", LabelIds: []string{"UNREAD", "TRASH"}}, LabelIds: []string{"TRASH"}}}},
+			},
+			want:  []string{"m2", "m4"},
+			lists: 2,
+		},
+		{
+			// A full sync when the history has expired.
+			expired: true,
+			want:    []string{"m3", "m2", "m1"},
+			lists:   4,
+		},
+	}
+
+	for _, tc := range tt {
+		server.setHistory(tc.history, tc.expired)
+
+		if got := ids(); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("want %v, got %v", tc.want, got)
+		}
+		if got := atomic.LoadInt32(&server.lists); got != tc.lists {
+			t.Errorf("want %v, got %v", tc.lists, got)
+		}
+	}
+}
+
+func TestUnreadMailsHistorySpam(t *testing.T) {
+	server := newTestGmailServer(t)
+	defer server.Close()
+	s := newTestService(server)
+
+	s.queries.Unread = testSpamQuery
+	if _, err := s.UnreadMails(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The query names the excluded label, so spam is added and other mail removed.
+	server.setHistory([]*gmail.History{
+		{MessagesAdded: []*gmail.HistoryMessageAdded{{Message: &gmail.Message{Id: "m5", ThreadId: "t4", LabelIds: []string{"SPAM", "UNREAD"}}}}},
+		{LabelsRemoved: []*gmail.HistoryLabelRemoved{{Message: &gmail.Message{Id: "m3", LabelIds: []string{"INBOX", "UNREAD"}}, LabelIds: []string{"SPAM"}}}},
+	}, false)
+
+	mails, err := s.UnreadMails()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := []string{}
+	for _, m := range mails {
+		got = append(got, m.ID)
+	}
+	want := []string{"m5", "m2", "m1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	if got := atomic.LoadInt32(&server.lists); got != 2 {
+		t.Errorf("want %v, got %v", 2, got)
+	}
+}
+
+func TestUnreadMailsWithoutHistory(t *testing.T) {
+	server := newTestGmailServer(t)
+	defer server.Close()
+	s := newTestService(server)
+
+	s.queries.Unread = testSizeQuery
+
+	// A query that can't be evaluated with labels is listed every time.
+	for i := 0; i < 2; i++ {
+		if _, err := s.UnreadMails(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&server.lists); got != 4 {
+		t.Errorf("want %v, got %v", 4, got)
+	}
+}
+
+func TestQueryCounts(t *testing.T) {
+	server := newTestGmailServer(t)
+	defer server.Close()
+
+	got, err := newTestService(server).QueryCounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]tin.MailCount{"inbox": 2, "alerts": 7, "primary": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestUnreadLabel(t *testing.T) {
+	labels := []*gmail.Label{
+		{Id: "INBOX", Name: "INBOX"},
+		{Id: "Label_1", Name: "Work/Alerts"},
+		{Id: "Label_2", Name: "My Label"},
+	}
+
+	tt := []struct {
+		q    string
+		want string
+	}{
+		{q: "is:unread in:inbox", want: "INBOX"},
+		{q: "label:work-alerts is:unread", want: "Label_1"},
+		{q: "label:Work/Alerts is:unread", want: "Label_1"},
+		{q: "is:unread label:my-label", want: "Label_2"},
+		{q: "is:unread category:primary", want: "CATEGORY_PERSONAL"},
+		{q: "in:inbox category:primary is:unread", want: ""},
+		{q: "label:missing is:unread", want: ""},
+		{q: "label:work-alerts", want: ""},
+		{q: "is:unread from:alice", want: ""},
+	}
+
+	for _, tc := range tt {
+		got, _ := unreadLabel(tc.q, labels)
+		if got != tc.want {
+			t.Errorf("%v: want %v, got %v", tc.q, tc.want, got)
+		}
+	}
+}
+
+func TestBatchGetMetadata(t *testing.T) {
+	server := newTestGmailServer(t)
+	defer server.Close()
+	server.missing = map[string]bool{}
+	s := newTestService(server)
+
+	ids := []string{}
+	for i := 0; i < batchSize+1; i++ {
+		ids = append(ids, "m2")
+	}
+
+	got, err := s.batchGetMetadata(server.Client(), ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != batchSize+1 {
+		t.Errorf("want %v, got %v", batchSize+1, len(got))
+	}
+	if n := atomic.LoadInt32(&server.batches); n != 2 {
+		t.Errorf("want %v, got %v", 2, n)
+	}
+}
+
+func TestParseBatchResponseError(t *testing.T) {
+	tt := []string{
+		"application/json",
+		"invalid",
+	}
+
+	for _, contentType := range tt {
+		_, err := parseBatchResponse(contentType, strings.NewReader(""))
+		if err == nil {
+			t.Errorf("want error, got nil")
+		}
+	}
+}
+
+// tempCredentials creates a directory with a credentials file of which the
+// token endpoint is the URL.
+func tempCredentials(t *testing.T, tokenURL string) string {
+	dir, err := ioutil.TempDir("", "gmail")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credentials := fmt.Sprintf(`{"installed": {
+		"client_id": "id",
+		"client_secret": "secret",
+		"auth_uri": "https://accounts.google.com/o/oauth2/auth",
+		"token_uri": %q,
+		"redirect_uris": ["urn:ietf:wg:oauth:2.0:oob", "http://localhost"]
+	}}`, tokenURL)
+	if err := ioutil.WriteFile(filepath.Join(dir, "credentials.json"), []byte(credentials), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestAuthURL(t *testing.T) {
+	dir := tempCredentials(t, "http://127.0.0.1/token")
+	defer os.RemoveAll(dir)
+	s := NewService(filepath.Join(dir, "credentials.json"), filepath.Join(dir, "token.json"), Queries{})
+
+	tt := []struct {
+		redirectURL string
+		want        string
+	}{
+		{redirectURL: "http://127.0.0.1:8080/", want: "http://127.0.0.1:8080/"},
+		{redirectURL: "", want: "urn:ietf:wg:oauth:2.0:oob"},
+	}
+
+	states := map[string]bool{}
+	for _, tc := range tt {
+		authURL, state, err := s.AuthURL(tc.redirectURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		u, err := url.Parse(authURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := u.Query()
+
+		if got := q.Get("redirect_uri"); got != tc.want {
+			t.Errorf("want %v, got %v", tc.want, got)
+		}
+		if got := q.Get("state"); got != state || state == "" || states[state] {
+			t.Errorf("want new state %v, got %v", state, got)
+		}
+		states[state] = true
+		if got := q.Get("code_challenge_method"); got != "S256" {
+			t.Errorf("want %v, got %v", "S256", got)
+		}
+		if got := q.Get("code_challenge"); got != codeChallenge(s.auth.verifier) {
+			t.Errorf("want %v, got %v", codeChallenge(s.auth.verifier), got)
+		}
+	}
+}
+
+func TestExchangeAuthCode(t *testing.T) {
+	var challenge string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if got := r.PostForm.Get("code"); got != "code" {
+			t.Errorf("want %v, got %v", "code", got)
+		}
+		if got := r.PostForm.Get("redirect_uri"); got != "http://127.0.0.1:8080/" {
+			t.Errorf("want %v, got %v", "http://127.0.0.1:8080/", got)
+		}
+		if got := codeChallenge(r.PostForm.Get("code_verifier")); got != challenge {
+			t.Errorf("want %v, got %v", challenge, got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token": "token", "token_type": "Bearer", "refresh_token": "refresh"}`)
+	}))
+	defer server.Close()
+
+	dir := tempCredentials(t, server.URL)
+	defer os.RemoveAll(dir)
+	s := NewService(filepath.Join(dir, "credentials.json"), filepath.Join(dir, "token.json"), Queries{})
+
+	if err := s.ExchangeAuthCode("code", ""); err != ErrInvalidState {
+		t.Errorf("want %v, got %v", ErrInvalidState, err)
+	}
+
+	authURL, state, err := s.AuthURL("http://127.0.0.1:8080/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(authURL)
+	challenge = u.Query().Get("code_challenge")
+
+	s.client = server.Client()
+	if err := s.ExchangeAuthCode("code", "other"); err != ErrInvalidState {
+		t.Errorf("want %v, got %v", ErrInvalidState, err)
+	}
+	if err := s.ExchangeAuthCode("code", state); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := s.getToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.RefreshToken != "refresh" {
+		t.Errorf("want %v, got %v", "refresh", token.RefreshToken)
+	}
+
+	// The client of the previous token isn't used anymore.
+	if s.client != nil {
+		t.Errorf("want %v, got %v", nil, s.client)
+	}
+
+	// The state can't be used again.
+	if err := s.ExchangeAuthCode("code", state); err != ErrInvalidState {
+		t.Errorf("want %v, got %v", ErrInvalidState, err)
+	}
+}
@@ -0,0 +1,189 @@
+package gmail
+
+import (
+	"errors"
+	"net/http"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// historyTypes are the types of the changes of the history that affect the unread messages.
+var historyTypes = []string{"messageAdded", "messageDeleted", "labelAdded", "labelRemoved"}
+
+// excludedLabels are the labels of the messages that searches exclude, unless
+// the query names them, for example in:spam.
+var excludedLabels = []string{"SPAM", "TRASH"}
+
+// unreadMessages returns the unread messages, newest first.
+//
+// The first call lists the messages of the unread query and stores the
+// history ID of the mailbox, later calls apply the changes since then.
+// The messages are listed again when the history expired, or every call
+// when the query can't be evaluated with the labels of a message.
+// See https://developers.google.com/gmail/api/guides/sync.
+func (s *Service) unreadMessages(users *gmail.UsersService) ([]*gmail.Message, error) {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+
+	if s.historyID != 0 {
+		err := s.partialSync(users)
+		if err == nil {
+			return append([]*gmail.Message{}, s.unread...), nil
+		}
+		if !historyExpired(err) {
+			return nil, err
+		}
+	}
+
+	if err := s.fullSync(users); err != nil {
+		return nil, err
+	}
+	return append([]*gmail.Message{}, s.unread...), nil
+}
+
+// fullSync lists the unread messages.
+//
+// The history ID is requested before the messages are listed, so changes
+// during the listing are applied by the next partial sync.
+func (s *Service) fullSync(users *gmail.UsersService) error {
+	s.historyID = 0
+
+	profile, err := users.GetProfile("me").Do()
+	if err != nil {
+		return err
+	}
+
+	labels, err := listLabels(users)
+	if err != nil {
+		return err
+	}
+
+	msgs := []*gmail.Message{}
+	token := ""
+	for {
+		call := users.Messages.List("me").Q(s.queries.Unread)
+		if token != "" {
+			call = call.PageToken(token)
+		}
+
+		resp, err := call.Do()
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, resp.Messages...)
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		token = resp.NextPageToken
+	}
+
+	s.unread = msgs
+	if ids, ok := queryLabels(s.queries.Unread, labels); ok {
+		s.unreadLabels = ids
+		s.historyID = profile.HistoryId
+	}
+	return nil
+}
+
+// partialSync applies the changes of the history since the history ID.
+func (s *Service) partialSync(users *gmail.UsersService) error {
+	token := ""
+	for {
+		call := users.History.List("me").StartHistoryId(s.historyID).HistoryTypes(historyTypes...)
+		if token != "" {
+			call = call.PageToken(token)
+		}
+
+		resp, err := call.Do()
+		if err != nil {
+			return err
+		}
+
+		for _, h := range resp.History {
+			for _, v := range h.MessagesAdded {
+				s.applyChange(v.Message)
+			}
+			for _, v := range h.LabelsAdded {
+				s.applyChange(v.Message)
+			}
+			for _, v := range h.LabelsRemoved {
+				s.applyChange(v.Message)
+			}
+			for _, v := range h.MessagesDeleted {
+				if v.Message != nil {
+					s.removeUnread(v.Message.Id)
+				}
+			}
+		}
+
+		if resp.NextPageToken == "" {
+			s.historyID = resp.HistoryId
+			return nil
+		}
+		token = resp.NextPageToken
+	}
+}
+
+// applyChange adds or removes the message of a change depending on whether
+// its labels match the unread query.
+//
+// The message of a change has the labels of the message after the change.
+func (s *Service) applyChange(msg *gmail.Message) {
+	if msg == nil {
+		return
+	}
+
+	if !s.matchesUnread(msg.LabelIds) {
+		s.removeUnread(msg.Id)
+		return
+	}
+
+	for _, m := range s.unread {
+		if m.Id == msg.Id {
+			return
+		}
+	}
+	s.unread = append([]*gmail.Message{{Id: msg.Id, ThreadId: msg.ThreadId}}, s.unread...)
+}
+
+// removeUnread removes the message from the unread messages.
+func (s *Service) removeUnread(id string) {
+	for i, m := range s.unread {
+		if m.Id == id {
+			s.unread = append(s.unread[:i], s.unread[i+1:]...)
+			return
+		}
+	}
+}
+
+// matchesUnread reports whether a message with the labels matches the unread query.
+func (s *Service) matchesUnread(labelIDs []string) bool {
+	has := map[string]bool{}
+	for _, id := range labelIDs {
+		has[id] = true
+	}
+
+	queried := map[string]bool{}
+	for _, id := range s.unreadLabels {
+		if !has[id] {
+			return false
+		}
+		queried[id] = true
+	}
+
+	for _, id := range excludedLabels {
+		if has[id] && !queried[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// historyExpired reports whether the error means the history ID is too old,
+// Gmail keeps the history for about a week.
+func historyExpired(err error) bool {
+	var e *googleapi.Error
+	return errors.As(err, &e) && e.Code == http.StatusNotFound
+}
@@ -0,0 +1,162 @@
+package gmail
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sjengpho/tin/tin"
+	"google.golang.org/api/gmail/v1"
+)
+
+// maxResults is the maximum number of messages of a page when counting the
+// messages of a query, the IDs are the only fields that are requested.
+const maxResults = 500
+
+// stateLabels are the IDs of the system labels of the "is:" search terms.
+var stateLabels = map[string]string{
+	"unread":    "UNREAD",
+	"starred":   "STARRED",
+	"important": "IMPORTANT",
+}
+
+// categoryLabels are the IDs of the labels of the inbox categories by search name.
+var categoryLabels = map[string]string{
+	"primary":    "CATEGORY_PERSONAL",
+	"social":     "CATEGORY_SOCIAL",
+	"promotions": "CATEGORY_PROMOTIONS",
+	"updates":    "CATEGORY_UPDATES",
+	"forums":     "CATEGORY_FORUMS",
+}
+
+// QueryCounts returns the counts of the mails of the counter queries by name.
+//
+// A query of the unread mails of a single label, like "label:alerts is:unread",
+// is counted with the messagesUnread of the label. The other queries are
+// counted by paging through the IDs of the messages.
+func (s *Service) QueryCounts() (map[string]tin.MailCount, error) {
+	counts := map[string]tin.MailCount{}
+	if len(s.queries.Counters) == 0 {
+		return counts, nil
+	}
+
+	users, err := s.getUsersService()
+	if users == nil {
+		return counts, err
+	}
+
+	labels, err := listLabels(users)
+	if err != nil {
+		return counts, fmt.Errorf("Failed fetching labels: %w", err)
+	}
+
+	for name, q := range s.queries.Counters {
+		if id, ok := unreadLabel(q, labels); ok {
+			l, err := users.Labels.Get("me", id).Fields("messagesUnread").Do()
+			if err != nil {
+				return counts, fmt.Errorf("Failed fetching label of query %v: %w", name, err)
+			}
+			counts[name] = tin.MailCount(l.MessagesUnread)
+			continue
+		}
+
+		n, err := countMessages(users, q)
+		if err != nil {
+			return counts, fmt.Errorf("Failed counting mails of query %v: %w", name, err)
+		}
+		counts[name] = tin.MailCount(n)
+	}
+
+	return counts, nil
+}
+
+// countMessages returns the number of messages that match the query.
+func countMessages(users *gmail.UsersService, q string) (int, error) {
+	n := 0
+	token := ""
+	for {
+		call := users.Messages.List("me").Q(q).MaxResults(maxResults).Fields("messages/id", "nextPageToken")
+		if token != "" {
+			call = call.PageToken(token)
+		}
+
+		resp, err := call.Do()
+		if err != nil {
+			return 0, err
+		}
+		n += len(resp.Messages)
+
+		if resp.NextPageToken == "" {
+			return n, nil
+		}
+		token = resp.NextPageToken
+	}
+}
+
+// unreadLabel returns the ID of the label of a query that consists of
+// "is:unread" and a single label, "in:" or "category:" term.
+func unreadLabel(q string, labels []*gmail.Label) (string, bool) {
+	ids, ok := queryLabels(q, labels)
+	if !ok || len(ids) != 2 {
+		return "", false
+	}
+
+	switch {
+	case ids[0] == "UNREAD" && ids[1] != "UNREAD":
+		return ids[1], true
+	case ids[1] == "UNREAD" && ids[0] != "UNREAD":
+		return ids[0], true
+	}
+	return "", false
+}
+
+// queryLabels returns the IDs of the labels of a query that consists of label
+// terms only, a message matches the query when it has all of the labels.
+//
+// Gmail searches labels by their lower case name in which spaces and
+// slashes are replaced with dashes.
+func queryLabels(q string, labels []*gmail.Label) ([]string, bool) {
+	terms := strings.Fields(strings.ToLower(q))
+	if len(terms) == 0 {
+		return nil, false
+	}
+
+	ids := []string{}
+	for _, term := range terms {
+		id, ok := termLabel(term, labels)
+		if !ok {
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
+
+// termLabel returns the ID of the label of a search term.
+func termLabel(term string, labels []*gmail.Label) (string, bool) {
+	i := strings.Index(term, ":")
+	if i < 0 {
+		return "", false
+	}
+	operator, value := term[:i], labelSearchName(term[i+1:])
+
+	switch operator {
+	case "is":
+		id, ok := stateLabels[value]
+		return id, ok
+	case "category":
+		id, ok := categoryLabels[value]
+		return id, ok
+	case "label", "in":
+		for _, l := range labels {
+			if labelSearchName(l.Name) == value || strings.ToLower(l.Id) == value {
+				return l.Id, true
+			}
+		}
+	}
+	return "", false
+}
+
+// labelSearchName returns the name of a label as it is used in search queries.
+func labelSearchName(name string) string {
+	return strings.NewReplacer(" ", "-", "/", "-").Replace(strings.ToLower(name))
+}
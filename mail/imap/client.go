@@ -0,0 +1,383 @@
+package imap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Represents the security of the connection.
+const (
+	SecurityTLS      = "tls"
+	SecurityStartTLS = "starttls"
+	SecurityNone     = "none"
+)
+
+// errNoIdle means the server doesn't support IMAP IDLE.
+var errNoIdle = errors.New("imap: idle not supported")
+
+// maxLiteralSize is the maximum size of a literal, only headers are fetched.
+const maxLiteralSize = 4 << 20
+
+// response represents a response line, the literals are the strings
+// the line contains in the {n} literal syntax.
+type response struct {
+	text     string
+	literals [][]byte
+}
+
+// client is a minimal IMAP4rev1 client, see RFC 3501.
+//
+// Every command has to complete within the timeout, except for the IDLE
+// state which lasts as long as requested.
+type client struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	tag     int
+	timeout time.Duration
+}
+
+// dial connects and authenticates to the server of the account.
+func dial(a Account, tlsConfig *tls.Config, timeout time.Duration) (*client, error) {
+	host, _, err := net.SplitHostPort(a.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	tlsConfig = tlsConfig.Clone()
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = host
+	}
+
+	d := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	switch a.Security {
+	case SecurityStartTLS, SecurityNone:
+		conn, err = d.Dial("tcp", a.Address)
+	default:
+		conn, err = tls.DialWithDialer(d, "tcp", a.Address, tlsConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &client{conn: conn, r: bufio.NewReader(conn), timeout: timeout}
+	conn.SetDeadline(time.Now().Add(timeout))
+	if err := c.greeting(); err != nil {
+		c.close()
+		return nil, err
+	}
+
+	if a.Security == SecurityStartTLS {
+		if _, err := c.command("STARTTLS"); err != nil {
+			c.close()
+			return nil, err
+		}
+
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			c.close()
+			return nil, err
+		}
+		c.conn = tlsConn
+		c.r = bufio.NewReader(tlsConn)
+	}
+
+	if err := c.authenticate(a); err != nil {
+		c.close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// greeting reads the greeting of the server.
+func (c *client) greeting() error {
+	r, err := c.readResponse()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(r.text, "* OK") && !strings.HasPrefix(r.text, "* PREAUTH") {
+		return fmt.Errorf("imap: unexpected greeting %q", r.text)
+	}
+	return nil
+}
+
+// authenticate logs in with XOAUTH2 when the account has a token source, with the password otherwise.
+func (c *client) authenticate(a Account) error {
+	if a.TokenSource != nil {
+		t, err := a.TokenSource.Token()
+		if err != nil {
+			return fmt.Errorf("imap: token: %w", err)
+		}
+
+		ir := base64.StdEncoding.EncodeToString([]byte("user=" + a.Username + "\x01auth=Bearer " + t.AccessToken + "\x01\x01"))
+		_, err = c.command("AUTHENTICATE XOAUTH2 " + ir)
+		return err
+	}
+
+	_, err := c.command(fmt.Sprintf("LOGIN %v %v", quote(a.Username), quote(a.Password)))
+	return err
+}
+
+// command sends the command and returns the untagged responses.
+//
+// A continuation request is answered with an empty line, which cancels
+// for example a failed AUTHENTICATE.
+func (c *client) command(cmd string) ([]response, error) {
+	tag, err := c.send(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	untagged := []response{}
+	for {
+		r, err := c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case strings.HasPrefix(r.text, "+"):
+			if _, err := io.WriteString(c.conn, "\r\n"); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(r.text, tag+" "):
+			return untagged, status(strings.TrimPrefix(r.text, tag+" "))
+		default:
+			untagged = append(untagged, r)
+		}
+	}
+}
+
+// send writes the command with a new tag and returns the tag.
+//
+// The command and its responses have to complete within the timeout.
+func (c *client) send(cmd string) (string, error) {
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	c.tag++
+	tag := fmt.Sprintf("T%d", c.tag)
+	_, err := io.WriteString(c.conn, tag+" "+cmd+"\r\n")
+	return tag, err
+}
+
+// status returns the error of the status of a tagged response, or nil when it's OK.
+func status(text string) error {
+	if strings.HasPrefix(text, "OK") {
+		return nil
+	}
+	return fmt.Errorf("imap: %v", text)
+}
+
+// readResponse reads a response line including its literals.
+//
+// Example of a line with a literal: * 1 FETCH (BODY[HEADER.FIELDS (SUBJECT)] {16}
+func (c *client) readResponse() (response, error) {
+	r := response{}
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return r, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		r.text += line
+
+		n, ok, err := literalSize(line)
+		if err != nil {
+			return r, err
+		}
+		if !ok {
+			return r, nil
+		}
+
+		literal := make([]byte, n)
+		if _, err := io.ReadFull(c.r, literal); err != nil {
+			return r, err
+		}
+		r.literals = append(r.literals, literal)
+	}
+}
+
+// literalSize returns the size of the literal the line ends with.
+//
+// An error is returned when the size exceeds the maxLiteralSize.
+func literalSize(line string) (int, bool, error) {
+	if !strings.HasSuffix(line, "}") {
+		return 0, false, nil
+	}
+
+	i := strings.LastIndex(line, "{")
+	if i < 0 {
+		return 0, false, nil
+	}
+
+	v := line[i+1 : len(line)-1]
+	if len(v) == 0 || strings.Trim(v, "0123456789") != "" {
+		return 0, false, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n > maxLiteralSize {
+		return 0, false, fmt.Errorf("imap: literal of %v bytes exceeds %v bytes", v, maxLiteralSize)
+	}
+	return n, true, nil
+}
+
+// capabilities returns the capabilities of the server.
+//
+// Example of a line: * CAPABILITY IMAP4rev1 IDLE AUTH=XOAUTH2
+func (c *client) capabilities() (map[string]bool, error) {
+	responses, err := c.command("CAPABILITY")
+	if err != nil {
+		return nil, err
+	}
+
+	caps := map[string]bool{}
+	for _, r := range responses {
+		if !strings.HasPrefix(r.text, "* CAPABILITY ") {
+			continue
+		}
+		for _, v := range strings.Fields(strings.TrimPrefix(r.text, "* CAPABILITY ")) {
+			caps[strings.ToUpper(v)] = true
+		}
+	}
+	return caps, nil
+}
+
+// examine selects the mailbox read-only.
+func (c *client) examine(mailbox string) error {
+	_, err := c.command("EXAMINE " + quote(mailbox))
+	return err
+}
+
+// search returns the sequence numbers of the messages matching the criteria.
+//
+// Example of a line: * SEARCH 2 84 882
+func (c *client) search(criteria string) ([]string, error) {
+	responses, err := c.command("SEARCH " + criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, r := range responses {
+		if r.text == "* SEARCH" || strings.HasPrefix(r.text, "* SEARCH ") {
+			ids = append(ids, strings.Fields(strings.TrimPrefix(r.text, "* SEARCH"))...)
+		}
+	}
+	return ids, nil
+}
+
+// fetchHeaders returns the headers of the messages, the fields are
+// fetched without setting the seen flag.
+func (c *client) fetchHeaders(ids []string, fields ...string) ([][]byte, error) {
+	if len(ids) == 0 {
+		return [][]byte{}, nil
+	}
+
+	cmd := fmt.Sprintf("FETCH %v (BODY.PEEK[HEADER.FIELDS (%v)])", strings.Join(ids, ","), strings.Join(fields, " "))
+	responses, err := c.command(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := [][]byte{}
+	for _, r := range responses {
+		if strings.Contains(r.text, " FETCH ") && len(r.literals) > 0 {
+			headers = append(headers, r.literals[0])
+		}
+	}
+	return headers, nil
+}
+
+// idle waits in the IDLE state until the server reports a change of the
+// mailbox, or the duration has passed. It reports whether the mailbox changed.
+//
+// Untagged responses may arrive before the continuation request that
+// starts the IDLE state.
+func (c *client) idle(d time.Duration) (bool, error) {
+	tag, err := c.send("IDLE")
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	for {
+		r, err := c.readResponse()
+		if err != nil {
+			return false, err
+		}
+		if strings.HasPrefix(r.text, "+") {
+			break
+		}
+		if strings.HasPrefix(r.text, tag+" ") {
+			return false, status(strings.TrimPrefix(r.text, tag+" "))
+		}
+		changed = changed || isChange(r.text)
+	}
+
+	c.conn.SetReadDeadline(time.Now().Add(d))
+	for !changed {
+		r, err := c.readResponse()
+		if err != nil {
+			if e, ok := err.(net.Error); ok && e.Timeout() {
+				break
+			}
+			return false, err
+		}
+		changed = isChange(r.text)
+	}
+
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	if _, err := io.WriteString(c.conn, "DONE\r\n"); err != nil {
+		return false, err
+	}
+	for {
+		r, err := c.readResponse()
+		if err != nil {
+			return false, err
+		}
+		if strings.HasPrefix(r.text, tag+" ") {
+			return changed, status(strings.TrimPrefix(r.text, tag+" "))
+		}
+	}
+}
+
+// isChange reports whether the untagged response reports a change of the mailbox.
+//
+// Example of a change: * 23 EXISTS
+func isChange(text string) bool {
+	f := strings.Fields(text)
+	if len(f) < 3 || f[0] != "*" {
+		return false
+	}
+
+	switch strings.ToUpper(f[2]) {
+	case "EXISTS", "EXPUNGE", "FETCH", "RECENT":
+		return true
+	}
+	return false
+}
+
+// close logs out and closes the connection.
+func (c *client) close() error {
+	c.conn.SetDeadline(time.Now().Add(time.Second))
+	c.send("LOGOUT")
+	return c.conn.Close()
+}
+
+// quote returns the string in the quoted syntax.
+func quote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	return `"` + s + `"`
+}
@@ -0,0 +1,211 @@
+package imap
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/mail"
+	"strings"
+	"time"
+
+	tinmail "github.com/sjengpho/tin/mail"
+	"github.com/sjengpho/tin/tin"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// Represents the timings of the connections.
+const (
+	dialTimeout = 30 * time.Second
+	// idleTimeout is below the 29 minutes after which servers may log out an idle client.
+	idleTimeout  = 25 * time.Minute
+	idleRetryMin = 5 * time.Second
+	idleRetryMax = 5 * time.Minute
+)
+
+// Account represents an IMAP account.
+type Account struct {
+	Address   string // Host and port of the server, for example imap.example.com:993.
+	Security  string // tls, starttls or none. When it is empty tls is used.
+	Username  string
+	Password  string
+	Mailboxes []string // When it is empty the INBOX is used.
+
+	// TokenSource returns the OAuth 2.0 access tokens, XOAUTH2 is used
+	// instead of the password when it is set.
+	TokenSource oauth2.TokenSource
+}
+
+// NewTokenSource returns an oauth2.TokenSource of the OAuth 2.0 files of an account.
+//
+// The credentials file is the client of the account in the format of the
+// Google API Console, the token file is the JSON of an oauth2.Token. The
+// access token is refreshed with the refresh token of the token file when it expires.
+func NewTokenSource(credentials string, token string) (oauth2.TokenSource, error) {
+	data, err := ioutil.ReadFile(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening file %v: %w", credentials, err)
+	}
+
+	config, err := google.ConfigFromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing credentials file: %w", err)
+	}
+
+	data, err = ioutil.ReadFile(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening file %v: %w", token, err)
+	}
+
+	t := &oauth2.Token{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("failed parsing token file: %w", err)
+	}
+
+	return config.TokenSource(context.Background(), t), nil
+}
+
+// Service implements tin.MailProvider and tin.MailWatcher.
+type Service struct {
+	account     Account
+	tlsConfig   *tls.Config
+	idleTimeout time.Duration
+}
+
+// NewService returns a new Service.
+func NewService(a Account) *Service {
+	if len(a.Mailboxes) == 0 {
+		a.Mailboxes = []string{"INBOX"}
+	}
+
+	return &Service{account: a, idleTimeout: idleTimeout}
+}
+
+// UnreadMails fetches the unseen messages of the mailboxes.
+//
+// The mailboxes are examined read-only and the headers are fetched with
+// BODY.PEEK, so the messages stay unseen.
+func (s *Service) UnreadMails() ([]tin.Mail, error) {
+	c, err := dial(s.account, s.tlsConfig, dialTimeout)
+	if err != nil {
+		return []tin.Mail{}, fmt.Errorf("Failed connecting to %v: %w", s.account.Address, err)
+	}
+	defer c.close()
+
+	mm := []tin.Mail{}
+	for _, mailbox := range s.account.Mailboxes {
+		if err := c.examine(mailbox); err != nil {
+			return []tin.Mail{}, fmt.Errorf("Failed examining %v: %w", mailbox, err)
+		}
+
+		ids, err := c.search("UNSEEN")
+		if err != nil {
+			return []tin.Mail{}, fmt.Errorf("Failed fetching unread mails: %w", err)
+		}
+
+		headers, err := c.fetchHeaders(ids, "MESSAGE-ID", "FROM", "SUBJECT", "DATE")
+		if err != nil {
+			return []tin.Mail{}, fmt.Errorf("Failed fetching unread mails: %w", err)
+		}
+
+		for _, h := range headers {
+			m := parseHeader(h)
+			m.Labels = []string{mailbox}
+			mm = append(mm, m)
+		}
+	}
+
+	return mm, nil
+}
+
+// Watch returns a channel that receives a value when a mailbox changes.
+//
+// Every mailbox is watched with IMAP IDLE on its own connection, which is
+// reconnected with a backoff when it fails. Mailboxes of a server that
+// doesn't support IDLE aren't watched.
+func (s *Service) Watch() (<-chan struct{}, error) {
+	ch := make(chan struct{}, 1)
+	for _, mailbox := range s.account.Mailboxes {
+		go s.watch(mailbox, ch)
+	}
+	return ch, nil
+}
+
+// watch idles on the mailbox until the server turns out not to support IDLE.
+func (s *Service) watch(mailbox string, ch chan struct{}) {
+	retry := idleRetryMin
+	for {
+		err := s.idle(mailbox, ch, func() { retry = idleRetryMin })
+		if err == errNoIdle {
+			return
+		}
+
+		time.Sleep(retry)
+		if retry *= 2; retry > idleRetryMax {
+			retry = idleRetryMax
+		}
+	}
+}
+
+// idle connects and sends a value to the channel whenever the mailbox changes.
+//
+// The connected func is called once the mailbox is examined.
+func (s *Service) idle(mailbox string, ch chan struct{}, connected func()) error {
+	c, err := dial(s.account, s.tlsConfig, dialTimeout)
+	if err != nil {
+		return err
+	}
+	defer c.close()
+
+	caps, err := c.capabilities()
+	if err != nil {
+		return err
+	}
+	if !caps["IDLE"] {
+		return errNoIdle
+	}
+
+	if err := c.examine(mailbox); err != nil {
+		return err
+	}
+	connected()
+
+	for {
+		changed, err := c.idle(s.idleTimeout)
+		if err != nil {
+			return err
+		}
+		if changed {
+			notify(ch)
+		}
+	}
+}
+
+// notify sends a value to the channel unless it already has a pending value.
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// parseHeader returns the tin.Mail of the header fields.
+func parseHeader(header []byte) tin.Mail {
+	msg, err := mail.ReadMessage(bytes.NewReader(append(bytes.TrimRight(header, "\r\n"), "\r\n\r\n"...)))
+	if err != nil {
+		return tin.Mail{}
+	}
+
+	m := tin.Mail{
+		ID:      strings.Trim(msg.Header.Get("Message-Id"), "<> "),
+		From:    tinmail.Sender(msg.Header.Get("From")),
+		Subject: tinmail.DecodeHeader(msg.Header.Get("Subject")),
+	}
+	if date, err := msg.Header.Date(); err == nil {
+		m.Date = date
+	}
+	return m
+}
@@ -0,0 +1,520 @@
+package imap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+	"golang.org/x/oauth2"
+)
+
+type testMessage struct {
+	from    string
+	subject string
+	seen    bool
+}
+
+// testServer is an in-process IMAP server that implements the commands used by the client.
+type testServer struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+	implicit  bool // The connection starts with TLS.
+
+	username  string
+	password  string
+	token     string
+	noIdle    bool
+	mailboxes map[string][]testMessage
+
+	once     sync.Once
+	mutex    sync.Mutex
+	commands []string
+	idling   chan struct{}
+	pushes   chan string
+}
+
+func newTestServer(t *testing.T) *testServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &testServer{
+		listener: l,
+		username: "user@example.com",
+		password: `p"ss\word`,
+		token:    "token",
+		mailboxes: map[string][]testMessage{
+			"INBOX": {
+				{from: "Alice <alice@example.com>", subject: "Hello"},
+				{subject: "Read", seen: true},
+				{subject: "=?UTF-8?q?Caf=C3=A9?="},
+			},
+			"Work": {
+				{subject: "Report"},
+			},
+		},
+		idling: make(chan struct{}, 10),
+		pushes: make(chan string, 10),
+	}
+	t.Cleanup(func() { l.Close() })
+	return s
+}
+
+// withTLS makes the server use a certificate the returned service trusts.
+func (s *testServer) withTLS(t *testing.T) *tls.Config {
+	ts := httptest.NewUnstartedServer(nil)
+	ts.StartTLS()
+	defer ts.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+	s.tlsConfig = &tls.Config{Certificates: ts.TLS.Certificates}
+	return &tls.Config{RootCAs: pool}
+}
+
+// address starts serving once the server is configured and returns its address.
+func (s *testServer) address() string {
+	s.once.Do(func() { go s.serve() })
+	return s.listener.Addr().String()
+}
+
+func (s *testServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *testServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	if s.implicit {
+		conn = tls.Server(conn, s.tlsConfig)
+	}
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "* OK IMAP4rev1 ready\r\n")
+
+	selected := ""
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		f := strings.SplitN(line, " ", 3)
+		if len(f) < 2 {
+			fmt.Fprint(conn, "* BAD invalid command\r\n")
+			continue
+		}
+		tag, cmd, args := f[0], strings.ToUpper(f[1]), ""
+		if len(f) == 3 {
+			args = f[2]
+		}
+
+		s.mutex.Lock()
+		s.commands = append(s.commands, cmd)
+		s.mutex.Unlock()
+
+		switch cmd {
+		case "CAPABILITY":
+			caps := "IMAP4rev1 STARTTLS AUTH=XOAUTH2"
+			if !s.noIdle {
+				caps += " IDLE"
+			}
+			fmt.Fprintf(conn, "* CAPABILITY %v\r\n%v OK done\r\n", caps, tag)
+		case "STARTTLS":
+			fmt.Fprintf(conn, "%v OK begin TLS\r\n", tag)
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			r = bufio.NewReader(conn)
+		case "LOGIN":
+			if args != quote(s.username)+" "+quote(s.password) {
+				fmt.Fprintf(conn, "%v NO [AUTHENTICATIONFAILED] invalid credentials\r\n", tag)
+				continue
+			}
+			fmt.Fprintf(conn, "%v OK logged in\r\n", tag)
+		case "AUTHENTICATE":
+			want := base64.StdEncoding.EncodeToString([]byte("user=" + s.username + "\x01auth=Bearer " + s.token + "\x01\x01"))
+			if args != "XOAUTH2 "+want {
+				fmt.Fprint(conn, "+ eyJzdGF0dXMiOiI0MDEifQ==\r\n")
+				if _, err := r.ReadString('\n'); err != nil {
+					return
+				}
+				fmt.Fprintf(conn, "%v NO [AUTHENTICATIONFAILED] invalid token\r\n", tag)
+				continue
+			}
+			fmt.Fprintf(conn, "%v OK authenticated\r\n", tag)
+		case "EXAMINE":
+			name := strings.Trim(args, `"`)
+			messages, ok := s.mailboxes[name]
+			if !ok {
+				fmt.Fprintf(conn, "%v NO no such mailbox\r\n", tag)
+				continue
+			}
+			selected = name
+			fmt.Fprintf(conn, "* %v EXISTS\r\n%v OK [READ-ONLY] examined\r\n", len(messages), tag)
+		case "SEARCH":
+			ids := []string{}
+			for i, m := range s.mailboxes[selected] {
+				if !m.seen {
+					ids = append(ids, fmt.Sprint(i+1))
+				}
+			}
+			fmt.Fprintf(conn, "* SEARCH %v\r\n%v OK done\r\n", strings.Join(ids, " "), tag)
+		case "FETCH":
+			set := strings.SplitN(args, " ", 2)[0]
+			for _, id := range strings.Split(set, ",") {
+				var i int
+				fmt.Sscan(id, &i)
+				m := s.mailboxes[selected][i-1]
+				header := fmt.Sprintf("From: %v\r\nSubject: %v\r\n\r\n", m.from, m.subject)
+				fmt.Fprintf(conn, "* %v FETCH (BODY[HEADER.FIELDS (FROM SUBJECT)] {%v}\r\n%v)\r\n", i, len(header), header)
+			}
+			fmt.Fprintf(conn, "%v OK done\r\n", tag)
+		case "IDLE":
+			fmt.Fprint(conn, "+ idling\r\n")
+			s.idling <- struct{}{}
+
+			done := make(chan error)
+			go func() {
+				_, err := r.ReadString('\n')
+				done <- err
+			}()
+
+		idle:
+			for {
+				select {
+				case line := <-s.pushes:
+					fmt.Fprint(conn, line+"\r\n")
+				case err := <-done:
+					if err != nil {
+						return
+					}
+					fmt.Fprintf(conn, "%v OK idle done\r\n", tag)
+					break idle
+				}
+			}
+		case "LOGOUT":
+			fmt.Fprintf(conn, "* BYE\r\n%v OK done\r\n", tag)
+			return
+		default:
+			fmt.Fprintf(conn, "%v BAD unknown command\r\n", tag)
+		}
+	}
+}
+
+func (s *testServer) count(cmd string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	n := 0
+	for _, c := range s.commands {
+		if c == cmd {
+			n++
+		}
+	}
+	return n
+}
+
+func staticToken(token string) oauth2.TokenSource {
+	return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+}
+
+func snippets(mm []tin.Mail) []string {
+	v := []string{}
+	for _, m := range mm {
+		v = append(v, m.Subject)
+	}
+	sort.Strings(v)
+	return v
+}
+
+func TestUnreadMails(t *testing.T) {
+	s := newTestServer(t)
+
+	tt := []struct {
+		account Account
+		want    []string
+	}{
+		{
+			account: Account{Security: SecurityNone, Username: s.username, Password: s.password},
+			want:    []string{"Café", "Hello"},
+		},
+		{
+			account: Account{Security: SecurityNone, Username: s.username, TokenSource: staticToken(s.token), Mailboxes: []string{"INBOX", "Work"}},
+			want:    []string{"Café", "Hello", "Report"},
+		},
+	}
+
+	for _, tc := range tt {
+		tc.account.Address = s.address()
+		got, err := NewService(tc.account).UnreadMails()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(snippets(got), tc.want) {
+			t.Errorf("want %v, got %v", tc.want, snippets(got))
+		}
+	}
+}
+
+func TestUnreadMailsError(t *testing.T) {
+	s := newTestServer(t)
+	address := s.address()
+
+	tt := []Account{
+		{Address: address, Security: SecurityNone, Username: s.username, Password: "wrong"},
+		{Address: address, Security: SecurityNone, Username: s.username, TokenSource: staticToken("wrong")},
+		{Address: address, Security: SecurityNone, Username: s.username, Password: s.password, Mailboxes: []string{"Missing"}},
+		{Address: "invalid", Security: SecurityNone},
+	}
+
+	for _, a := range tt {
+		got, err := NewService(a).UnreadMails()
+		if err == nil {
+			t.Errorf("want error, got nil")
+		}
+		if len(got) != 0 {
+			t.Errorf("want %v, got %v", 0, len(got))
+		}
+	}
+}
+
+func TestUnreadMailsTLS(t *testing.T) {
+	tt := []struct {
+		security string
+		implicit bool
+	}{
+		{security: SecurityTLS, implicit: true},
+		{security: "", implicit: true},
+		{security: SecurityStartTLS},
+	}
+
+	for _, tc := range tt {
+		s := newTestServer(t)
+		s.implicit = tc.implicit
+		tlsConfig := s.withTLS(t)
+		service := NewService(Account{Address: s.address(), Security: tc.security, Username: s.username, Password: s.password})
+		service.tlsConfig = tlsConfig
+
+		got, err := service.UnreadMails()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{"Café", "Hello"}
+		if !reflect.DeepEqual(snippets(got), want) {
+			t.Errorf("want %v, got %v", want, snippets(got))
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	s := newTestServer(t)
+	service := NewService(Account{Address: s.address(), Security: SecurityNone, Username: s.username, Password: s.password})
+	service.idleTimeout = 100 * time.Millisecond
+	events, err := service.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The IDLE is issued again after the timeout.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-s.idling:
+		case <-time.After(5 * time.Second):
+			t.Fatal("want idle, got timeout")
+		}
+	}
+
+	select {
+	case <-events:
+		t.Fatal("want no event, got event")
+	default:
+	}
+
+	<-s.idling
+	s.pushes <- "* 4 EXISTS"
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("want event, got timeout")
+	}
+
+	if got := s.count("EXAMINE"); got != 1 {
+		t.Errorf("want %v, got %v", 1, got)
+	}
+}
+
+func TestWatchNoIdle(t *testing.T) {
+	s := newTestServer(t)
+	s.noIdle = true
+	service := NewService(Account{Address: s.address(), Security: SecurityNone, Username: s.username, Password: s.password})
+
+	ch := make(chan struct{}, 1)
+	err := service.idle("INBOX", ch, func() {})
+	if err != errNoIdle {
+		t.Errorf("want %v, got %v", errNoIdle, err)
+	}
+}
+
+func TestIdleUntaggedBeforeContinuation(t *testing.T) {
+	server, conn := net.Pipe()
+	defer server.Close()
+	c := &client{conn: conn, r: bufio.NewReader(conn), timeout: 5 * time.Second}
+
+	go func() {
+		r := bufio.NewReader(server)
+		r.ReadString('\n')
+		io.WriteString(server, "* OK still here\r\n* 4 EXISTS\r\n+ idling\r\n")
+		r.ReadString('\n')
+		io.WriteString(server, "T1 OK idle done\r\n")
+	}()
+
+	changed, err := c.idle(time.Minute)
+	if err != nil || !changed {
+		t.Errorf("want %v, got %v, %v", true, changed, err)
+	}
+}
+
+func TestCommandTimeout(t *testing.T) {
+	server, conn := net.Pipe()
+	defer server.Close()
+	c := &client{conn: conn, r: bufio.NewReader(conn), timeout: 100 * time.Millisecond}
+
+	// The server stalls after receiving the command.
+	go bufio.NewReader(server).ReadString('\n')
+
+	_, err := c.search("UNSEEN")
+	if e, ok := err.(net.Error); !ok || !e.Timeout() {
+		t.Errorf("want %v, got %v", "timeout", err)
+	}
+}
+
+func TestNewTokenSource(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("refresh_token") != "refresh" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"access_token":"refreshed","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "imap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	credentials := filepath.Join(dir, "credentials.json")
+	ioutil.WriteFile(credentials, []byte(`{"installed":{"client_id":"id","client_secret":"secret","redirect_uris":["http://localhost"],"token_uri":"`+ts.URL+`"}}`), 0600)
+	token := filepath.Join(dir, "token.json")
+	ioutil.WriteFile(token, []byte(`{"access_token":"expired","refresh_token":"refresh","expiry":"2020-01-01T00:00:00Z"}`), 0600)
+
+	s, err := NewTokenSource(credentials, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "refreshed"
+	got, err := s.Token()
+	if err != nil || got.AccessToken != want {
+		t.Errorf("want %v, got %v, %v", want, got, err)
+	}
+
+	if _, err := NewTokenSource(credentials, filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("want error, got nil")
+	}
+}
+
+func TestReadResponse(t *testing.T) {
+	server, conn := net.Pipe()
+	defer server.Close()
+	c := &client{conn: conn, r: bufio.NewReader(conn)}
+
+	go io.WriteString(server, "* 1 FETCH (BODY[HEADER.FIELDS (SUBJECT)] {15}\r\nSubject: Hi\r\n\r\n)\r\n")
+	got, err := c.readResponse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := response{
+		text:     "* 1 FETCH (BODY[HEADER.FIELDS (SUBJECT)] {15})",
+		literals: [][]byte{[]byte("Subject: Hi\r\n\r\n")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestReadResponseLiteralLimit(t *testing.T) {
+	server, conn := net.Pipe()
+	defer server.Close()
+	c := &client{conn: conn, r: bufio.NewReader(conn)}
+
+	for _, line := range []string{"* 1 FETCH (BODY[] {9999999999}\r\n", "* 1 FETCH (BODY[] {99999999999999999999}\r\n"} {
+		go io.WriteString(server, line)
+		if _, err := c.readResponse(); err == nil {
+			t.Errorf("%q: want error, got nil", line)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	want := `"a\"b\\c"`
+	got := quote(`a"b\c`)
+	if got != want {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	tt := []struct {
+		header string
+		want   tin.Mail
+	}{
+		{header: "From: Alice <alice@example.com>\r\nSubject: Hello\r\n\r\n", want: tin.Mail{From: "Alice", Subject: "Hello"}},
+		{
+			header: "Message-ID: <1@example.com>\r\nDate: Wed, 01 Jan 2020 10:00:00 +0000\r\nSubject: Dated\r\n\r\n",
+			want:   tin.Mail{ID: "1@example.com", Subject: "Dated", Date: time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)},
+		},
+		{header: "From: bob@example.com\r\nSubject: =?UTF-8?q?Caf=C3=A9?=\r\n\r\n", want: tin.Mail{From: "bob@example.com", Subject: "Café"}},
+		{header: "From: =?UTF-8?q?Jos=C3=A9?= <jose@example.com>\r\n", want: tin.Mail{From: "José"}},
+		{header: "\r\n", want: tin.Mail{}},
+	}
+
+	for _, tc := range tt {
+		got := parseHeader([]byte(tc.header))
+		got.Date = got.Date.UTC()
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("want %+v, got %+v", tc.want, got)
+		}
+	}
+}
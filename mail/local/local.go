@@ -0,0 +1,205 @@
+package local
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tinmail "github.com/sjengpho/tin/mail"
+	"github.com/sjengpho/tin/tin"
+)
+
+// headerLimit is the maximum number of bytes read of the header of a message.
+const headerLimit = 64 << 10
+
+// Service implements tin.MailProvider, tin.MailWatcher and tin.LocalMailProvider for local mail,
+// for example synced by mbsync or offlineimap.
+type Service struct {
+	paths []string
+}
+
+// NewService returns a new Service of Maildir folders and mbox files.
+func NewService(paths []string) *Service {
+	return &Service{paths: paths}
+}
+
+// Local implements tin.LocalMailProvider, the mailboxes don't need the network.
+func (s *Service) Local() bool {
+	return true
+}
+
+// UnreadMails returns the unread messages of the Maildir folders and mbox files.
+//
+// A path is treated as Maildir folder when it's a directory, as mbox file otherwise.
+func (s *Service) UnreadMails() ([]tin.Mail, error) {
+	mm := []tin.Mail{}
+	for _, path := range s.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return []tin.Mail{}, fmt.Errorf("Failed reading %v: %w", path, err)
+		}
+
+		var unread []tin.Mail
+		if info.IsDir() {
+			unread, err = maildirUnread(path)
+		} else {
+			unread, err = mboxUnread(path)
+		}
+		if err != nil {
+			return []tin.Mail{}, fmt.Errorf("Failed reading %v: %w", path, err)
+		}
+		for i := range unread {
+			unread[i].Labels = []string{filepath.Base(path)}
+		}
+		mm = append(mm, unread...)
+	}
+
+	return mm, nil
+}
+
+// maildirUnread returns the unread messages of the Maildir folder.
+//
+// Messages in new are unread, messages in cur are unread when the info
+// lacks the seen flag. See https://cr.yp.to/proto/maildir.html.
+// Example of a name in cur: 1577836800.M1P2.host,U=12:2,FS
+func maildirUnread(path string) ([]tin.Mail, error) {
+	mm := []tin.Mail{}
+	for _, dir := range []string{"new", "cur"} {
+		files, err := ioutil.ReadDir(filepath.Join(path, dir))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range files {
+			if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+				continue
+			}
+			if dir == "cur" && maildirSeen(f.Name()) {
+				continue
+			}
+
+			m, err := readMaildirMessage(filepath.Join(path, dir, f.Name()))
+			if os.IsNotExist(err) {
+				// The message was moved in the meantime, for example when it was read.
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			mm = append(mm, m)
+		}
+	}
+	return mm, nil
+}
+
+// maildirSeen reports whether the info of the name has the seen flag.
+func maildirSeen(name string) bool {
+	i := strings.LastIndex(name, ":2,")
+	if i < 0 {
+		return false
+	}
+	return strings.ContainsRune(name[i+3:], 'S')
+}
+
+// readMaildirMessage returns the tin.Mail of the header of the message file.
+func readMaildirMessage(path string) (tin.Mail, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return tin.Mail{}, err
+	}
+	defer f.Close()
+
+	msg, err := mail.ReadMessage(io.LimitReader(f, headerLimit))
+	if err != nil {
+		// The message is counted even if the header is malformed.
+		return tin.Mail{}, nil
+	}
+	return newMail(msg.Header), nil
+}
+
+// mboxUnread returns the unread messages of the mbox file.
+//
+// Messages start with a From line at the start of the file or after an empty
+// line. A message is unread when the Status header lacks the read flag,
+// messages of which the X-Status header has the deleted flag are ignored.
+// Example of the headers of a read message: Status: RO
+func mboxUnread(path string) ([]tin.Mail, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	mm := []tin.Mail{}
+	r := bufio.NewReader(f)
+	header := []byte{}
+	inHeader := false
+	blank := true
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			empty := len(bytes.TrimRight(line, "\r\n")) == 0
+			switch {
+			case blank && !inHeader && bytes.HasPrefix(line, []byte("From ")):
+				inHeader = true
+				header = header[:0]
+			case inHeader && empty:
+				inHeader = false
+				if m, unread := parseMboxHeader(header); unread {
+					mm = append(mm, m)
+				}
+			case inHeader && len(header) < headerLimit:
+				header = append(header, line...)
+			}
+			blank = empty
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The file ends within the header of the last message.
+	if inHeader {
+		if m, unread := parseMboxHeader(header); unread {
+			mm = append(mm, m)
+		}
+	}
+	return mm, nil
+}
+
+// parseMboxHeader returns the tin.Mail of the header of a mbox message and
+// reports whether it's unread.
+func parseMboxHeader(header []byte) (tin.Mail, bool) {
+	msg, err := mail.ReadMessage(bytes.NewReader(append(header, "\r\n"...)))
+	if err != nil {
+		return tin.Mail{}, true
+	}
+
+	if strings.ContainsRune(msg.Header.Get("Status"), 'R') || strings.ContainsRune(msg.Header.Get("X-Status"), 'D') {
+		return tin.Mail{}, false
+	}
+	return newMail(msg.Header), true
+}
+
+// newMail returns the tin.Mail of the header.
+func newMail(h mail.Header) tin.Mail {
+	m := tin.Mail{
+		ID:      strings.Trim(h.Get("Message-Id"), "<> "),
+		From:    tinmail.Sender(h.Get("From")),
+		Subject: tinmail.DecodeHeader(h.Get("Subject")),
+	}
+	if date, err := h.Date(); err == nil {
+		m.Date = date
+	}
+	return m
+}
@@ -0,0 +1,247 @@
+package local
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+const testMbox = `From alice@example.com Wed Jan  1 00:00:00 2020
+From: Alice <alice@example.com>
+Subject: Unread
+
+>From the body is escaped, it isn't a new message.
+
+From bob@example.com Wed Jan  1 00:00:00 2020
+From: bob@example.com
+Subject: Read
+Status: RO
+
+Body
+
+From carol@example.com Wed Jan  1 00:00:00 2020
+From: Carol <carol@example.com>
+Subject: Deleted
+X-Status: D
+
+Body
+
+From dave@example.com Wed Jan  1 00:00:00 2020
+From: =?UTF-8?q?Dav=C3=A9?= <dave@example.com>
+Subject: =?UTF-8?q?Caf=C3=A9?=
+Status: O
+
+Body
+`
+
+// tempMaildir creates a directory with a Maildir folder with messages in new and cur.
+func tempMaildir(t *testing.T) string {
+	root, err := ioutil.TempDir("", "maildir")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(root, "Work")
+	for _, d := range []string{"new", "cur", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	messages := map[string]string{
+		"new/1.M1.host":          "Message-ID: <1@example.com>\nDate: Wed, 01 Jan 2020 10:00:00 +0000\nFrom: Alice <alice@example.com>\nSubject: New\n\nBody",
+		"cur/2.M2.host:2,":       "From: bob@example.com\nSubject: Unseen\n\nBody",
+		"cur/3.M3.host:2,FS":     "From: Carol <carol@example.com>\nSubject: Seen\n\nBody",
+		"cur/4.M4.host,U=4:2,RS": "Subject: Replied\n\nBody",
+		"tmp/5.M5.host":          "Subject: Delivering\n\nBody",
+		"new/.hidden":            "Subject: Hidden\n\nBody",
+	}
+	for name, content := range messages {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// tempMbox creates a directory with a mbox file.
+func tempMbox(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "mbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "inbox")
+	if err := ioutil.WriteFile(path, []byte(testMbox), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestUnreadMails(t *testing.T) {
+	maildir := tempMaildir(t)
+	defer os.RemoveAll(filepath.Dir(maildir))
+	mbox := tempMbox(t)
+	defer os.RemoveAll(filepath.Dir(mbox))
+
+	tt := []struct {
+		paths []string
+		want  []tin.Mail
+	}{
+		{
+			paths: []string{maildir},
+			want: []tin.Mail{
+				{ID: "1@example.com", From: "Alice", Subject: "New", Date: time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC), Labels: []string{"Work"}},
+				{From: "bob@example.com", Subject: "Unseen", Labels: []string{"Work"}},
+			},
+		},
+		{
+			paths: []string{mbox},
+			want: []tin.Mail{
+				{From: "Alice", Subject: "Unread", Labels: []string{"inbox"}},
+				{From: "Davé", Subject: "Café", Labels: []string{"inbox"}},
+			},
+		},
+		{
+			paths: []string{maildir, mbox},
+			want: []tin.Mail{
+				{ID: "1@example.com", From: "Alice", Subject: "New", Date: time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC), Labels: []string{"Work"}},
+				{From: "bob@example.com", Subject: "Unseen", Labels: []string{"Work"}},
+				{From: "Alice", Subject: "Unread", Labels: []string{"inbox"}},
+				{From: "Davé", Subject: "Café", Labels: []string{"inbox"}},
+			},
+		},
+		{
+			paths: []string{},
+			want:  []tin.Mail{},
+		},
+	}
+
+	for _, tc := range tt {
+		got, err := NewService(tc.paths).UnreadMails()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range got {
+			got[i].Date = got[i].Date.UTC()
+		}
+
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("want %v, got %v", tc.want, got)
+		}
+	}
+}
+
+func TestUnreadMailsError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "maildir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tt := [][]string{
+		{filepath.Join(dir, "missing")},
+		{dir}, // A directory without new and cur isn't a Maildir folder.
+	}
+
+	for _, paths := range tt {
+		got, err := NewService(paths).UnreadMails()
+		if err == nil {
+			t.Errorf("want error, got nil")
+		}
+		if len(got) != 0 {
+			t.Errorf("want %v, got %v", 0, len(got))
+		}
+	}
+}
+
+func TestMaildirSeen(t *testing.T) {
+	tt := []struct {
+		name string
+		want bool
+	}{
+		{name: "1.M1.host", want: false},
+		{name: "1.M1.host:2,", want: false},
+		{name: "1.M1.host:2,FR", want: false},
+		{name: "1.M1.host,U=1:2,FRS", want: true},
+		{name: "1.M1.host:2,S", want: true},
+	}
+
+	for _, tc := range tt {
+		if got := maildirSeen(tc.name); got != tc.want {
+			t.Errorf("%v: want %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+// waitEvent waits for a value of the channel.
+func waitEvent(t *testing.T, events <-chan struct{}) {
+	t.Helper()
+
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("want event, got timeout")
+	}
+}
+
+// drainEvents receives the pending value of the channel.
+func drainEvents(events <-chan struct{}) {
+	select {
+	case <-events:
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWatch(t *testing.T) {
+	maildir := tempMaildir(t)
+	defer os.RemoveAll(filepath.Dir(maildir))
+	mbox := tempMbox(t)
+	defer os.RemoveAll(filepath.Dir(mbox))
+
+	events, err := NewService([]string{maildir, mbox}).Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Delivery of a message.
+	if err := os.Rename(filepath.Join(maildir, "tmp", "5.M5.host"), filepath.Join(maildir, "new", "5.M5.host")); err != nil {
+		t.Fatal(err)
+	}
+	waitEvent(t, events)
+	drainEvents(events)
+
+	// Reading a message.
+	if err := os.Rename(filepath.Join(maildir, "cur", "2.M2.host:2,"), filepath.Join(maildir, "cur", "2.M2.host:2,S")); err != nil {
+		t.Fatal(err)
+	}
+	waitEvent(t, events)
+	drainEvents(events)
+
+	// Other files of the directory of the mbox file are ignored.
+	if err := ioutil.WriteFile(filepath.Join(filepath.Dir(mbox), "other"), []byte{}, 0600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-events:
+		t.Fatal("want no event, got event")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := ioutil.WriteFile(mbox, []byte(testMbox+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	waitEvent(t, events)
+}
+
+func TestWatchError(t *testing.T) {
+	_, err := NewService([]string{"/nonexistent/inbox"}).Watch()
+	if err == nil {
+		t.Errorf("want error, got nil")
+	}
+}
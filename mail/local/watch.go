@@ -0,0 +1,123 @@
+package local
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+// Represents the inotify events of changes of the Maildir folders and the mbox files.
+//
+// A Maildir message is moved from new to cur and renamed when its flags
+// change, a mbox file is written or replaced.
+const (
+	maildirEvents = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+	mboxEvents    = syscall.IN_CLOSE_WRITE | syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+)
+
+// Watch returns a channel that receives a value when a Maildir folder or mbox file changes.
+//
+// The new and cur directories of the Maildir folders are watched with inotify,
+// and the directories of the mbox files because they are often replaced.
+func (s *Service) Watch() (<-chan struct{}, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+
+	// The names of the mbox files by watch descriptor, events of other files are ignored.
+	names := map[int32]map[string]bool{}
+	watch := func(path string, mask uint32) (int32, error) {
+		wd, err := syscall.InotifyAddWatch(fd, path, mask)
+		if err != nil {
+			return 0, fmt.Errorf("failed watching %v: %w", path, os.NewSyscallError("inotify_add_watch", err))
+		}
+		return int32(wd), nil
+	}
+
+	for _, path := range s.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			syscall.Close(fd)
+			return nil, err
+		}
+
+		if info.IsDir() {
+			for _, dir := range []string{"new", "cur"} {
+				if _, err := watch(filepath.Join(path, dir), maildirEvents); err != nil {
+					syscall.Close(fd)
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		wd, err := watch(filepath.Dir(path), mboxEvents)
+		if err != nil {
+			syscall.Close(fd)
+			return nil, err
+		}
+		if names[wd] == nil {
+			names[wd] = map[string]bool{}
+		}
+		names[wd][filepath.Base(path)] = true
+	}
+
+	ch := make(chan struct{}, 1)
+	go func() {
+		defer close(ch)
+		defer syscall.Close(fd)
+
+		buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+		for {
+			n, err := syscall.Read(fd, buf)
+			switch {
+			case err == syscall.EINTR:
+				continue
+			case err != nil || n <= 0:
+				return
+			}
+
+			if isMailChange(buf[:n], names) {
+				notify(ch)
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// isMailChange reports whether the inotify events contain a change of a
+// Maildir folder or of one of the mbox files.
+func isMailChange(data []byte, names map[int32]map[string]bool) bool {
+	for len(data) >= syscall.SizeofInotifyEvent {
+		e := (*syscall.InotifyEvent)(unsafe.Pointer(&data[0]))
+		end := syscall.SizeofInotifyEvent + int(e.Len)
+		if end > len(data) {
+			return false
+		}
+
+		if e.Mask&syscall.IN_Q_OVERFLOW != 0 {
+			// The queue overflowed, events were lost.
+			return true
+		}
+
+		name := string(bytes.TrimRight(data[syscall.SizeofInotifyEvent:end], "\x00"))
+		if files, ok := names[e.Wd]; !ok || files[name] {
+			return true
+		}
+		data = data[end:]
+	}
+	return false
+}
+
+// notify sends a value to the channel unless it already has a pending value.
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
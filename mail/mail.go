@@ -0,0 +1,30 @@
+// Package mail contains the helpers the mail providers share.
+package mail
+
+import (
+	"mime"
+	"net/mail"
+	"strings"
+)
+
+// Sender returns the name of the address of a From header, or the address
+// when it has no name. The encoded words of the name are decoded.
+func Sender(v string) string {
+	a, err := mail.ParseAddress(v)
+	if err != nil {
+		return DecodeHeader(v)
+	}
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.Address
+}
+
+// DecodeHeader returns the header value of which the encoded words are decoded.
+func DecodeHeader(v string) string {
+	d := &mime.WordDecoder{}
+	if s, err := d.DecodeHeader(v); err == nil {
+		v = s
+	}
+	return strings.TrimSpace(v)
+}
@@ -0,0 +1,29 @@
+package mail
+
+import "testing"
+
+func TestSender(t *testing.T) {
+	tt := []struct {
+		v    string
+		want string
+	}{
+		{v: "Alice <alice@example.com>", want: "Alice"},
+		{v: "bob@example.com", want: "bob@example.com"},
+		{v: "=?UTF-8?q?Jos=C3=A9?= <jose@example.com>", want: "José"},
+		{v: " =?UTF-8?q?Caf=C3=A9?= (invalid ", want: "Café (invalid"},
+	}
+
+	for _, tc := range tt {
+		if got := Sender(tc.v); got != tc.want {
+			t.Errorf("want %v, got %v", tc.want, got)
+		}
+	}
+}
+
+func TestDecodeHeader(t *testing.T) {
+	want := "Café"
+	got := DecodeHeader(" =?UTF-8?q?Caf=C3=A9?= ")
+	if got != want {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
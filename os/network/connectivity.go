@@ -0,0 +1,159 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+// DefaultConnectivityTargets are the targets probed by the connectivity checker.
+var DefaultConnectivityTargets = []string{
+	"http://connectivitycheck.gstatic.com/generate_204",
+	"tcp://1.1.1.1:443",
+	"dns://archlinux.org",
+}
+
+// portalBodyLimit is the number of bytes of the response body that are read
+// to tell an empty response from a captive portal.
+const portalBodyLimit = 512
+
+// NewConnectivityChecker returns a tin.ConnectivityChecker.
+//
+// A target is an URL of which the scheme is the kind of probe:
+// tcp://host:port connects to the port, http:// and https:// expect an
+// empty response with status 204 and dns://host resolves the host.
+// DefaultConnectivityTargets are used when the targets are empty.
+// If none of the targets is valid it will return nil.
+func NewConnectivityChecker(targets []string) tin.ConnectivityChecker {
+	if len(targets) == 0 {
+		targets = DefaultConnectivityTargets
+	}
+
+	c := &connectivityChecker{timeout: 5 * time.Second}
+	for _, t := range targets {
+		u, err := url.Parse(t)
+		if err != nil || u.Host == "" {
+			continue
+		}
+
+		switch u.Scheme {
+		case "tcp", "http", "https", "dns":
+			c.targets = append(c.targets, u)
+		}
+	}
+
+	if len(c.targets) == 0 {
+		return nil
+	}
+	return c
+}
+
+// connectivityChecker implements tin.ConnectivityChecker.
+type connectivityChecker struct {
+	timeout time.Duration
+	targets []*url.URL
+}
+
+// Check returns the results of probing the targets in order.
+//
+// The targets are probed concurrently, a probe fails when it exceeds the timeout.
+func (c *connectivityChecker) Check() ([]tin.ProbeResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	results := make([]tin.ProbeResult, len(c.targets))
+	for i, t := range c.targets {
+		wg.Add(1)
+		go func(i int, t *url.URL) {
+			defer wg.Done()
+			results[i] = c.probe(ctx, t)
+		}(i, t)
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// probe returns the result of probing the target.
+func (c *connectivityChecker) probe(ctx context.Context, target *url.URL) tin.ProbeResult {
+	start := time.Now()
+	var portal bool
+	var err error
+	switch target.Scheme {
+	case "tcp":
+		err = probeTCP(ctx, target.Host)
+	case "dns":
+		err = probeDNS(ctx, target.Hostname())
+	default:
+		portal, err = probeHTTP(ctx, target.String())
+	}
+
+	r := tin.ProbeResult{Target: target.String(), Portal: portal}
+	if err == nil && !portal {
+		r.Success = true
+		r.Latency = time.Since(start)
+	}
+	return r
+}
+
+// probeTCP connects to the address.
+func probeTCP(ctx context.Context, address string) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// probeDNS resolves the host.
+func probeDNS(ctx context.Context, host string) error {
+	_, err := net.DefaultResolver.LookupHost(ctx, host)
+	return err
+}
+
+// probeHTTP requests the URL and reports whether it's answered by a captive portal.
+//
+// The URL is expected to respond with status 204 and an empty body, captive
+// portals typically redirect or respond with a login page instead. Redirects
+// aren't followed.
+func probeHTTP(ctx context.Context, url string) (bool, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	data, err := readAll(io.LimitReader(resp.Body, portalBodyLimit))
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNoContent && len(data) == 0:
+		return false, nil
+	case resp.StatusCode < http.StatusBadRequest:
+		return true, nil
+	default:
+		return false, fmt.Errorf("unexpected status %v", resp.Status)
+	}
+}
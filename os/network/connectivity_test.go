@@ -0,0 +1,91 @@
+package network
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+func TestNewConnectivityChecker(t *testing.T) {
+	if got := NewConnectivityChecker(nil).(*connectivityChecker); len(got.targets) != len(DefaultConnectivityTargets) {
+		t.Errorf("want %v, got %v", len(DefaultConnectivityTargets), len(got.targets))
+	}
+
+	if got := NewConnectivityChecker([]string{"ftp://example.com", "1.1.1.1:443"}); got != nil {
+		t.Errorf("want %v, got %v", nil, got)
+	}
+}
+
+func TestConnectivityCheck(t *testing.T) {
+	online := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer online.Close()
+
+	portal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("<html>Login</html>"))
+	}))
+	defer portal.Close()
+
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, "http://portal.example.com/login", http.StatusFound)
+	}))
+	defer redirect.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// Reserving a port and closing it, connecting to it is refused.
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed.Close()
+
+	tests := []struct {
+		target string
+		want   tin.ProbeResult
+	}{
+		{target: online.URL, want: tin.ProbeResult{Success: true}},
+		{target: portal.URL, want: tin.ProbeResult{Portal: true}},
+		{target: redirect.URL, want: tin.ProbeResult{Portal: true}},
+		{target: failing.URL, want: tin.ProbeResult{}},
+		{target: "tcp://" + listener.Addr().String(), want: tin.ProbeResult{Success: true}},
+		{target: "tcp://" + closed.Addr().String(), want: tin.ProbeResult{}},
+		{target: "dns://localhost", want: tin.ProbeResult{Success: true}},
+	}
+
+	targets := []string{}
+	for _, tt := range tests {
+		targets = append(targets, tt.target)
+	}
+
+	c := NewConnectivityChecker(targets).(*connectivityChecker)
+	c.timeout = 2 * time.Second
+	got, err := c.Check()
+	if err != nil {
+		t.Fatalf("want %v, got %v", nil, err)
+	}
+
+	for i, tt := range tests {
+		r := got[i]
+		if r.Target != tt.target || r.Success != tt.want.Success || r.Portal != tt.want.Portal {
+			t.Errorf("%v: want %v, got %v", tt.target, tt.want, r)
+		}
+		if r.Success && r.Latency <= 0 {
+			t.Errorf("%v: want a latency, got %v", tt.target, r.Latency)
+		}
+	}
+}
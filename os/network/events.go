@@ -0,0 +1,102 @@
+package network
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+var netlinkSocket = syscall.Socket
+
+// Represents the multicast groups of rtnetlink, of which the syscall package
+// only defines a few.
+const (
+	rtmgrpLink       = 0x1
+	rtmgrpIPv4IfAddr = 0x10
+	rtmgrpIPv4Route  = 0x40
+	rtmgrpIPv6IfAddr = 0x100
+	rtmgrpIPv6Route  = 0x400
+)
+
+// netlinkGroups are the multicast groups of the link, address and route changes.
+const netlinkGroups = rtmgrpLink | rtmgrpIPv4IfAddr | rtmgrpIPv4Route | rtmgrpIPv6IfAddr | rtmgrpIPv6Route
+
+// NewEventSource returns a tin.NetworkEventSource.
+func NewEventSource() tin.NetworkEventSource {
+	return &netlinkEventSource{}
+}
+
+// netlinkEventSource implements tin.NetworkEventSource.
+type netlinkEventSource struct{}
+
+// Events returns a channel that receives a value when the links, addresses or routes change.
+//
+// It listens to the route netlink socket of the kernel. Events that arrive
+// while the previous one isn't received yet are merged.
+func (n *netlinkEventSource) Events() (<-chan struct{}, error) {
+	fd, err := netlinkSocket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.NETLINK_ROUTE)
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+
+	sa := &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: netlinkGroups}
+	if err := syscall.Bind(fd, sa); err != nil {
+		syscall.Close(fd)
+		return nil, os.NewSyscallError("bind", err)
+	}
+
+	ch := make(chan struct{}, 1)
+	go func() {
+		defer close(ch)
+		defer syscall.Close(fd)
+
+		buf := make([]byte, os.Getpagesize())
+		for {
+			nr, _, err := syscall.Recvfrom(fd, buf, 0)
+			switch {
+			case err == syscall.EINTR:
+				continue
+			case err == syscall.ENOBUFS:
+				// The socket buffer overflowed, events were lost.
+				notify(ch)
+				continue
+			case err != nil:
+				return
+			}
+
+			if isNetworkChange(buf[:nr]) {
+				notify(ch)
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// notify sends a value to the channel unless it already has a pending value.
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// isNetworkChange reports whether the netlink messages contain a change of
+// a link, address or route.
+func isNetworkChange(data []byte) bool {
+	msgs, err := syscall.ParseNetlinkMessage(data)
+	if err != nil {
+		return false
+	}
+
+	for _, m := range msgs {
+		switch m.Header.Type {
+		case syscall.RTM_NEWLINK, syscall.RTM_DELLINK,
+			syscall.RTM_NEWADDR, syscall.RTM_DELADDR,
+			syscall.RTM_NEWROUTE, syscall.RTM_DELROUTE:
+			return true
+		}
+	}
+	return false
+}
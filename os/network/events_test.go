@@ -0,0 +1,54 @@
+package network
+
+import (
+	"encoding/binary"
+	"syscall"
+	"testing"
+)
+
+// netlinkMessage returns a netlink message of the type without payload.
+func netlinkMessage(t uint16) []byte {
+	b := make([]byte, syscall.NLMSG_HDRLEN)
+	binary.LittleEndian.PutUint32(b[0:4], syscall.NLMSG_HDRLEN)
+	binary.LittleEndian.PutUint16(b[4:6], t)
+	return b
+}
+
+func TestIsNetworkChange(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want bool
+	}{
+		{data: netlinkMessage(syscall.RTM_NEWLINK), want: true},
+		{data: netlinkMessage(syscall.RTM_DELADDR), want: true},
+		{data: append(netlinkMessage(syscall.NLMSG_NOOP), netlinkMessage(syscall.RTM_NEWROUTE)...), want: true},
+		{data: netlinkMessage(syscall.RTM_NEWNEIGH), want: false},
+		{data: []byte{1, 2, 3}, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isNetworkChange(tt.data); got != tt.want {
+			t.Errorf("want %v, got %v", tt.want, got)
+		}
+	}
+}
+
+func TestEventsSocketError(t *testing.T) {
+	netlinkSocket = func(domain, typ, proto int) (int, error) { return -1, syscall.EPROTONOSUPPORT }
+	defer func() { netlinkSocket = syscall.Socket }()
+
+	_, err := NewEventSource().Events()
+	if err == nil {
+		t.Errorf("want %v, got %v", "error", err)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	ch := make(chan struct{}, 1)
+	notify(ch)
+	notify(ch)
+
+	if got := len(ch); got != 1 {
+		t.Errorf("want %v, got %v", 1, got)
+	}
+}
@@ -0,0 +1,268 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+var fileModTime = func(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// geoIPResponseLimit is the maximum number of bytes read of a response.
+const geoIPResponseLimit = 64 << 10
+
+// NewHTTPEnricher returns a tin.IPEnricher that requests the details from a JSON API.
+//
+// The {ip} of the endpoint is replaced by the IP address. The fields of the
+// common APIs are recognized, for example ipinfo.io and ip-api.com.
+// If the endpoint isn't an URL it will return nil.
+func NewHTTPEnricher(endpoint string) tin.IPEnricher {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil
+	}
+
+	return &httpEnricher{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// httpEnricher implements tin.IPEnricher.
+type httpEnricher struct {
+	endpoint string
+	client   *http.Client
+}
+
+// Enrich returns the tin.IPDetails of the IP address.
+func (e *httpEnricher) Enrich(ip net.IP) (tin.IPDetails, error) {
+	endpoint := strings.Replace(e.endpoint, "{ip}", url.PathEscape(ip.String()), -1)
+	resp, err := e.client.Get(endpoint)
+	if err != nil {
+		return tin.IPDetails{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return tin.IPDetails{}, fmt.Errorf("unexpected status %v", resp.Status)
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, geoIPResponseLimit)).Decode(&fields); err != nil {
+		return tin.IPDetails{}, err
+	}
+
+	return parseGeoIPFields(fields), nil
+}
+
+// parseGeoIPFields returns the tin.IPDetails of the fields of a JSON response.
+//
+// The country is either the name or the code depending on the API, the
+// autonomous system is often combined with the name of the ISP.
+// Example of an autonomous system: AS15169 Google LLC
+func parseGeoIPFields(fields map[string]interface{}) tin.IPDetails {
+	str := func(keys ...string) string {
+		for _, k := range keys {
+			if v, ok := fields[k].(string); ok && v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	d := tin.IPDetails{
+		Country:     str("country_name"),
+		CountryCode: str("country_code", "countryCode"),
+		City:        str("city"),
+		ISP:         str("isp"),
+	}
+
+	if country := str("country"); len(country) == 2 && d.CountryCode == "" {
+		d.CountryCode = strings.ToUpper(country)
+	} else if len(country) > 2 && d.Country == "" {
+		d.Country = country
+	}
+
+	switch v := fields["asn"].(type) {
+	case float64:
+		d.ASN = uint(v)
+	case string:
+		d.ASN, _ = parseASN(v)
+	}
+
+	for _, k := range []string{"as", "org"} {
+		v, ok := fields[k].(string)
+		if !ok {
+			continue
+		}
+
+		asn, name := parseASN(v)
+		if d.ASN == 0 {
+			d.ASN = asn
+		}
+		if d.ISP == "" {
+			d.ISP = name
+		}
+	}
+	return d
+}
+
+// parseASN returns the number and name of an autonomous system.
+//
+// Example of a value: AS15169 Google LLC
+func parseASN(v string) (uint, string) {
+	f := strings.SplitN(strings.TrimSpace(v), " ", 2)
+	if !strings.HasPrefix(strings.ToUpper(f[0]), "AS") {
+		return 0, strings.TrimSpace(v)
+	}
+
+	asn, err := strconv.ParseUint(f[0][2:], 10, 32)
+	if err != nil {
+		return 0, strings.TrimSpace(v)
+	}
+	if len(f) == 1 {
+		return uint(asn), ""
+	}
+	return uint(asn), strings.TrimSpace(f[1])
+}
+
+// NewMMDBEnricher returns a tin.IPEnricher that looks up the details in
+// MaxMind DB files, for example GeoLite2-City.mmdb and GeoLite2-ASN.mmdb.
+//
+// The details of the databases are combined. A database is read again when
+// it's modified, for example by geoipupdate.
+// If there are no databases it will return nil.
+func NewMMDBEnricher(paths []string) tin.IPEnricher {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	return &mmdbEnricher{paths: paths, readers: map[string]*mmdbFile{}}
+}
+
+// mmdbFile represents a read MaxMind DB file.
+type mmdbFile struct {
+	reader  *mmdbReader
+	modTime time.Time
+}
+
+// mmdbEnricher implements tin.IPEnricher.
+type mmdbEnricher struct {
+	paths   []string
+	mutex   sync.Mutex
+	readers map[string]*mmdbFile
+}
+
+// Enrich returns the tin.IPDetails of the IP address.
+func (e *mmdbEnricher) Enrich(ip net.IP) (tin.IPDetails, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	d := tin.IPDetails{}
+	for _, path := range e.paths {
+		r, err := e.reader(path)
+		if err != nil {
+			return tin.IPDetails{}, err
+		}
+
+		v, err := r.lookup(ip)
+		if err != nil {
+			return tin.IPDetails{}, fmt.Errorf("failed reading %v: %w", path, err)
+		}
+
+		record, _ := v.(map[string]interface{})
+		mergeIPDetails(&d, parseMMDBRecord(record))
+	}
+	return d, nil
+}
+
+// reader returns the reader of the database, it's read again when it's modified.
+func (e *mmdbEnricher) reader(path string) (*mmdbReader, error) {
+	modTime, err := fileModTime(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if f, ok := e.readers[path]; ok && f.modTime.Equal(modTime) {
+		return f.reader, nil
+	}
+
+	data, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := newMMDBReader(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading %v: %w", path, err)
+	}
+
+	e.readers[path] = &mmdbFile{reader: r, modTime: modTime}
+	return r, nil
+}
+
+// parseMMDBRecord returns the tin.IPDetails of a record of the GeoIP2 or GeoLite2 databases.
+func parseMMDBRecord(record map[string]interface{}) tin.IPDetails {
+	path := func(keys ...string) interface{} {
+		var v interface{} = record
+		for _, k := range keys {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			v = m[k]
+		}
+		return v
+	}
+	str := func(keys ...string) string {
+		v, _ := path(keys...).(string)
+		return v
+	}
+
+	d := tin.IPDetails{
+		Country:     str("country", "names", "en"),
+		CountryCode: str("country", "iso_code"),
+		City:        str("city", "names", "en"),
+		ASN:         uint(toUint(path("autonomous_system_number"))),
+		ISP:         str("isp"),
+	}
+	if d.ISP == "" {
+		d.ISP = str("autonomous_system_organization")
+	}
+	return d
+}
+
+// mergeIPDetails sets the empty fields of the details to the fields of the other details.
+func mergeIPDetails(d *tin.IPDetails, o tin.IPDetails) {
+	if d.Country == "" {
+		d.Country = o.Country
+	}
+	if d.CountryCode == "" {
+		d.CountryCode = o.CountryCode
+	}
+	if d.City == "" {
+		d.City = o.City
+	}
+	if d.ASN == 0 {
+		d.ASN = o.ASN
+	}
+	if d.ISP == "" {
+		d.ISP = o.ISP
+	}
+}
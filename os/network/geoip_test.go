@@ -0,0 +1,102 @@
+package network
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+func TestNewHTTPEnricher(t *testing.T) {
+	if got := NewHTTPEnricher("https://ipinfo.io/{ip}/json"); got == nil {
+		t.Errorf("want %v, got %v", "tin.IPEnricher", got)
+	}
+
+	for _, endpoint := range []string{"", "ipinfo.io", "ftp://ipinfo.io/{ip}"} {
+		if got := NewHTTPEnricher(endpoint); got != nil {
+			t.Errorf("want %v, got %v", nil, got)
+		}
+	}
+}
+
+func TestHTTPEnricher(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/203.0.113.7/json" {
+			http.NotFound(w, req)
+			return
+		}
+		w.Write([]byte(`{"ip":"203.0.113.7","city":"Amsterdam","country":"NL","org":"AS64500 Example ISP"}`))
+	}))
+	defer testServer.Close()
+
+	want := tin.IPDetails{CountryCode: "NL", City: "Amsterdam", ASN: 64500, ISP: "Example ISP"}
+
+	e := NewHTTPEnricher(testServer.URL + "/{ip}/json")
+	got, err := e.Enrich(net.ParseIP("203.0.113.7"))
+	if err != nil || got != want {
+		t.Errorf("want %v, got %v, %v", want, got, err)
+	}
+
+	if _, err := e.Enrich(net.ParseIP("198.51.100.1")); err == nil {
+		t.Errorf("want %v, got %v", "error", err)
+	}
+}
+
+func TestParseGeoIPFields(t *testing.T) {
+	tests := []struct {
+		fields map[string]interface{}
+		want   tin.IPDetails
+	}{
+		{
+			// ip-api.com
+			fields: map[string]interface{}{"country": "Netherlands", "countryCode": "NL", "city": "Amsterdam", "isp": "Example ISP", "as": "AS64500 Example Holding"},
+			want:   tin.IPDetails{Country: "Netherlands", CountryCode: "NL", City: "Amsterdam", ASN: 64500, ISP: "Example ISP"},
+		},
+		{
+			fields: map[string]interface{}{"country_name": "Netherlands", "country_code": "NL", "asn": float64(64500), "org": "Example ISP"},
+			want:   tin.IPDetails{Country: "Netherlands", CountryCode: "NL", ASN: 64500, ISP: "Example ISP"},
+		},
+		{
+			fields: map[string]interface{}{"asn": "AS64500"},
+			want:   tin.IPDetails{ASN: 64500},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := parseGeoIPFields(tt.fields); got != tt.want {
+			t.Errorf("want %v, got %v", tt.want, got)
+		}
+	}
+}
+
+func TestMMDBEnricher(t *testing.T) {
+	e := NewMMDBEnricher([]string{"testdata/geo.mmdb"})
+
+	want := tin.IPDetails{Country: "Netherlands", CountryCode: "NL", City: "Amsterdam", ASN: 64500, ISP: "Example ISP"}
+	got, err := e.Enrich(net.ParseIP("203.0.113.7"))
+	if err != nil || got != want {
+		t.Errorf("want %v, got %v, %v", want, got, err)
+	}
+
+	for _, ip := range []string{"198.51.100.1", "2001:db8::1"} {
+		got, err := e.Enrich(net.ParseIP(ip))
+		if err != nil || got != (tin.IPDetails{}) {
+			t.Errorf("want %v, got %v, %v", tin.IPDetails{}, got, err)
+		}
+	}
+}
+
+func TestMMDBEnricherError(t *testing.T) {
+	if got := NewMMDBEnricher(nil); got != nil {
+		t.Errorf("want %v, got %v", nil, got)
+	}
+
+	for _, path := range []string{"testdata/missing", "testdata/route"} {
+		e := NewMMDBEnricher([]string{path})
+		if _, err := e.Enrich(net.ParseIP("203.0.113.7")); err == nil {
+			t.Errorf("want %v, got %v", "error", err)
+		}
+	}
+}
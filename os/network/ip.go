@@ -3,10 +3,13 @@ package network
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
-	"sync"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/sjengpho/tin/tin"
@@ -17,80 +20,285 @@ var readAll = ioutil.ReadAll
 // ErrTimeout means the IP lookup has timed out.
 var ErrTimeout = errors.New("ip lookup: timed out")
 
+// ErrNoAnswer means none of the sources answered with an IP address.
+var ErrNoAnswer = errors.New("ip lookup: no answer")
+
+// ErrNoQuorum means not enough of the sources agree on the IP address.
+var ErrNoQuorum = errors.New("ip lookup: no quorum")
+
+// DefaultPublicIPSources are the sources of the public IP lookup.
+var DefaultPublicIPSources = []string{
+	"https://ifconfig.me/ip",
+	"https://ifconfig.co/ip",
+	"https://api64.ipify.org",
+	"dns://resolver1.opendns.com/myip.opendns.com",
+}
+
+// ipResponseLimit is the maximum number of bytes read of a response.
+const ipResponseLimit = 64
+
+// Represents the address families of the public IP lookup.
+const (
+	ipv4 = "4"
+	ipv6 = "6"
+)
+
 // NewPublicIPLookup returns a tin.PublicIPLookup.
-func NewPublicIPLookup() tin.PublicIPLookup {
-	return &ipLookupper{
-		timeout: 10 * time.Second,
-		sources: []string{
-			"https://ifconfig.me/ip",
-			"https://ifconfig.co/ip",
-			"https://api.ipify.org",
-		},
+//
+// A source is an URL of which the response body is the IP address, or a
+// dns://resolver/name URL of which the address records of the name are
+// the IP address, for example dns://resolver1.opendns.com/myip.opendns.com.
+// DefaultPublicIPSources are used when the sources are empty.
+// The quorum is the number of sources that have to agree on the IP address,
+// the first answer is used when it's less than 2.
+// If none of the sources is valid it will return nil.
+func NewPublicIPLookup(sources []string, quorum int) tin.PublicIPLookup {
+	if len(sources) == 0 {
+		sources = DefaultPublicIPSources
+	}
+	if quorum < 1 {
+		quorum = 1
+	}
+
+	l := &ipLookupper{timeout: 10 * time.Second, quorum: quorum}
+	for _, s := range sources {
+		if source, err := newIPSource(s); err == nil {
+			l.sources = append(l.sources, source)
+		}
+	}
+
+	if len(l.sources) == 0 {
+		return nil
+	}
+	return l
+}
+
+// ipSource is the interface implemented by an object that can
+// lookup the public IP address of an address family.
+type ipSource interface {
+	lookup(ctx context.Context, family string) (net.IP, error)
+	String() string
+}
+
+// newIPSource returns the ipSource of the URL.
+func newIPSource(source string) (ipSource, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case u.Host == "":
+		return nil, fmt.Errorf("invalid source %v", source)
+	case u.Scheme == "http" || u.Scheme == "https":
+		return httpIPSource(source), nil
+	case u.Scheme == "dns" && strings.Trim(u.Path, "/") != "":
+		return dnsIPSource{resolver: u.Host, name: strings.Trim(u.Path, "/")}, nil
+	default:
+		return nil, fmt.Errorf("invalid source %v", source)
 	}
 }
 
 // ipLookupper implements tin.PublicIPLookup.
 type ipLookupper struct {
 	timeout time.Duration
-	sources []string
+	quorum  int
+	sources []ipSource
 }
 
 // Lookup returns a tin.PublicIP.
+//
+// The IPv4 and IPv6 addresses are looked up separately, a network without
+// IPv6 only has an IPv4 address. It fails when neither is found.
 func (i *ipLookupper) Lookup() (tin.PublicIP, error) {
-	ctx := context.Background()
-	ctx, cancel := context.WithTimeout(ctx, i.timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), i.timeout)
 	defer cancel()
 
-	// Requesting the IP address from the sources.
-	var wg sync.WaitGroup
-	ch := make(chan net.IP, 1)
-	for _, s := range i.sources {
-		wg.Add(1)
-		go i.lookup(ctx, s, ch, &wg)
+	type answer struct {
+		ip      net.IP
+		sources []string
+		err     error
 	}
-
-	// Cleaning up channel.
+	ch4 := make(chan answer, 1)
+	ch6 := make(chan answer, 1)
+	go func() {
+		ip, sources, err := i.lookup(ctx, ipv4)
+		ch4 <- answer{ip, sources, err}
+	}()
 	go func() {
-		wg.Wait()
-		close(ch)
+		ip, sources, err := i.lookup(ctx, ipv6)
+		ch6 <- answer{ip, sources, err}
 	}()
+	a4, a6 := <-ch4, <-ch6
+
+	if a4.err != nil && a6.err != nil {
+		return tin.PublicIP{}, a4.err
+	}
+
+	return tin.PublicIP{
+		IPv4:        a4.ip,
+		IPv6:        a6.ip,
+		IPv4Sources: a4.sources,
+		IPv6Sources: a6.sources,
+	}, nil
+}
+
+// lookup returns the IP address of the address family and the sources that answered it.
+//
+// The sources are requested concurrently, the IP address is returned as soon
+// as the quorum of the sources agrees on it.
+func (i *ipLookupper) lookup(ctx context.Context, family string) (net.IP, []string, error) {
+	type answer struct {
+		source string
+		ip     net.IP
+	}
+	ch := make(chan answer, len(i.sources))
+	for _, s := range i.sources {
+		go func(s ipSource) {
+			ip, err := s.lookup(ctx, family)
+			if err != nil || !isFamily(ip, family) {
+				ip = nil
+			}
+			ch <- answer{source: s.String(), ip: ip}
+		}(s)
+	}
+
+	votes := map[string][]string{}
+	answered := false
+	for range i.sources {
+		select {
+		case a := <-ch:
+			if a.ip == nil {
+				continue
+			}
 
-	// Cancelling the other requests when one of the sources
-	// has returned a result. When the deadline has exceeded and none of the
-	// sources has returned a result an error is returned.
-	select {
-	case i := <-ch:
-		return tin.PublicIP{IP: i}, nil
-	case <-ctx.Done():
-		return tin.PublicIP{}, ErrTimeout
+			answered = true
+			key := a.ip.String()
+			votes[key] = append(votes[key], a.source)
+			if len(votes[key]) >= i.quorum {
+				return a.ip, votes[key], nil
+			}
+		case <-ctx.Done():
+			return nil, nil, ErrTimeout
+		}
 	}
+
+	if answered {
+		return nil, nil, ErrNoQuorum
+	}
+	return nil, nil, ErrNoAnswer
 }
 
-// lookup fetches the IP address and sends the result to the channel.
-func (i *ipLookupper) lookup(ctx context.Context, url string, ch chan net.IP, wg *sync.WaitGroup) {
-	defer wg.Done()
+// isFamily reports whether the IP address is of the address family.
+func isFamily(ip net.IP, family string) bool {
+	if ip == nil {
+		return false
+	}
+	if family == ipv4 {
+		return ip.To4() != nil
+	}
+	return ip.To4() == nil
+}
+
+// familyTransports are the transports of the HTTP sources by address family,
+// they are shared by the lookups so idle connections are reused and closed.
+var familyTransports = map[string]*http.Transport{
+	ipv4: newFamilyTransport(ipv4),
+	ipv6: newFamilyTransport(ipv6),
+}
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+// newFamilyTransport returns a http.Transport that only connects over the address family.
+//
+// The lookups run every minute, idle connections are closed before the next one.
+func newFamilyTransport(family string) *http.Transport {
+	return &http.Transport{
+		DialContext:     familyDialer(family),
+		IdleConnTimeout: 30 * time.Second,
+	}
+}
+
+// familyDialer returns a dial function that only connects over the address family.
+func familyDialer(family string) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, network+family, address)
+	}
+}
+
+// httpIPSource implements ipSource.
+//
+// The source is the URL of which the response body is the IP address.
+type httpIPSource string
+
+// lookup requests the IP address over the address family.
+func (s httpIPSource) lookup(ctx context.Context, family string) (net.IP, error) {
+	req, err := http.NewRequest(http.MethodGet, string(s), nil)
 	if err != nil {
-		return
+		return nil, err
 	}
 	req = req.WithContext(ctx)
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil || resp.StatusCode != http.StatusOK {
-		return
+	client := &http.Client{Transport: familyTransports[family]}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
 	}
-
 	defer resp.Body.Close()
-	data, err := readAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %v", resp.Status)
+	}
+
+	data, err := readAll(io.LimitReader(resp.Body, ipResponseLimit))
 	if err != nil {
-		return
+		return nil, err
 	}
 
-	ip := net.ParseIP(string(data))
+	ip := net.ParseIP(strings.TrimSpace(string(data)))
 	if ip == nil {
-		return
+		return nil, fmt.Errorf("invalid IP address %q", data)
 	}
+	return ip, nil
+}
+
+// String implements fmt.Stringer.
+func (s httpIPSource) String() string {
+	return string(s)
+}
+
+// dnsIPSource implements ipSource.
+//
+// The resolver answers the address records of the name with the IP address
+// the query is received from.
+type dnsIPSource struct {
+	resolver string
+	name     string
+}
+
+// lookup queries the resolver over the address family.
+func (s dnsIPSource) lookup(ctx context.Context, family string) (net.IP, error) {
+	dial := familyDialer(family)
+	r := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return dial(ctx, network, net.JoinHostPort(s.resolver, "53"))
+		},
+	}
+
+	addrs, err := r.LookupIPAddr(ctx, s.name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range addrs {
+		if isFamily(a.IP, family) {
+			return a.IP, nil
+		}
+	}
+	return nil, fmt.Errorf("no IPv%v address for %v", family, s.name)
+}
 
-	ch <- ip
+// String implements fmt.Stringer.
+func (s dnsIPSource) String() string {
+	return fmt.Sprintf("dns://%v/%v", s.resolver, s.name)
 }
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -15,30 +16,71 @@ func fakeReadAllError(r io.Reader) ([]byte, error) {
 	return nil, errors.New("read error")
 }
 
+// newIPServer returns a server that responds with the body.
+func newIPServer(body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(body))
+	}))
+}
+
 func TestNewPublicIPLookup(t *testing.T) {
 	want := &ipLookupper{}
-	got := NewPublicIPLookup()
+	got := NewPublicIPLookup(nil, 0)
 
 	if reflect.TypeOf(got) != reflect.TypeOf(want) {
 		t.Errorf("want %v, got %v", reflect.TypeOf(want), reflect.TypeOf(got))
 	}
+
+	if l := got.(*ipLookupper); len(l.sources) != len(DefaultPublicIPSources) || l.quorum != 1 {
+		t.Errorf("want %v sources and quorum 1, got %v", len(DefaultPublicIPSources), l)
+	}
+
+	if got := NewPublicIPLookup([]string{"ftp://example.com", "dns://resolver1.opendns.com"}, 1); got != nil {
+		t.Errorf("want %v, got %v", nil, got)
+	}
+}
+
+func TestNewIPSource(t *testing.T) {
+	tests := []struct {
+		source string
+		want   ipSource
+	}{
+		{source: "https://ifconfig.me/ip", want: httpIPSource("https://ifconfig.me/ip")},
+		{source: "dns://resolver1.opendns.com/myip.opendns.com", want: dnsIPSource{resolver: "resolver1.opendns.com", name: "myip.opendns.com"}},
+		{source: "dns://resolver1.opendns.com", want: nil},
+		{source: "ifconfig.me", want: nil},
+	}
+
+	for _, tt := range tests {
+		got, _ := newIPSource(tt.source)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("want %v, got %v", tt.want, got)
+		}
+	}
 }
 
 func TestIPLookupSuccess(t *testing.T) {
-	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		w.Write([]byte("127.0.0.1"))
-	}))
+	testServer := newIPServer(" 127.0.0.1\n")
 	defer func() { testServer.Close() }()
 
 	lookupper := ipLookupper{
 		timeout: 10 * time.Second,
-		sources: []string{testServer.URL},
+		quorum:  1,
+		sources: []ipSource{httpIPSource(testServer.URL)},
 	}
 
-	_, err := lookupper.Lookup()
+	got, err := lookupper.Lookup()
 	if err != nil {
 		t.Errorf("want %v, got %v", nil, err)
 	}
+
+	if got.IPv4.String() != "127.0.0.1" || got.IPv6 != nil {
+		t.Errorf("want %v, got %v", "127.0.0.1", got)
+	}
+
+	if want := []string{testServer.URL}; !reflect.DeepEqual(got.IPv4Sources, want) {
+		t.Errorf("want %v, got %v", want, got.IPv4Sources)
+	}
 }
 
 func TestIPLookupResponseError(t *testing.T) {
@@ -48,11 +90,12 @@ func TestIPLookupResponseError(t *testing.T) {
 	defer func() { testServer.Close() }()
 
 	lookupper := ipLookupper{
-		timeout: time.Millisecond,
-		sources: []string{testServer.URL},
+		timeout: 10 * time.Second,
+		quorum:  1,
+		sources: []ipSource{httpIPSource(testServer.URL)},
 	}
 
-	want := ErrTimeout
+	want := ErrNoAnswer
 	_, got := lookupper.Lookup()
 	if got != want {
 		t.Errorf("want %v, got %v", want, got)
@@ -63,17 +106,16 @@ func TestIPLookupReadBodyError(t *testing.T) {
 	readAll = fakeReadAllError
 	defer func() { readAll = ioutil.ReadAll }()
 
-	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		w.Write([]byte(""))
-	}))
+	testServer := newIPServer("")
 	defer func() { testServer.Close() }()
 
 	lookupper := ipLookupper{
-		timeout: time.Millisecond,
-		sources: []string{testServer.URL},
+		timeout: 10 * time.Second,
+		quorum:  1,
+		sources: []ipSource{httpIPSource(testServer.URL)},
 	}
 
-	want := ErrTimeout
+	want := ErrNoAnswer
 	_, got := lookupper.Lookup()
 	if got != want {
 		t.Errorf("want %v, got %v", want, got)
@@ -81,19 +123,72 @@ func TestIPLookupReadBodyError(t *testing.T) {
 }
 
 func TestIPLookupParseError(t *testing.T) {
+	testServers := []*httptest.Server{
+		newIPServer("invalid-ip-address"),
+		newIPServer(strings.Repeat(" ", ipResponseLimit) + "127.0.0.1"),
+	}
+
+	for _, s := range testServers {
+		defer s.Close()
+
+		lookupper := ipLookupper{
+			timeout: 10 * time.Second,
+			quorum:  1,
+			sources: []ipSource{httpIPSource(s.URL)},
+		}
+
+		want := ErrNoAnswer
+		_, got := lookupper.Lookup()
+		if got != want {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	}
+}
+
+func TestIPLookupTimeout(t *testing.T) {
 	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		w.Write([]byte("invalid-ip-address"))
+		time.Sleep(100 * time.Millisecond)
 	}))
 	defer func() { testServer.Close() }()
 
 	lookupper := ipLookupper{
 		timeout: time.Millisecond,
-		sources: []string{testServer.URL},
+		quorum:  1,
+		sources: []ipSource{httpIPSource(testServer.URL)},
 	}
 
-	want := ErrTimeout
 	_, got := lookupper.Lookup()
-	if got != want {
+	if got != ErrTimeout && got != ErrNoAnswer {
+		t.Errorf("want %v, got %v", ErrTimeout, got)
+	}
+}
+
+func TestIPLookupQuorum(t *testing.T) {
+	a := newIPServer("127.0.0.1")
+	b := newIPServer("127.0.0.2")
+	c := newIPServer("127.0.0.1")
+	defer a.Close()
+	defer b.Close()
+	defer c.Close()
+
+	lookupper := ipLookupper{
+		timeout: 10 * time.Second,
+		quorum:  2,
+		sources: []ipSource{httpIPSource(a.URL), httpIPSource(b.URL), httpIPSource(c.URL)},
+	}
+
+	got, err := lookupper.Lookup()
+	if err != nil {
+		t.Fatalf("want %v, got %v", nil, err)
+	}
+
+	if got.IPv4.String() != "127.0.0.1" || len(got.IPv4Sources) != 2 {
+		t.Errorf("want %v answered by 2 sources, got %v", "127.0.0.1", got)
+	}
+
+	lookupper.sources = lookupper.sources[:2]
+	want := ErrNoQuorum
+	if _, got := lookupper.Lookup(); got != want {
 		t.Errorf("want %v, got %v", want, got)
 	}
 }
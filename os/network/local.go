@@ -0,0 +1,231 @@
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+var readFile = ioutil.ReadFile
+var netInterfaces = net.Interfaces
+var interfaceAddrs = func(i net.Interface) ([]net.Addr, error) { return i.Addrs() }
+
+// Represents the paths used by the local network lookup.
+var (
+	sysClassNetPath      = "/sys/class/net"
+	procNetRoutePath     = "/proc/net/route"
+	procNetIPv6RoutePath = "/proc/net/ipv6_route"
+	resolvConfPath       = "/etc/resolv.conf"
+	resolvedConfPath     = "/run/systemd/resolve/resolv.conf"
+)
+
+// resolvedStubAddress is the address of the systemd-resolved stub resolver.
+const resolvedStubAddress = "127.0.0.53"
+
+// NewLocalNetworkLookup returns a tin.LocalNetworkLookup.
+func NewLocalNetworkLookup() tin.LocalNetworkLookup {
+	return &localNetworkLookupper{}
+}
+
+// localNetworkLookupper implements tin.LocalNetworkLookup.
+type localNetworkLookupper struct{}
+
+// Lookup returns a tin.LocalNetwork.
+//
+// The routes and DNS servers are optional, failing to read them leaves them empty.
+func (l *localNetworkLookupper) Lookup() (tin.LocalNetwork, error) {
+	interfaces, err := l.interfaces()
+	if err != nil {
+		return tin.LocalNetwork{}, err
+	}
+
+	routes := []tin.Route{}
+	if data, err := readFile(procNetRoutePath); err == nil {
+		routes = append(routes, parseRoutes(string(data))...)
+	}
+	if data, err := readFile(procNetIPv6RoutePath); err == nil {
+		routes = append(routes, parseIPv6Routes(string(data))...)
+	}
+
+	return tin.LocalNetwork{
+		Interfaces: interfaces,
+		Routes:     routes,
+		DNSServers: l.dnsServers(),
+	}, nil
+}
+
+// interfaces returns the network interfaces of the kernel.
+//
+// The state is the operational state of sysfs, the flags are used when it's unavailable.
+func (l *localNetworkLookupper) interfaces() ([]tin.NetworkInterface, error) {
+	ii, err := netInterfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	interfaces := []tin.NetworkInterface{}
+	for _, i := range ii {
+		state := "down"
+		if i.Flags&net.FlagUp != 0 {
+			state = "up"
+		}
+		if data, err := readFile(filepath.Join(sysClassNetPath, i.Name, "operstate")); err == nil {
+			state = strings.TrimSpace(string(data))
+		}
+
+		addresses := []string{}
+		addrs, err := interfaceAddrs(i)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading addresses of %v: %w", i.Name, err)
+		}
+		for _, a := range addrs {
+			addresses = append(addresses, a.String())
+		}
+
+		interfaces = append(interfaces, tin.NetworkInterface{
+			Name:      i.Name,
+			State:     state,
+			MAC:       i.HardwareAddr.String(),
+			MTU:       i.MTU,
+			Addresses: addresses,
+		})
+	}
+	return interfaces, nil
+}
+
+// dnsServers returns the nameservers of resolv.conf.
+//
+// When systemd-resolved is used, the stub resolver is replaced by the upstream servers.
+func (l *localNetworkLookupper) dnsServers() []string {
+	data, err := readFile(resolvConfPath)
+	if err != nil {
+		return []string{}
+	}
+
+	servers := parseResolvConf(string(data))
+	if len(servers) == 1 && servers[0] == resolvedStubAddress {
+		if data, err := readFile(resolvedConfPath); err == nil {
+			servers = parseResolvConf(string(data))
+		}
+	}
+	return servers
+}
+
+// parseResolvConf returns the nameservers of the resolv.conf.
+//
+// Example of a line: nameserver 192.168.1.1
+func parseResolvConf(data string) []string {
+	servers := []string{}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		f := strings.Fields(scanner.Text())
+		if len(f) >= 2 && f[0] == "nameserver" {
+			servers = append(servers, f[1])
+		}
+	}
+	return servers
+}
+
+// Represents the flags of a route.
+const (
+	routeUp      = 0x0001
+	routeGateway = 0x0002
+	routeReject  = 0x0200
+)
+
+// parseRoutes parses the content of /proc/net/route into a slice of tin.Route.
+//
+// The addresses are hexadecimal in host byte order, routes that are down are ignored.
+// Example of a line: wlan0	00000000	0101A8C0	0003	0	0	600	00000000	0	0	0
+func parseRoutes(data string) []tin.Route {
+	routes := []tin.Route{}
+	for i, v := range strings.Split(data, "\n") {
+		f := strings.Fields(v)
+		if i == 0 || len(f) < 8 {
+			continue
+		}
+
+		flags, err := strconv.ParseUint(f[3], 16, 16)
+		if err != nil || flags&routeUp == 0 {
+			continue
+		}
+
+		destination, err1 := parseIPv4Hex(f[1])
+		gateway, err2 := parseIPv4Hex(f[2])
+		mask, err3 := parseIPv4Hex(f[7])
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+
+		prefix, _ := net.IPMask(mask.To4()).Size()
+		r := tin.Route{
+			Destination: fmt.Sprintf("%v/%v", destination, prefix),
+			Interface:   f[0],
+		}
+		r.Metric, _ = strconv.Atoi(f[6])
+		if flags&routeGateway != 0 {
+			r.Gateway = gateway.String()
+		}
+		routes = append(routes, r)
+	}
+	return routes
+}
+
+// parseIPv4Hex parses a hexadecimal IPv4 address in little-endian byte order.
+func parseIPv4Hex(v string) (net.IP, error) {
+	b, err := hex.DecodeString(v)
+	if err != nil || len(b) != 4 {
+		return nil, fmt.Errorf("invalid address %v", v)
+	}
+
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(b))
+	return ip, nil
+}
+
+// parseIPv6Routes parses the content of /proc/net/ipv6_route into a slice of tin.Route.
+//
+// Routes of the loopback interface and rejecting routes are ignored.
+// Example of a line: 00000000000000000000000000000000 00 00000000000000000000000000000000 00 fe800000000000000000000000000001 00000400 00000001 00000000 00000003 wlan0
+func parseIPv6Routes(data string) []tin.Route {
+	routes := []tin.Route{}
+	for _, v := range strings.Split(data, "\n") {
+		f := strings.Fields(v)
+		if len(f) != 10 || f[9] == "lo" {
+			continue
+		}
+
+		flags, err := strconv.ParseUint(f[8], 16, 32)
+		if err != nil || flags&routeUp == 0 || flags&routeReject != 0 {
+			continue
+		}
+
+		destination, err1 := hex.DecodeString(f[0])
+		prefix, err2 := strconv.ParseUint(f[1], 16, 8)
+		gateway, err3 := hex.DecodeString(f[4])
+		metric, err4 := strconv.ParseUint(f[5], 16, 32)
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			continue
+		}
+
+		r := tin.Route{
+			Destination: fmt.Sprintf("%v/%v", net.IP(destination), prefix),
+			Interface:   f[9],
+			Metric:      int(metric),
+		}
+		if !bytes.Equal(gateway, net.IPv6zero) {
+			r.Gateway = net.IP(gateway).String()
+		}
+		routes = append(routes, r)
+	}
+	return routes
+}
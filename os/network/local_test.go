@@ -0,0 +1,115 @@
+package network
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+func fakeNetInterfaces() ([]net.Interface, error) {
+	mac, _ := net.ParseMAC("3c:22:fb:12:34:56")
+	return []net.Interface{
+		{Index: 1, MTU: 65536, Name: "lo", Flags: net.FlagUp | net.FlagLoopback},
+		{Index: 2, MTU: 1500, Name: "wlan0", HardwareAddr: mac, Flags: net.FlagUp},
+	}, nil
+}
+
+func fakeInterfaceAddrs(i net.Interface) ([]net.Addr, error) {
+	if i.Name == "lo" {
+		_, n, _ := net.ParseCIDR("127.0.0.1/8")
+		n.IP = net.ParseIP("127.0.0.1")
+		return []net.Addr{n}, nil
+	}
+
+	ip, n, _ := net.ParseCIDR("192.168.1.10/24")
+	n.IP = ip
+	return []net.Addr{n}, nil
+}
+
+func TestLocalNetworkLookup(t *testing.T) {
+	netInterfaces = fakeNetInterfaces
+	interfaceAddrs = fakeInterfaceAddrs
+	sysClassNetPath = "testdata/missing"
+	procNetRoutePath = "testdata/route"
+	procNetIPv6RoutePath = "testdata/ipv6_route"
+	resolvConfPath = "testdata/resolv.conf"
+	defer func() {
+		netInterfaces = net.Interfaces
+		interfaceAddrs = func(i net.Interface) ([]net.Addr, error) { return i.Addrs() }
+		sysClassNetPath = "/sys/class/net"
+		procNetRoutePath = "/proc/net/route"
+		procNetIPv6RoutePath = "/proc/net/ipv6_route"
+		resolvConfPath = "/etc/resolv.conf"
+	}()
+
+	want := tin.LocalNetwork{
+		Interfaces: []tin.NetworkInterface{
+			{Name: "lo", State: "up", MAC: "", MTU: 65536, Addresses: []string{"127.0.0.1/8"}},
+			{Name: "wlan0", State: "up", MAC: "3c:22:fb:12:34:56", MTU: 1500, Addresses: []string{"192.168.1.10/24"}},
+		},
+		Routes: []tin.Route{
+			{Destination: "0.0.0.0/0", Gateway: "192.168.1.1", Interface: "wlan0", Metric: 600},
+			{Destination: "192.168.1.0/24", Interface: "wlan0", Metric: 600},
+			{Destination: "::/0", Gateway: "fe80::1", Interface: "wlan0", Metric: 1024},
+			{Destination: "fe80::/64", Interface: "wlan0", Metric: 256},
+		},
+		DNSServers: []string{"192.168.1.1", "fe80::1%wlan0"},
+	}
+
+	l := NewLocalNetworkLookup()
+	got, err := l.Lookup()
+	if err != nil {
+		t.Errorf("want %v, got %v", nil, err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if r := got.DefaultRoutes(); len(r) != 2 {
+		t.Errorf("want %v, got %v", 2, len(r))
+	}
+}
+
+func TestLocalNetworkLookupError(t *testing.T) {
+	netInterfaces = func() ([]net.Interface, error) { return nil, errors.New("error") }
+	defer func() { netInterfaces = net.Interfaces }()
+
+	l := NewLocalNetworkLookup()
+	_, got := l.Lookup()
+	if got == nil {
+		t.Errorf("want %v, got %v", "error", got)
+	}
+}
+
+func TestDNSServersResolved(t *testing.T) {
+	resolvConfPath = "testdata/resolv-stub.conf"
+	resolvedConfPath = "testdata/resolved.conf"
+	defer func() {
+		resolvConfPath = "/etc/resolv.conf"
+		resolvedConfPath = "/run/systemd/resolve/resolv.conf"
+	}()
+
+	l := &localNetworkLookupper{}
+	want := []string{"1.1.1.1", "9.9.9.9"}
+	got := l.dnsServers()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestParseRoutesInvalid(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/route")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := parseRoutes(string(data) + "eth0\tXYZ\t00000000\t0001\t0\t0\t0\t00000000\t0\t0\t0\n")
+	if len(got) != 2 {
+		t.Errorf("want %v, got %v", 2, got)
+	}
+}
@@ -0,0 +1,323 @@
+package network
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+)
+
+// mmdbMetadataMarker precedes the metadata at the end of a MaxMind DB file.
+var mmdbMetadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+// mmdbDataSeparator is the number of zero bytes between the search tree and the data section.
+const mmdbDataSeparator = 16
+
+// Represents the types of the MaxMind DB data section.
+const (
+	mmdbPointer   = 1
+	mmdbString    = 2
+	mmdbDouble    = 3
+	mmdbBytes     = 4
+	mmdbUint16    = 5
+	mmdbUint32    = 6
+	mmdbMap       = 7
+	mmdbInt32     = 8
+	mmdbUint64    = 9
+	mmdbUint128   = 10
+	mmdbArray     = 11
+	mmdbContainer = 12
+	mmdbEndMarker = 13
+	mmdbBoolean   = 14
+	mmdbFloat     = 15
+)
+
+// mmdbReader reads a MaxMind DB file, the format of the GeoIP2 and GeoLite2 databases.
+//
+// The format is a binary search tree of the bits of the IP address of which
+// the leaves point to records in the data section.
+// See https://maxmind.github.io/MaxMind-DB/ for the specification.
+type mmdbReader struct {
+	data       []byte
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	dataStart  uint
+	ipv4Start  uint
+}
+
+// newMMDBReader returns a mmdbReader of the content of a MaxMind DB file.
+func newMMDBReader(data []byte) (*mmdbReader, error) {
+	i := bytes.LastIndex(data, mmdbMetadataMarker)
+	if i < 0 {
+		return nil, errors.New("mmdb: metadata not found")
+	}
+
+	start := uint(i + len(mmdbMetadataMarker))
+	d := mmdbDecoder{data: data[start:]}
+	v, _, err := d.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("mmdb: invalid metadata: %w", err)
+	}
+	metadata, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("mmdb: invalid metadata")
+	}
+
+	r := &mmdbReader{
+		data:       data,
+		nodeCount:  uint(toUint(metadata["node_count"])),
+		recordSize: uint(toUint(metadata["record_size"])),
+		ipVersion:  uint(toUint(metadata["ip_version"])),
+	}
+	switch r.recordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("mmdb: unsupported record size %v", r.recordSize)
+	}
+
+	r.dataStart = r.nodeCount*r.recordSize/4 + mmdbDataSeparator
+	if r.dataStart > uint(i) {
+		return nil, errors.New("mmdb: invalid search tree size")
+	}
+
+	// IPv4 addresses are stored as ::a.b.c.d in an IPv6 database.
+	if r.ipVersion == 6 {
+		node := uint(0)
+		for bit := 0; bit < 96 && node < r.nodeCount; bit++ {
+			node = r.readNode(node, 0)
+		}
+		r.ipv4Start = node
+	}
+	return r, nil
+}
+
+// lookup returns the record of the IP address, or nil when there is none.
+func (r *mmdbReader) lookup(ip net.IP) (interface{}, error) {
+	node := uint(0)
+	address := ip.To16()
+	if ip4 := ip.To4(); ip4 != nil {
+		address = ip4
+		if r.ipVersion == 6 {
+			node = r.ipv4Start
+		}
+	} else if r.ipVersion == 4 {
+		return nil, nil
+	}
+
+	for i := 0; i < len(address)*8 && node < r.nodeCount; i++ {
+		bit := uint(address[i/8]>>(7-uint(i%8))) & 1
+		node = r.readNode(node, bit)
+	}
+
+	if node <= r.nodeCount {
+		return nil, nil
+	}
+
+	offset := node - r.nodeCount - mmdbDataSeparator
+	d := mmdbDecoder{data: r.data[r.dataStart:]}
+	v, _, err := d.decode(offset)
+	return v, err
+}
+
+// readNode returns the left (0) or right (1) record of the node.
+func (r *mmdbReader) readNode(node, bit uint) uint {
+	b := r.data
+	switch r.recordSize {
+	case 24:
+		o := node*6 + bit*3
+		return uint(b[o])<<16 | uint(b[o+1])<<8 | uint(b[o+2])
+	case 28:
+		o := node * 7
+		if bit == 0 {
+			return uint(b[o+3]&0xF0)<<20 | uint(b[o])<<16 | uint(b[o+1])<<8 | uint(b[o+2])
+		}
+		return uint(b[o+3]&0x0F)<<24 | uint(b[o+4])<<16 | uint(b[o+5])<<8 | uint(b[o+6])
+	default:
+		o := node*8 + bit*4
+		return uint(binary.BigEndian.Uint32(b[o:]))
+	}
+}
+
+// mmdbDecoder decodes the values of a data section, pointers are relative to the start of it.
+type mmdbDecoder struct {
+	data []byte
+}
+
+// mmdbMaxDepth is the maximum nesting of maps, arrays and pointers.
+const mmdbMaxDepth = 32
+
+// decode returns the value at the offset and the offset after it.
+func (d mmdbDecoder) decode(offset uint) (interface{}, uint, error) {
+	return d.decodeDepth(offset, 0)
+}
+
+// decodeDepth returns the value at the offset of the nesting depth and the offset after it.
+func (d mmdbDecoder) decodeDepth(offset uint, depth int) (interface{}, uint, error) {
+	if depth > mmdbMaxDepth {
+		return nil, 0, errors.New("maximum depth exceeded")
+	}
+	if offset >= uint(len(d.data)) {
+		return nil, 0, errors.New("offset out of range")
+	}
+
+	ctrl := d.data[offset]
+	offset++
+	typ := uint(ctrl >> 5)
+
+	if typ == mmdbPointer {
+		return d.decodePointer(ctrl, offset, depth)
+	}
+
+	if typ == 0 {
+		if offset >= uint(len(d.data)) {
+			return nil, 0, errors.New("offset out of range")
+		}
+		typ = 7 + uint(d.data[offset])
+		offset++
+	}
+
+	size, offset, err := d.size(ctrl, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch typ {
+	case mmdbMap:
+		m := map[string]interface{}{}
+		for i := uint(0); i < size; i++ {
+			k, next, err := d.decodeDepth(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			v, next, err := d.decodeDepth(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, 0, errors.New("invalid map key")
+			}
+			m[key] = v
+			offset = next
+		}
+		return m, offset, nil
+	case mmdbArray:
+		a := make([]interface{}, 0, size)
+		for i := uint(0); i < size; i++ {
+			v, next, err := d.decodeDepth(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			a = append(a, v)
+			offset = next
+		}
+		return a, offset, nil
+	case mmdbBoolean:
+		return size != 0, offset, nil
+	case mmdbContainer, mmdbEndMarker:
+		return nil, offset, nil
+	}
+
+	end := offset + size
+	if end > uint(len(d.data)) {
+		return nil, 0, errors.New("value out of range")
+	}
+	b := d.data[offset:end]
+
+	switch typ {
+	case mmdbString:
+		return string(b), end, nil
+	case mmdbBytes, mmdbUint128:
+		return append([]byte{}, b...), end, nil
+	case mmdbDouble:
+		if size != 8 {
+			return nil, 0, errors.New("invalid double size")
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), end, nil
+	case mmdbFloat:
+		if size != 4 {
+			return nil, 0, errors.New("invalid float size")
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(b)), end, nil
+	case mmdbUint16, mmdbUint32, mmdbUint64:
+		var v uint64
+		for _, c := range b {
+			v = v<<8 | uint64(c)
+		}
+		return v, end, nil
+	case mmdbInt32:
+		var v uint32
+		for _, c := range b {
+			v = v<<8 | uint32(c)
+		}
+		return int64(int32(v)), end, nil
+	default:
+		return nil, 0, fmt.Errorf("unknown type %v", typ)
+	}
+}
+
+// size returns the size of the value of the control byte and the offset after it.
+func (d mmdbDecoder) size(ctrl byte, offset uint) (uint, uint, error) {
+	size := uint(ctrl & 0x1F)
+	if size < 29 {
+		return size, offset, nil
+	}
+
+	n := size - 28
+	if offset+n > uint(len(d.data)) {
+		return 0, 0, errors.New("size out of range")
+	}
+
+	var v uint
+	for _, c := range d.data[offset : offset+n] {
+		v = v<<8 | uint(c)
+	}
+	switch size {
+	case 29:
+		return 29 + v, offset + n, nil
+	case 30:
+		return 285 + v, offset + n, nil
+	default:
+		return 65821 + v, offset + n, nil
+	}
+}
+
+// decodePointer returns the value the pointer points to and the offset after the pointer.
+func (d mmdbDecoder) decodePointer(ctrl byte, offset uint, depth int) (interface{}, uint, error) {
+	n := uint(ctrl>>3&0x3) + 1
+	if offset+n > uint(len(d.data)) {
+		return nil, 0, errors.New("pointer out of range")
+	}
+
+	b := d.data[offset : offset+n]
+	var p uint
+	switch n {
+	case 1:
+		p = uint(ctrl&0x7)<<8 | uint(b[0])
+	case 2:
+		p = (uint(ctrl&0x7)<<16 | uint(b[0])<<8 | uint(b[1])) + 2048
+	case 3:
+		p = (uint(ctrl&0x7)<<24 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])) + 526336
+	default:
+		p = uint(binary.BigEndian.Uint32(b))
+	}
+
+	v, _, err := d.decodeDepth(p, depth+1)
+	return v, offset + n, err
+}
+
+// toUint returns the unsigned integer of a decoded value, or 0 when it isn't one.
+func toUint(v interface{}) uint64 {
+	switch n := v.(type) {
+	case uint64:
+		return n
+	case int64:
+		if n > 0 {
+			return uint64(n)
+		}
+	}
+	return 0
+}
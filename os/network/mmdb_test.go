@@ -0,0 +1,63 @@
+package network
+
+import (
+	"io/ioutil"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestMMDBReader(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/geo.mmdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := newMMDBReader(data)
+	if err != nil {
+		t.Fatalf("want %v, got %v", nil, err)
+	}
+
+	v, err := r.lookup(net.ParseIP("203.0.113.255"))
+	if err != nil {
+		t.Fatalf("want %v, got %v", nil, err)
+	}
+
+	country := map[string]interface{}{
+		"iso_code": "NL",
+		"names":    map[string]interface{}{"en": "Netherlands"},
+	}
+	want := map[string]interface{}{
+		"city":                           map[string]interface{}{"names": map[string]interface{}{"en": "Amsterdam"}},
+		"country":                        country,
+		"registered_country":             country,
+		"autonomous_system_number":       uint64(64500),
+		"autonomous_system_organization": "Example ISP",
+		"location":                       map[string]interface{}{"latitude": 52.37, "accuracy_radius": uint64(20)},
+		"is_anycast":                     false,
+	}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("want %v, got %v", want, v)
+	}
+}
+
+func TestMMDBReaderInvalid(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/geo.mmdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range [][]byte{[]byte("not a database"), data[len(data)-100:]} {
+		if _, err := newMMDBReader(d); err == nil {
+			t.Errorf("want %v, got %v", "error", err)
+		}
+	}
+}
+
+func TestMMDBDecoderLoop(t *testing.T) {
+	// A map of which the value is a pointer to the map.
+	d := mmdbDecoder{data: []byte{0xe1, 0x41, 'a', 0x20, 0x00}}
+	if _, _, err := d.decode(0); err == nil {
+		t.Errorf("want %v, got %v", "error", err)
+	}
+}
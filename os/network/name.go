@@ -1,7 +1,10 @@
 package network
 
 import (
+	"errors"
+	"fmt"
 	"os/exec"
+	"regexp"
 	"strings"
 
 	"github.com/sjengpho/tin/tin"
@@ -10,15 +13,79 @@ import (
 var execCommand = exec.Command
 var lookPath = exec.LookPath
 
+var procNetWirelessPath = "/proc/net/wireless"
+
+// ErrNotConnected is returned when no wireless interface is connected to a network.
+var ErrNotConnected = errors.New("essid lookup: not connected")
+
+// ansiEscape matches the escape sequences of colored output.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// DefaultESSIDBackends are the backends of the network name lookup in order of preference.
+var DefaultESSIDBackends = []string{"iw", "nmcli", "iwctl", "iwgetid"}
+
+// essidBackends are the tin.ESSIDLookup implementations by name.
+var essidBackends = map[string]tin.ESSIDLookup{
+	"iw":      &iw{},
+	"nmcli":   &nmcli{},
+	"iwctl":   &iwctl{},
+	"iwgetid": &iwgetid{},
+}
+
 // NewNameLookup returns a tin.ESSIDLookup.
 //
+// The preference contains the names of the backends to try in order,
+// DefaultESSIDBackends are used when it's empty. Backends of which the
+// executable isn't installed are skipped.
 // If tin.ESSIDLookup couldn't be resolved it will return nil.
-func NewNameLookup() tin.ESSIDLookup {
-	if _, err := lookPath("iwgetid"); err == nil {
-		return &iwgetid{}
+func NewNameLookup(preference []string) tin.ESSIDLookup {
+	if len(preference) == 0 {
+		preference = DefaultESSIDBackends
+	}
+
+	c := &nameLookupChain{}
+	for _, name := range preference {
+		backend, ok := essidBackends[name]
+		if !ok {
+			continue
+		}
+
+		if _, err := lookPath(name); err == nil {
+			c.backends = append(c.backends, backend)
+		}
+	}
+
+	if len(c.backends) == 0 {
+		return nil
+	}
+	return c
+}
+
+// nameLookupChain implements tin.ESSIDLookup.
+type nameLookupChain struct {
+	backends []tin.ESSIDLookup
+}
+
+// Lookup returns a tin.ESSID.
+//
+// It returns the result of the first backend that succeeds, for example
+// nmcli fails when NetworkManager isn't running. The name is empty when
+// none succeeds and a backend reported ErrNotConnected.
+func (c *nameLookupChain) Lookup() (tin.ESSID, error) {
+	err := errors.New("essid lookup: no backends")
+	notConnected := false
+	for _, b := range c.backends {
+		var name tin.ESSID
+		if name, err = b.Lookup(); err == nil {
+			return name, nil
+		}
+		notConnected = notConnected || errors.Is(err, ErrNotConnected)
 	}
 
-	return nil
+	if notConnected {
+		return "", nil
+	}
+	return "", err
 }
 
 // iwgetid implements tin.ESSIDLookup.
@@ -35,3 +102,143 @@ func (i *iwgetid) Lookup() (tin.ESSID, error) {
 
 	return tin.ESSID(strings.TrimSpace(string(output))), nil
 }
+
+// iw implements tin.ESSIDLookup.
+type iw struct{}
+
+// Lookup returns a tin.ESSID.
+//
+// It uses iw to fetch the network name of the first connected wireless interface.
+func (i *iw) Lookup() (tin.ESSID, error) {
+	return lookupInterfaces(func(name string) (string, error) {
+		output, err := execCommand("iw", "dev", name, "link").Output()
+		return parseIWLink(string(output)), err
+	})
+}
+
+// parseIWLink returns the SSID of the output of iw dev <interface> link.
+//
+// The output is Not connected. when the interface isn't connected.
+// Example of a line: 	SSID: network-name
+func parseIWLink(output string) string {
+	for _, v := range strings.Split(output, "\n") {
+		v = strings.TrimSpace(v)
+		if strings.HasPrefix(v, "SSID: ") {
+			return strings.TrimPrefix(v, "SSID: ")
+		}
+	}
+	return ""
+}
+
+// nmcli implements tin.ESSIDLookup.
+type nmcli struct{}
+
+// Lookup returns a tin.ESSID.
+//
+// It uses the NetworkManager CLI to fetch the network name of the active connection.
+func (n *nmcli) Lookup() (tin.ESSID, error) {
+	output, err := execCommand("nmcli", "-t", "-f", "active,ssid", "dev", "wifi").Output()
+	if err != nil {
+		return "", err
+	}
+
+	ssid := parseNmcli(string(output))
+	if ssid == "" {
+		return "", ErrNotConnected
+	}
+	return tin.ESSID(ssid), nil
+}
+
+// parseNmcli returns the SSID of the active network.
+//
+// The fields are separated by colons, colons in the SSID are escaped with a backslash.
+// Example of a line: yes:network-name
+func parseNmcli(output string) string {
+	for _, v := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(v, "yes:") {
+			continue
+		}
+
+		ssid := strings.TrimPrefix(v, "yes:")
+		ssid = strings.ReplaceAll(ssid, `\:`, ":")
+		ssid = strings.ReplaceAll(ssid, `\\`, `\`)
+		return ssid
+	}
+	return ""
+}
+
+// iwctl implements tin.ESSIDLookup.
+type iwctl struct{}
+
+// Lookup returns a tin.ESSID.
+//
+// It uses the iwd CLI to fetch the network name of the first connected wireless interface.
+func (i *iwctl) Lookup() (tin.ESSID, error) {
+	return lookupInterfaces(func(name string) (string, error) {
+		output, err := execCommand("iwctl", "station", name, "show").Output()
+		return parseIwctl(string(output)), err
+	})
+}
+
+// parseIwctl returns the connected network of the output of iwctl station <interface> show.
+//
+// The output is a table with colors, the property and value are separated by spaces.
+// Example of a line:             Connected network     network-name
+func parseIwctl(output string) string {
+	output = ansiEscape.ReplaceAllString(output, "")
+	for _, v := range strings.Split(output, "\n") {
+		v = strings.TrimSpace(v)
+		if strings.HasPrefix(v, "Connected network") {
+			return strings.TrimSpace(strings.TrimPrefix(v, "Connected network"))
+		}
+	}
+	return ""
+}
+
+// lookupInterfaces returns the network name of the first wireless interface
+// for which lookup returns a name.
+//
+// Interfaces that fail are skipped, for example an interface that isn't
+// managed by iwd. It returns the last error, or ErrNotConnected when no
+// interface is connected, so the next backend is tried.
+func lookupInterfaces(lookup func(name string) (string, error)) (tin.ESSID, error) {
+	interfaces, err := wirelessInterfaces()
+	if err != nil {
+		return "", err
+	}
+
+	err = ErrNotConnected
+	for _, name := range interfaces {
+		ssid, e := lookup(name)
+		if e != nil {
+			err = fmt.Errorf("%v: %w", name, e)
+			continue
+		}
+
+		if ssid != "" {
+			return tin.ESSID(ssid), nil
+		}
+	}
+	return "", err
+}
+
+// wirelessInterfaces returns the names of the wireless interfaces.
+//
+// The first two lines of /proc/net/wireless are headers.
+// Example of a line:  wlan0: 0000   60.  -50.  -256        0      0      0      0     12        0
+func wirelessInterfaces() ([]string, error) {
+	data, err := readFile(procNetWirelessPath)
+	if err != nil {
+		return nil, err
+	}
+
+	interfaces := []string{}
+	for i, v := range strings.Split(string(data), "\n") {
+		sep := strings.Index(v, ":")
+		if i < 2 || sep < 0 {
+			continue
+		}
+		interfaces = append(interfaces, strings.TrimSpace(v[:sep]))
+	}
+	return interfaces, nil
+}
@@ -3,6 +3,7 @@ package network
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"reflect"
@@ -33,8 +34,8 @@ func TestNewNameLookupSuccess(t *testing.T) {
 	lookPath = fakeLookPathSuccess
 	defer func() { lookPath = exec.LookPath }()
 
-	want := reflect.TypeOf(&iwgetid{})
-	got := reflect.TypeOf(NewNameLookup())
+	want := reflect.TypeOf(&nameLookupChain{})
+	got := reflect.TypeOf(NewNameLookup(nil))
 	if got != want {
 		t.Errorf("want %v, got %v", want, got)
 	}
@@ -45,7 +46,7 @@ func TestNewNameLookupError(t *testing.T) {
 	defer func() { lookPath = exec.LookPath }()
 
 	want := reflect.TypeOf(nil)
-	got := reflect.TypeOf(NewNameLookup())
+	got := reflect.TypeOf(NewNameLookup(nil))
 	if got != want {
 		t.Errorf("want %v, got %v", want, got)
 	}
@@ -90,3 +91,187 @@ func TestNameLookupCommandError(t *testing.T) {
 
 	os.Exit(1)
 }
+
+func TestNewNameLookupPreference(t *testing.T) {
+	lookPath = func(file string) (string, error) {
+		if file != "nmcli" && file != "iwgetid" {
+			return "", errors.New("executeable doesn't exists")
+		}
+		return "fake-path", nil
+	}
+	defer func() { lookPath = exec.LookPath }()
+
+	want := []tin.ESSIDLookup{&iwgetid{}, &nmcli{}}
+	got := NewNameLookup([]string{"iwgetid", "iw", "unknown", "nmcli"}).(*nameLookupChain)
+	if !reflect.DeepEqual(got.backends, want) {
+		t.Errorf("want %v, got %v", want, got.backends)
+	}
+}
+
+func TestNameLookupChain(t *testing.T) {
+	c := &nameLookupChain{backends: []tin.ESSIDLookup{
+		essidLookupMock{err: errors.New("error")},
+		essidLookupMock{name: "network-name"},
+	}}
+
+	want := tin.ESSID("network-name")
+	got, err := c.Lookup()
+	if err != nil || got != want {
+		t.Errorf("want %v, got %v, %v", want, got, err)
+	}
+
+	c = &nameLookupChain{backends: []tin.ESSIDLookup{essidLookupMock{err: errors.New("error")}}}
+	if _, err := c.Lookup(); err == nil {
+		t.Errorf("want %v, got %v", "error", err)
+	}
+
+	c = &nameLookupChain{backends: []tin.ESSIDLookup{
+		essidLookupMock{err: ErrNotConnected},
+		essidLookupMock{err: errors.New("error")},
+	}}
+	if got, err := c.Lookup(); err != nil || got != "" {
+		t.Errorf("want %v, got %v, %v", "", got, err)
+	}
+}
+
+type essidLookupMock struct {
+	name tin.ESSID
+	err  error
+}
+
+func (e essidLookupMock) Lookup() (tin.ESSID, error) {
+	return e.name, e.err
+}
+
+func TestNameLookupBackends(t *testing.T) {
+	procNetWirelessPath = "testdata/wireless"
+	defer func() {
+		procNetWirelessPath = "/proc/net/wireless"
+		execCommand = exec.Command
+	}()
+
+	tests := []struct {
+		lookup tin.ESSIDLookup
+		fake   string
+	}{
+		{lookup: &iw{}, fake: "TestIWCommandSuccess"},
+		{lookup: &nmcli{}, fake: "TestNmcliCommandSuccess"},
+		{lookup: &iwctl{}, fake: "TestIwctlCommandSuccess"},
+	}
+
+	for _, tt := range tests {
+		execCommand = fakeExecCommand(tt.fake)
+
+		want := tin.ESSID("network-name")
+		got, err := tt.lookup.Lookup()
+		if err != nil || got != want {
+			t.Errorf("%v: want %v, got %v, %v", tt.fake, want, got, err)
+		}
+	}
+}
+
+func TestNameLookupBackendsNotConnected(t *testing.T) {
+	procNetWirelessPath = "testdata/wireless"
+	execCommand = fakeExecCommand("TestNotConnectedCommandSuccess")
+	defer func() {
+		procNetWirelessPath = "/proc/net/wireless"
+		execCommand = exec.Command
+	}()
+
+	for _, l := range []tin.ESSIDLookup{&iw{}, &nmcli{}, &iwctl{}} {
+		got, err := l.Lookup()
+		if !errors.Is(err, ErrNotConnected) || got != "" {
+			t.Errorf("want %v, got %v, %v", ErrNotConnected, got, err)
+		}
+	}
+}
+
+func TestNameLookupBackendsFailingInterface(t *testing.T) {
+	procNetWirelessPath = "testdata/wireless-two"
+	execCommand = fakeExecCommand("TestSecondInterfaceCommandSuccess")
+	defer func() {
+		procNetWirelessPath = "/proc/net/wireless"
+		execCommand = exec.Command
+	}()
+
+	for _, l := range []tin.ESSIDLookup{&iw{}, &iwctl{}} {
+		want := tin.ESSID("network-name")
+		got, err := l.Lookup()
+		if err != nil || got != want {
+			t.Errorf("want %v, got %v, %v", want, got, err)
+		}
+	}
+}
+
+func TestParseNmcliEscaped(t *testing.T) {
+	want := `network:name\`
+	got := parseNmcli("no:other\nyes:network\\:name\\\\\n")
+	if got != want {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+// writeFixture writes the fixture to standard output.
+func writeFixture(name string) {
+	data, err := ioutil.ReadFile("testdata/" + name)
+	if err != nil {
+		os.Exit(2)
+	}
+	os.Stdout.Write(data)
+}
+
+func TestIWCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	writeFixture("iw-link.txt")
+	os.Exit(0)
+}
+
+func TestNmcliCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Println("no:other-network")
+	fmt.Println("yes:network-name")
+	os.Exit(0)
+}
+
+func TestIwctlCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	writeFixture("iwctl-station.txt")
+	os.Exit(0)
+}
+
+func TestSecondInterfaceCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	// The first interface isn't managed by the backend.
+	args := os.Args[3:]
+	if args[2] == "wlan0" {
+		os.Exit(1)
+	}
+
+	if args[0] == "iw" {
+		writeFixture("iw-link.txt")
+	} else {
+		writeFixture("iwctl-station.txt")
+	}
+	os.Exit(0)
+}
+
+func TestNotConnectedCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Println("Not connected.")
+	os.Exit(0)
+}
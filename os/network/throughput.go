@@ -0,0 +1,69 @@
+package network
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+var procNetDevPath = "/proc/net/dev"
+
+// NewTrafficCounterReader returns a tin.TrafficCounterReader.
+func NewTrafficCounterReader() tin.TrafficCounterReader {
+	return &procNetDev{}
+}
+
+// procNetDev implements tin.TrafficCounterReader.
+type procNetDev struct{}
+
+// Read returns a slice of tin.TrafficCounter.
+func (p *procNetDev) Read() ([]tin.TrafficCounter, error) {
+	data, err := readFile(procNetDevPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.parse(string(data))
+}
+
+// parse parses the content of /proc/net/dev into a slice of tin.TrafficCounter.
+//
+// The first two lines are headers. The name is followed by 8 receive and
+// 8 transmit fields, the first field of both is the number of bytes.
+// Example of a line:  wlan0: 1461744873 1205328 0 0 0 0 0 0 64213431 471521 0 0 0 0 0 0
+func (p *procNetDev) parse(data string) ([]tin.TrafficCounter, error) {
+	counters := []tin.TrafficCounter{}
+	for i, v := range strings.Split(data, "\n") {
+		if i < 2 || strings.TrimSpace(v) == "" {
+			continue
+		}
+
+		sep := strings.Index(v, ":")
+		if sep < 0 {
+			return nil, fmt.Errorf("failed parsing %q: missing interface name", v)
+		}
+
+		f := strings.Fields(v[sep+1:])
+		if len(f) < 16 {
+			return nil, fmt.Errorf("failed parsing %q: expected 16 fields", v)
+		}
+
+		rx, err := strconv.ParseUint(f[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing %q: %w", v, err)
+		}
+		tx, err := strconv.ParseUint(f[8], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing %q: %w", v, err)
+		}
+
+		counters = append(counters, tin.TrafficCounter{
+			Interface: strings.TrimSpace(v[:sep]),
+			RxBytes:   rx,
+			TxBytes:   tx,
+		})
+	}
+	return counters, nil
+}
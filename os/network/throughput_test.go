@@ -0,0 +1,45 @@
+package network
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+func TestTrafficCounterReader(t *testing.T) {
+	procNetDevPath = "testdata/dev"
+	defer func() { procNetDevPath = "/proc/net/dev" }()
+
+	want := []tin.TrafficCounter{
+		{Interface: "lo", RxBytes: 286592, TxBytes: 286592},
+		{Interface: "wlan0", RxBytes: 1461744873, TxBytes: 64213431},
+	}
+
+	r := NewTrafficCounterReader()
+	got, err := r.Read()
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v, %v", want, got, err)
+	}
+}
+
+func TestTrafficCounterReaderError(t *testing.T) {
+	tests := []string{
+		"header\nheader\nwlan0 1 2 3\n",
+		"header\nheader\nwlan0: 1 2 3\n",
+		"header\nheader\nwlan0: x 0 0 0 0 0 0 0 1 0 0 0 0 0 0 0\n",
+	}
+
+	p := &procNetDev{}
+	for _, tt := range tests {
+		if _, got := p.parse(tt); got == nil {
+			t.Errorf("want %v, got %v", "error", got)
+		}
+	}
+
+	procNetDevPath = "testdata/missing"
+	defer func() { procNetDevPath = "/proc/net/dev" }()
+	if _, got := p.Read(); got == nil {
+		t.Errorf("want %v, got %v", "error", got)
+	}
+}
@@ -0,0 +1,248 @@
+package network
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+var fileExists = func(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+var netlinkRIB = syscall.NetlinkRIB
+
+// Represents the hardware types of /sys/class/net/<interface>/type.
+const (
+	arphrdEther = "1"
+	arphrdNone  = "65534"
+)
+
+// NewVPNLookup returns a tin.VPNLookup.
+func NewVPNLookup() tin.VPNLookup {
+	return &vpnLookupper{}
+}
+
+// vpnLookupper implements tin.VPNLookup.
+type vpnLookupper struct{}
+
+// Lookup returns a tin.VPN.
+//
+// The VPN that owns the default route is preferred over the other VPNs,
+// the zero value is returned when there is no active VPN.
+func (v *vpnLookupper) Lookup() (tin.VPN, error) {
+	ii, err := netInterfaces()
+	if err != nil {
+		return tin.VPN{}, err
+	}
+
+	// The routes of the main table, the routes of the other tables are
+	// added for policy routing, for example wg-quick uses table 51820.
+	routes := []tin.Route{}
+	if data, err := readFile(procNetRoutePath); err == nil {
+		routes = append(routes, parseRoutes(string(data))...)
+	}
+	if data, err := readFile(procNetIPv6RoutePath); err == nil {
+		routes = append(routes, parseIPv6Routes(string(data))...)
+	}
+	if data, err := netlinkRIB(syscall.RTM_GETROUTE, syscall.AF_UNSPEC); err == nil {
+		routes = append(routes, parseNetlinkRoutes(data, ii)...)
+	}
+
+	vpn := tin.VPN{}
+	for _, i := range ii {
+		if i.Flags&net.FlagUp == 0 {
+			continue
+		}
+
+		name := vpnKind(i.Name)
+		if name == "" {
+			continue
+		}
+
+		found := tin.VPN{Name: name, Interface: i.Name, DefaultRoute: ownsDefaultRoute(routes, i.Name)}
+		if name == "WireGuard" {
+			found.Endpoint, found.LatestHandshake = wireGuardPeer(i.Name)
+		}
+
+		if !vpn.Active() || (found.DefaultRoute && !vpn.DefaultRoute) {
+			vpn = found
+		}
+	}
+	return vpn, nil
+}
+
+// vpnKind returns the kind of VPN of the interface, or an empty string when it isn't a VPN.
+//
+// The kind is determined by the device type and hardware type of sysfs, tun
+// devices have tun_flags. The name of the interface is used when sysfs is unavailable.
+func vpnKind(name string) string {
+	dir := filepath.Join(sysClassNetPath, name)
+	hardware, err := readFile(filepath.Join(dir, "type"))
+	if err != nil {
+		switch {
+		case strings.HasPrefix(name, "wg"):
+			return "WireGuard"
+		case strings.HasPrefix(name, "tun"):
+			return "TUN"
+		case strings.HasPrefix(name, "tap"):
+			return "TAP"
+		default:
+			return ""
+		}
+	}
+
+	if uevent, err := readFile(filepath.Join(dir, "uevent")); err == nil {
+		for _, v := range strings.Split(string(uevent), "\n") {
+			if v == "DEVTYPE=wireguard" {
+				return "WireGuard"
+			}
+		}
+	}
+
+	if !fileExists(filepath.Join(dir, "tun_flags")) {
+		return ""
+	}
+	switch strings.TrimSpace(string(hardware)) {
+	case arphrdNone:
+		return "TUN"
+	case arphrdEther:
+		return "TAP"
+	default:
+		return ""
+	}
+}
+
+// parseNetlinkRoutes returns the routes of all tables of a route dump of netlink.
+//
+// Only the destination and interface of a route are parsed, routes without
+// an interface like blackhole routes are skipped.
+func parseNetlinkRoutes(data []byte, ii []net.Interface) []tin.Route {
+	msgs, err := syscall.ParseNetlinkMessage(data)
+	if err != nil {
+		return nil
+	}
+
+	names := map[uint32]string{}
+	for _, i := range ii {
+		names[uint32(i.Index)] = i.Name
+	}
+
+	routes := []tin.Route{}
+	for _, m := range msgs {
+		if m.Header.Type != syscall.RTM_NEWROUTE || len(m.Data) < syscall.SizeofRtMsg {
+			continue
+		}
+		rt := (*syscall.RtMsg)(unsafe.Pointer(&m.Data[0]))
+
+		attrs, err := syscall.ParseNetlinkRouteAttr(&m)
+		if err != nil {
+			continue
+		}
+
+		bits := 8 * net.IPv4len
+		if rt.Family == syscall.AF_INET6 {
+			bits = 8 * net.IPv6len
+		}
+		dst := make(net.IP, bits/8)
+		name := ""
+		for _, a := range attrs {
+			switch a.Attr.Type {
+			case syscall.RTA_DST:
+				if len(a.Value) == len(dst) {
+					dst = net.IP(a.Value)
+				}
+			case syscall.RTA_OIF:
+				if len(a.Value) == 4 {
+					name = names[nativeEndian.Uint32(a.Value)]
+				}
+			}
+		}
+		if name == "" {
+			continue
+		}
+
+		n := net.IPNet{IP: dst, Mask: net.CIDRMask(int(rt.Dst_len), bits)}
+		routes = append(routes, tin.Route{Destination: n.String(), Interface: name})
+	}
+	return routes
+}
+
+// nativeEndian is the byte order of the attributes of netlink.
+var nativeEndian = func() binary.ByteOrder {
+	v := uint16(1)
+	if *(*byte)(unsafe.Pointer(&v)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}()
+
+// ownsDefaultRoute reports whether the interface has a default route.
+//
+// VPNs commonly override the default route with the two halves of the
+// address space, for example OpenVPN with redirect-gateway def1, or add
+// it to the table of a routing policy like wg-quick.
+func ownsDefaultRoute(routes []tin.Route, name string) bool {
+	for _, r := range routes {
+		if r.Interface != name {
+			continue
+		}
+
+		switch r.Destination {
+		case "0.0.0.0/0", "0.0.0.0/1", "128.0.0.0/1", "::/0", "::/1", "8000::/1":
+			return true
+		}
+	}
+	return false
+}
+
+// wireGuardPeer returns the endpoint and latest handshake of the first peer of the interface.
+//
+// It uses wg, which requires privileges, the values are empty when it fails.
+func wireGuardPeer(name string) (string, time.Time) {
+	if _, err := lookPath("wg"); err != nil {
+		return "", time.Time{}
+	}
+
+	endpoints, err := execCommand("wg", "show", name, "endpoints").Output()
+	if err != nil {
+		return "", time.Time{}
+	}
+
+	endpoint := ""
+	if f := firstPeer(string(endpoints)); f != "(none)" {
+		endpoint = f
+	}
+
+	handshakes, err := execCommand("wg", "show", name, "latest-handshakes").Output()
+	if err != nil {
+		return endpoint, time.Time{}
+	}
+
+	seconds, err := strconv.ParseInt(firstPeer(string(handshakes)), 10, 64)
+	if err != nil || seconds == 0 {
+		return endpoint, time.Time{}
+	}
+	return endpoint, time.Unix(seconds, 0)
+}
+
+// firstPeer returns the value of the first peer of the output of wg show <interface> <field>.
+//
+// Example of a line: xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=	198.51.100.1:51820
+func firstPeer(output string) string {
+	for _, v := range strings.Split(output, "\n") {
+		if f := strings.Fields(v); len(f) == 2 {
+			return f[1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,196 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+func fakeVPNInterfaces() ([]net.Interface, error) {
+	return []net.Interface{
+		{Index: 2, MTU: 1500, Name: "wlan0", Flags: net.FlagUp},
+		{Index: 5, MTU: 1420, Name: "tun0", Flags: net.FlagUp},
+		{Index: 6, MTU: 1420, Name: "wg0", Flags: net.FlagUp},
+	}, nil
+}
+
+func fakeNetlinkRIBError(proto, family int) ([]byte, error) {
+	return nil, errors.New("netlink unavailable")
+}
+
+// fakeRouteMessage returns a netlink message of a route of the table.
+func fakeRouteMessage(family uint8, dst net.IP, dstLen uint8, table uint8, oif uint32) []byte {
+	attrs := []byte{}
+	if dst != nil {
+		attrs = append(attrs, fakeRouteAttr(syscall.RTA_DST, dst)...)
+	}
+	v := make([]byte, 4)
+	nativeEndian.PutUint32(v, oif)
+	attrs = append(attrs, fakeRouteAttr(syscall.RTA_OIF, v)...)
+
+	rt := syscall.RtMsg{Family: family, Dst_len: dstLen, Table: table, Type: syscall.RTN_UNICAST}
+	body := append((*[syscall.SizeofRtMsg]byte)(unsafe.Pointer(&rt))[:], attrs...)
+
+	h := syscall.NlMsghdr{Len: uint32(syscall.NLMSG_HDRLEN + len(body)), Type: syscall.RTM_NEWROUTE}
+	return append((*[syscall.NLMSG_HDRLEN]byte)(unsafe.Pointer(&h))[:], body...)
+}
+
+// fakeRouteAttr returns a route attribute padded to the alignment of netlink.
+func fakeRouteAttr(t uint16, v []byte) []byte {
+	a := syscall.RtAttr{Len: uint16(syscall.SizeofRtAttr + len(v)), Type: t}
+	data := append((*[syscall.SizeofRtAttr]byte)(unsafe.Pointer(&a))[:], v...)
+	for len(data)%syscall.NLMSG_ALIGNTO != 0 {
+		data = append(data, 0)
+	}
+	return data
+}
+
+func TestVPNLookup(t *testing.T) {
+	netInterfaces = fakeVPNInterfaces
+	sysClassNetPath = "testdata/net"
+	procNetRoutePath = "testdata/route-vpn"
+	procNetIPv6RoutePath = "testdata/missing"
+	netlinkRIB = fakeNetlinkRIBError
+	lookPath = fakeLookPathSuccess
+	execCommand = fakeExecCommand("TestWGCommandSuccess")
+	defer func() {
+		netInterfaces = net.Interfaces
+		sysClassNetPath = "/sys/class/net"
+		procNetRoutePath = "/proc/net/route"
+		procNetIPv6RoutePath = "/proc/net/ipv6_route"
+		netlinkRIB = syscall.NetlinkRIB
+		lookPath = exec.LookPath
+		execCommand = exec.Command
+	}()
+
+	want := tin.VPN{
+		Name:            "WireGuard",
+		Interface:       "wg0",
+		Endpoint:        "198.51.100.1:51820",
+		LatestHandshake: time.Unix(1700000000, 0),
+		DefaultRoute:    true,
+	}
+
+	l := NewVPNLookup()
+	got, err := l.Lookup()
+	if err != nil {
+		t.Errorf("want %v, got %v", nil, err)
+	}
+
+	if got != want {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestVPNLookupWithoutWG(t *testing.T) {
+	netInterfaces = fakeVPNInterfaces
+	sysClassNetPath = "testdata/net"
+	procNetRoutePath = "testdata/route"
+	netlinkRIB = fakeNetlinkRIBError
+	lookPath = fakeLookPathError
+	defer func() {
+		netInterfaces = net.Interfaces
+		sysClassNetPath = "/sys/class/net"
+		procNetRoutePath = "/proc/net/route"
+		netlinkRIB = syscall.NetlinkRIB
+		lookPath = exec.LookPath
+	}()
+
+	want := tin.VPN{Name: "TUN", Interface: "tun0"}
+
+	l := NewVPNLookup()
+	got, err := l.Lookup()
+	if err != nil || got != want {
+		t.Errorf("want %v, got %v, %v", want, got, err)
+	}
+}
+
+func TestVPNLookupPolicyRouting(t *testing.T) {
+	netInterfaces = fakeVPNInterfaces
+	sysClassNetPath = "testdata/net"
+	procNetRoutePath = "testdata/route"
+	lookPath = fakeLookPathError
+	// The default route of wg-quick is in the table of its fwmark rule.
+	netlinkRIB = func(proto, family int) ([]byte, error) {
+		return fakeRouteMessage(syscall.AF_INET, nil, 0, 252, 6), nil
+	}
+	defer func() {
+		netInterfaces = net.Interfaces
+		sysClassNetPath = "/sys/class/net"
+		procNetRoutePath = "/proc/net/route"
+		netlinkRIB = syscall.NetlinkRIB
+		lookPath = exec.LookPath
+	}()
+
+	want := tin.VPN{Name: "WireGuard", Interface: "wg0", DefaultRoute: true}
+
+	l := NewVPNLookup()
+	got, err := l.Lookup()
+	if err != nil || got != want {
+		t.Errorf("want %v, got %v, %v", want, got, err)
+	}
+}
+
+func TestParseNetlinkRoutes(t *testing.T) {
+	ii, _ := fakeVPNInterfaces()
+	data := append(fakeRouteMessage(syscall.AF_INET, net.IPv4(128, 0, 0, 0).To4(), 1, 254, 5),
+		fakeRouteMessage(syscall.AF_INET6, nil, 0, 252, 6)...)
+	// A route of an unknown interface.
+	data = append(data, fakeRouteMessage(syscall.AF_INET, net.IPv4(10, 0, 0, 0).To4(), 8, 254, 9)...)
+
+	want := []tin.Route{
+		{Destination: "128.0.0.0/1", Interface: "tun0"},
+		{Destination: "::/0", Interface: "wg0"},
+	}
+	got := parseNetlinkRoutes(data, ii)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestVPNKind(t *testing.T) {
+	tests := []struct {
+		path string
+		name string
+		want string
+	}{
+		{path: "testdata/net", name: "wg0", want: "WireGuard"},
+		{path: "testdata/net", name: "tun0", want: "TUN"},
+		{path: "testdata/net", name: "wlan0", want: ""},
+		{path: "testdata/missing", name: "tap0", want: "TAP"},
+		{path: "testdata/missing", name: "eth0", want: ""},
+	}
+
+	defer func() { sysClassNetPath = "/sys/class/net" }()
+	for _, tt := range tests {
+		sysClassNetPath = tt.path
+		if got := vpnKind(tt.name); got != tt.want {
+			t.Errorf("%v: want %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestWGCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	switch os.Args[len(os.Args)-1] {
+	case "endpoints":
+		fmt.Println("xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=\t198.51.100.1:51820")
+	case "latest-handshakes":
+		fmt.Println("xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg=\t1700000000")
+	default:
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,159 @@
+package network
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+// wirelessLinkMaxQuality is the maximum link quality of /proc/net/wireless.
+const wirelessLinkMaxQuality = 70
+
+// NewWirelessLinkLookup returns a tin.WirelessLinkLookup.
+//
+// If iw isn't installed it will return nil.
+func NewWirelessLinkLookup() tin.WirelessLinkLookup {
+	if _, err := lookPath("iw"); err != nil {
+		return nil
+	}
+	return &wirelessLinkLookupper{}
+}
+
+// wirelessLinkLookupper implements tin.WirelessLinkLookup.
+type wirelessLinkLookupper struct{}
+
+// Lookup returns a tin.WirelessLink.
+//
+// It uses iw to fetch the link of the first connected wireless interface,
+// the zero value is returned when none is connected. Interfaces that fail
+// are skipped, for example an interface that is being removed, an error is
+// returned when all of them fail. The connected time is optional, failing
+// to read the station leaves it empty.
+func (w *wirelessLinkLookupper) Lookup() (tin.WirelessLink, error) {
+	interfaces, err := wirelessInterfaces()
+	if err != nil {
+		return tin.WirelessLink{}, err
+	}
+
+	failed := 0
+	for _, name := range interfaces {
+		output, e := execCommand("iw", "dev", name, "link").Output()
+		if e != nil {
+			failed++
+			err = fmt.Errorf("%v: %w", name, e)
+			continue
+		}
+
+		link := parseIWLinkDetails(string(output))
+		if !link.Connected() {
+			continue
+		}
+
+		link.Interface = name
+		link.Quality = signalQuality(link.Signal)
+		if quality, err := wirelessQuality(name); err == nil {
+			link.Quality = quality
+		}
+		if output, err := execCommand("iw", "dev", name, "station", "dump").Output(); err == nil {
+			if d := parseConnectedTime(string(output)); d > 0 {
+				link.ConnectedSince = time.Now().Add(-d).Truncate(time.Second)
+			}
+		}
+		return link, nil
+	}
+
+	if failed > 0 && failed == len(interfaces) {
+		return tin.WirelessLink{}, err
+	}
+	return tin.WirelessLink{}, nil
+}
+
+// parseIWLinkDetails parses the output of iw dev <interface> link into a tin.WirelessLink.
+//
+// The BSSID is on the first line, the other properties are indented.
+// Example of a line: 	signal: -50 dBm
+func parseIWLinkDetails(output string) tin.WirelessLink {
+	link := tin.WirelessLink{}
+	for _, v := range strings.Split(output, "\n") {
+		v = strings.TrimSpace(v)
+		f := strings.Fields(v)
+		switch {
+		case strings.HasPrefix(v, "Connected to ") && len(f) >= 3:
+			link.BSSID = f[2]
+		case strings.HasPrefix(v, "SSID: "):
+			link.SSID = strings.TrimPrefix(v, "SSID: ")
+		case strings.HasPrefix(v, "freq: ") && len(f) >= 2:
+			freq, _ := strconv.ParseFloat(f[1], 64)
+			link.Frequency = int(freq)
+		case strings.HasPrefix(v, "signal: ") && len(f) >= 2:
+			link.Signal, _ = strconv.Atoi(f[1])
+		case strings.HasPrefix(v, "tx bitrate: ") && len(f) >= 3:
+			link.TxBitrate, _ = strconv.ParseFloat(f[2], 64)
+		}
+	}
+	return link
+}
+
+// parseConnectedTime returns the connected time of the output of iw dev <interface> station dump.
+//
+// Example of a line: 	connected time:	3600 seconds
+func parseConnectedTime(output string) time.Duration {
+	for _, v := range strings.Split(output, "\n") {
+		v = strings.TrimSpace(v)
+		if !strings.HasPrefix(v, "connected time:") {
+			continue
+		}
+
+		f := strings.Fields(strings.TrimPrefix(v, "connected time:"))
+		if len(f) == 0 {
+			return 0
+		}
+		seconds, _ := strconv.Atoi(f[0])
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
+
+// wirelessQuality returns the link quality percentage of the interface in /proc/net/wireless.
+//
+// Example of a line:  wlan0: 0000   60.  -50.  -256        0      0      0      0     12        0
+func wirelessQuality(name string) (int, error) {
+	data, err := readFile(procNetWirelessPath)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, v := range strings.Split(string(data), "\n") {
+		f := strings.Fields(v)
+		if len(f) < 3 || f[0] != name+":" {
+			continue
+		}
+
+		q, err := strconv.ParseFloat(strings.TrimSuffix(f[2], "."), 64)
+		if err != nil {
+			return 0, err
+		}
+		return clampPercentage(int(q * 100 / wirelessLinkMaxQuality)), nil
+	}
+	return 0, fmt.Errorf("interface %v not found", name)
+}
+
+// signalQuality returns the percentage of the signal, -100 dBm is 0% and -50 dBm is 100%.
+func signalQuality(dBm int) int {
+	return clampPercentage(2 * (dBm + 100))
+}
+
+// clampPercentage returns the value limited to the range of 0 to 100.
+func clampPercentage(v int) int {
+	switch {
+	case v < 0:
+		return 0
+	case v > 100:
+		return 100
+	default:
+		return v
+	}
+}
@@ -0,0 +1,149 @@
+package network
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+func TestNewWirelessLinkLookup(t *testing.T) {
+	defer func() { lookPath = exec.LookPath }()
+
+	lookPath = fakeLookPathSuccess
+	if got := NewWirelessLinkLookup(); got == nil {
+		t.Errorf("want %v, got %v", "tin.WirelessLinkLookup", got)
+	}
+
+	lookPath = fakeLookPathError
+	if got := NewWirelessLinkLookup(); got != nil {
+		t.Errorf("want %v, got %v", nil, got)
+	}
+}
+
+func TestWirelessLinkLookup(t *testing.T) {
+	procNetWirelessPath = "testdata/wireless"
+	execCommand = fakeExecCommand("TestIWDevCommandSuccess")
+	defer func() {
+		procNetWirelessPath = "/proc/net/wireless"
+		execCommand = exec.Command
+	}()
+
+	want := tin.WirelessLink{
+		Interface: "wlan0",
+		SSID:      "network-name",
+		BSSID:     "3c:22:fb:12:34:56",
+		Frequency: 5180,
+		Signal:    -50,
+		Quality:   85,
+		TxBitrate: 866.7,
+	}
+
+	l := &wirelessLinkLookupper{}
+	got, err := l.Lookup()
+	if err != nil {
+		t.Errorf("want %v, got %v", nil, err)
+	}
+
+	if since := time.Since(got.ConnectedSince); since < time.Hour || since > time.Hour+time.Minute {
+		t.Errorf("want %v, got %v", time.Hour, since)
+	}
+
+	got.ConnectedSince = time.Time{}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestWirelessLinkLookupNotConnected(t *testing.T) {
+	procNetWirelessPath = "testdata/wireless"
+	execCommand = fakeExecCommand("TestNotConnectedCommandSuccess")
+	defer func() {
+		procNetWirelessPath = "/proc/net/wireless"
+		execCommand = exec.Command
+	}()
+
+	l := &wirelessLinkLookupper{}
+	got, err := l.Lookup()
+	if err != nil || got.Connected() {
+		t.Errorf("want %v, got %v, %v", tin.WirelessLink{}, got, err)
+	}
+}
+
+func TestWirelessLinkLookupFailingInterface(t *testing.T) {
+	procNetWirelessPath = "testdata/wireless-two"
+	execCommand = fakeExecCommand("TestIWDevSecondInterfaceCommandSuccess")
+	defer func() {
+		procNetWirelessPath = "/proc/net/wireless"
+		execCommand = exec.Command
+	}()
+
+	l := &wirelessLinkLookupper{}
+	got, err := l.Lookup()
+	if err != nil || got.Interface != "wlan1" {
+		t.Errorf("want %v, got %v, %v", "wlan1", got.Interface, err)
+	}
+
+	// The only interface fails.
+	procNetWirelessPath = "testdata/wireless"
+	if _, err := l.Lookup(); err == nil {
+		t.Errorf("want error, got nil")
+	}
+}
+
+func TestSignalQuality(t *testing.T) {
+	tests := []struct {
+		dBm  int
+		want int
+	}{
+		{dBm: -30, want: 100},
+		{dBm: -70, want: 60},
+		{dBm: -110, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := signalQuality(tt.dBm); got != tt.want {
+			t.Errorf("want %v, got %v", tt.want, got)
+		}
+	}
+}
+
+func TestIWDevCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	switch os.Args[len(os.Args)-1] {
+	case "link":
+		writeFixture("iw-link.txt")
+	case "dump":
+		writeFixture("iw-station.txt")
+	default:
+		fmt.Println("unknown command")
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func TestIWDevSecondInterfaceCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	// The first interface is being removed.
+	if os.Args[5] == "wlan0" {
+		os.Exit(1)
+	}
+
+	switch os.Args[len(os.Args)-1] {
+	case "link":
+		writeFixture("iw-link.txt")
+	case "dump":
+		writeFixture("iw-station.txt")
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,142 @@
+package packagemanager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+// CommandTimeout is the maximum duration of a package manager command.
+var CommandTimeout = 5 * time.Minute
+
+// commandEnv is appended to the environment of every command, so the output
+// is parsable regardless of the locale and color settings of the user.
+var commandEnv = []string{"LC_ALL=C", "LANG=C", "NO_COLOR=1", "TERM=dumb"}
+
+// commandMutex prevents the commands that write to a package database from
+// running concurrently, see writesDatabase.
+var commandMutex sync.Mutex
+
+// Represents the lock files of the package databases.
+var (
+	pacmanLockPath = "/var/lib/pacman/db.lck"
+	xbpsLockPath   = "/var/db/xbps/lock"
+	dpkgLockPaths  = []string{"/var/lib/dpkg/lock-frontend", "/var/lib/dpkg/lock"}
+)
+
+var fileLocked = fcntlLocked
+
+// command returns the exec.Cmd to execute the named program with commandEnv.
+func command(name string, args ...string) *exec.Cmd {
+	cmd := execCommand(name, args...)
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, commandEnv...)
+	return cmd
+}
+
+// run executes the named program and returns its standard output.
+//
+// The standard error is available as the Stderr of an exec.ExitError.
+// Commands that write to a package database don't run concurrently, queries
+// don't wait for them. Commands fail with tin.ErrLocked when the package
+// database is locked by another process. The process group of the command
+// is killed when it exceeds the CommandTimeout.
+func run(name string, args ...string) ([]byte, error) {
+	if writesDatabase(name) {
+		commandMutex.Lock()
+		defer commandMutex.Unlock()
+	}
+
+	if err := checkLock(name); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), CommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := command(name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case err := <-done:
+		var e *exec.ExitError
+		if errors.As(err, &e) {
+			e.Stderr = stderr.Bytes()
+		}
+		return stdout.Bytes(), err
+	case <-ctx.Done():
+		// Killing the process group, so children like the database sync of checkupdates are killed as well.
+		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		<-done
+		return nil, fmt.Errorf("%v timed out after %v: %w", name, CommandTimeout, ctx.Err())
+	}
+}
+
+// writesDatabase reports whether the program writes to a package database or
+// metadata cache, for example checkupdates syncs its own copy of the pacman
+// databases and dnf refreshes its metadata.
+func writesDatabase(name string) bool {
+	return name == "checkupdates" || name == "xbps-install" || name == "dnf" || isAURHelper(name)
+}
+
+// checkLock returns tin.ErrLocked when the package database used by the program is locked.
+//
+// Pacman holds its lock by the existence of the lock file, xbps and dpkg
+// hold a lock on the file. Checkupdates isn't affected by the lock of pacman,
+// it syncs a temporary copy of the databases.
+func checkLock(name string) error {
+	switch {
+	case name == "pacman" || isAURHelper(name):
+		if _, err := osStat(pacmanLockPath); err == nil {
+			return fmt.Errorf("%v: %w", pacmanLockPath, tin.ErrLocked)
+		}
+	case strings.HasPrefix(name, "xbps-"):
+		if fileLocked(xbpsLockPath) {
+			return fmt.Errorf("%v: %w", xbpsLockPath, tin.ErrLocked)
+		}
+	case strings.HasPrefix(name, "apt-") || strings.HasPrefix(name, "dpkg"):
+		for _, p := range dpkgLockPaths {
+			if fileLocked(p) {
+				return fmt.Errorf("%v: %w", p, tin.ErrLocked)
+			}
+		}
+	}
+	return nil
+}
+
+// fcntlLocked reports whether another process holds a write lock on the file.
+//
+// A file that can't be opened is considered unlocked.
+func fcntlLocked(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	lock := syscall.Flock_t{Type: syscall.F_WRLCK, Whence: 0}
+	if err := syscall.FcntlFlock(f.Fd(), syscall.F_GETLK, &lock); err != nil {
+		return false
+	}
+	return lock.Type != syscall.F_UNLCK
+}
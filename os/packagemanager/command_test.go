@@ -0,0 +1,113 @@
+package packagemanager
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+func TestRunTimeout(t *testing.T) {
+	execCommand = fakeExecCommand("TestSleepCommand")
+	CommandTimeout = 100 * time.Millisecond
+	defer func() {
+		execCommand = exec.Command
+		CommandTimeout = 5 * time.Minute
+	}()
+
+	start := time.Now()
+	_, err := run("checkupdates")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("want %v, got %v", CommandTimeout, d)
+	}
+}
+
+func TestRunLocked(t *testing.T) {
+	execCommand = fakeExecCommand("TestArchCommandSuccess")
+	defer func() { execCommand = exec.Command }()
+
+	lock, err := ioutil.TempFile("", "db.lck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(lock.Name())
+
+	pacmanLockPath = lock.Name()
+	fileLocked = func(string) bool { return true }
+	defer func() {
+		pacmanLockPath = "/var/lib/pacman/db.lck"
+		fileLocked = fcntlLocked
+	}()
+
+	for _, name := range []string{"pacman", "xbps-query", "apt-get", "dpkg-query"} {
+		_, got := run(name)
+		if !errors.Is(got, tin.ErrLocked) {
+			t.Errorf("%v: want %v, got %v", name, tin.ErrLocked, got)
+		}
+	}
+
+	// Checkupdates uses a temporary copy of the databases.
+	for _, name := range []string{"checkupdates", "dnf"} {
+		if _, got := run(name); got != nil {
+			t.Errorf("%v: want %v, got %v", name, nil, got)
+		}
+	}
+}
+
+func TestRunConcurrentQuery(t *testing.T) {
+	execCommand = fakeExecCommand("TestArchCommandSuccess")
+	defer func() { execCommand = exec.Command }()
+
+	// A command that writes to a database is running.
+	commandMutex.Lock()
+	defer commandMutex.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := run("pacman", "-Qi", "linux")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("want %v, got %v", nil, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("want query, got timeout")
+	}
+}
+
+func TestFcntlLocked(t *testing.T) {
+	f, err := ioutil.TempFile("", "lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	if got := fcntlLocked(f.Name()); got {
+		t.Errorf("want %v, got %v", false, got)
+	}
+
+	if got := fcntlLocked("testdata/missing"); got {
+		t.Errorf("want %v, got %v", false, got)
+	}
+}
+
+func TestSleepCommand(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	time.Sleep(time.Minute)
+	os.Exit(0)
+}
@@ -0,0 +1,318 @@
+package packagemanager
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+// dpkgInfoPath is the directory containing the file lists of installed deb packages.
+var dpkgInfoPath = "/var/lib/dpkg/info"
+
+// Details returns a slice of tin.PackageDetails.
+//
+// Pacman exits with code 1 when a package isn't installed, the details of the
+// other packages are printed nonetheless. Packages that aren't installed are
+// omitted, like with the other package managers.
+func (a *Arch) Details(names ...string) ([]tin.PackageDetails, error) {
+	output, err := run("pacman", append([]string{"-Qi"}, names...)...)
+	var e *exec.ExitError
+	if errors.As(err, &e) && e.ExitCode() == 1 && bytes.Contains(e.Stderr, []byte("was not found")) {
+		return a.parseDetails(string(output)), nil
+	}
+	if err != nil {
+		return []tin.PackageDetails{}, err
+	}
+
+	return a.parseDetails(string(output)), nil
+}
+
+// parseDetails parses the output of pacman -Qi into a slice of tin.PackageDetails.
+//
+// Packages are separated by a blank line, every line contains a field.
+// Values that span multiple lines are indented.
+// Example of a line: Installed Size  : 80.35 MiB
+func (a *Arch) parseDetails(output string) []tin.PackageDetails {
+	dd := []tin.PackageDetails{}
+	for _, block := range strings.Split(output, "\n\n") {
+		fields := map[string]string{}
+		key := ""
+		for _, v := range strings.Split(block, "\n") {
+			i := strings.Index(v, " : ")
+			if i < 0 || strings.HasPrefix(v, " ") {
+				if key != "" {
+					fields[key] += " " + strings.TrimSpace(v)
+				}
+				continue
+			}
+
+			key = strings.TrimSpace(v[:i])
+			fields[key] = strings.TrimSpace(v[i+3:])
+		}
+
+		if fields["Name"] == "" {
+			continue
+		}
+
+		d := tin.PackageDetails{
+			Package:      tin.Package{Name: fields["Name"], Version: fields["Version"]},
+			Description:  fields["Description"],
+			Explicit:     fields["Install Reason"] == "Explicitly installed",
+			Dependencies: dependencyNames(strings.Fields(fields["Depends On"])),
+			RequiredBy:   dependencyNames(strings.Fields(fields["Required By"])),
+		}
+		d.InstalledSize, _ = parseSize(fields["Installed Size"])
+		d.InstallDate = parseDate(fields["Install Date"],
+			"Mon Jan _2 15:04:05 2006",
+			"Mon 02 Jan 2006 03:04:05 PM MST",
+			"Mon 02 Jan 2006 15:04:05 MST",
+		)
+		dd = append(dd, d)
+	}
+	return dd
+}
+
+// xbpsProperties are the properties of the installed packages of the details.
+var xbpsProperties = []string{"short_desc", "installed_size", "install-date", "automatic-install", "run_depends"}
+
+// Details returns a slice of tin.PackageDetails.
+//
+// Every property is queried for all installed packages at once, with a
+// search that matches every package, so the number of commands doesn't
+// depend on the number of packages. The reverse dependencies are derived
+// from the dependencies of every installed package.
+func (x *XBPS) Details(names ...string) ([]tin.PackageDetails, error) {
+	properties := map[string]map[string][]string{}
+	for _, p := range xbpsProperties {
+		output, err := run("xbps-query", "-p", p, "-s", "")
+		if err != nil {
+			return []tin.PackageDetails{}, fmt.Errorf("failed querying %v: %w", p, err)
+		}
+		properties[p] = x.parseProperty(string(output))
+	}
+
+	all := map[string]tin.PackageDetails{}
+	requiredBy := map[string][]string{}
+	for pkgver := range properties["installed_size"] {
+		i := strings.LastIndex(pkgver, "-")
+		if i < 1 {
+			continue
+		}
+
+		first := func(p string) string {
+			if v := properties[p][pkgver]; len(v) > 0 {
+				return v[0]
+			}
+			return ""
+		}
+
+		d := tin.PackageDetails{
+			Package:      tin.Package{Name: pkgver[:i], Version: pkgver[i+1:]},
+			Description:  first("short_desc"),
+			Explicit:     first("automatic-install") != "true",
+			Dependencies: dependencyNames(properties["run_depends"][pkgver]),
+			RequiredBy:   []string{},
+		}
+		d.InstalledSize, _ = parseSize(first("installed_size"))
+		d.InstallDate = parseDate(first("install-date"), "2006-01-02 15:04 MST")
+
+		for _, dep := range d.Dependencies {
+			requiredBy[dep] = append(requiredBy[dep], d.Name)
+		}
+		all[d.Name] = d
+	}
+
+	dd := []tin.PackageDetails{}
+	for _, n := range names {
+		d, ok := all[n]
+		if !ok {
+			continue
+		}
+		if r, ok := requiredBy[n]; ok {
+			sort.Strings(r)
+			d.RequiredBy = r
+		}
+		dd = append(dd, d)
+	}
+	return dd, nil
+}
+
+// parseProperty parses the output of a xbps-query property search into the
+// values of the property by pkgver.
+//
+// Every line contains a value, a list has a line for every item.
+// Example of a line: linux5.6-5.6.10_1: kmod>=11_1 (https://alpha.de.repo.voidlinux.org/current)
+func (x *XBPS) parseProperty(output string) map[string][]string {
+	values := map[string][]string{}
+	for _, v := range lines(output) {
+		i := strings.Index(v, ": ")
+		if i < 1 {
+			continue
+		}
+
+		value := v[i+2:]
+		if j := strings.LastIndex(value, " ("); j >= 0 && strings.HasSuffix(value, ")") {
+			value = value[:j]
+		}
+		values[v[:i]] = append(values[v[:i]], value)
+	}
+	return values
+}
+
+// dpkgFormat is the format of dpkg-query, fields are separated by tabs.
+const dpkgFormat = "${Package}\t${Version}\t${Installed-Size}\t${db:Status-Abbrev}\t${Depends}\t${Pre-Depends}\t${binary:Summary}\n"
+
+// Details returns a slice of tin.PackageDetails.
+//
+// The reverse dependencies are derived from the dependencies of every installed
+// package and the install date from the file list of the package.
+func (a *Apt) Details(names ...string) ([]tin.PackageDetails, error) {
+	output, err := run("dpkg-query", "-W", "-f="+dpkgFormat)
+	if err != nil {
+		return []tin.PackageDetails{}, err
+	}
+
+	manual, err := run("apt-mark", "showmanual")
+	if err != nil {
+		return []tin.PackageDetails{}, err
+	}
+
+	all := a.parseDetails(string(output), strings.Fields(string(manual)))
+
+	wanted := map[string]bool{}
+	for _, n := range names {
+		wanted[n] = true
+	}
+
+	dd := []tin.PackageDetails{}
+	for _, d := range all {
+		if !wanted[d.Name] {
+			continue
+		}
+
+		for _, p := range []string{d.Name + ".list", d.Name + ":*.list"} {
+			if m, _ := glob(filepath.Join(dpkgInfoPath, p)); len(m) > 0 {
+				if info, err := osStat(m[0]); err == nil {
+					d.InstallDate = info.ModTime()
+				}
+				break
+			}
+		}
+		dd = append(dd, d)
+	}
+	return dd, nil
+}
+
+// parseDetails parses the output of dpkg-query into a slice of tin.PackageDetails.
+//
+// Packages that aren't installed are ignored, the installed size is in KiB.
+// Example of a line: openssl	1.1.1d-0+deb10u3	1460	ii 	libc6 (>= 2.15), libssl1.1 (>= 1.1.1)		Secure Sockets Layer toolkit
+func (a *Apt) parseDetails(output string, manual []string) []tin.PackageDetails {
+	explicit := map[string]bool{}
+	for _, m := range manual {
+		explicit[m] = true
+	}
+
+	dd := []tin.PackageDetails{}
+	requiredBy := map[string][]string{}
+	for _, v := range strings.Split(output, "\n") {
+		f := strings.Split(v, "\t")
+		if len(f) != 7 || !strings.HasPrefix(f[3], "ii") {
+			continue
+		}
+
+		deps := []string{}
+		for _, dep := range strings.Split(f[5]+","+f[4], ",") {
+			// Only the first alternative is considered a dependency.
+			dep = strings.TrimSpace(strings.Split(dep, "|")[0])
+			if dep == "" {
+				continue
+			}
+			dep = strings.Fields(dep)[0]
+			dep = strings.TrimSuffix(dep, ":any")
+			deps = append(deps, dep)
+			requiredBy[dep] = append(requiredBy[dep], f[0])
+		}
+
+		size, _ := strconv.ParseInt(f[2], 10, 64)
+		dd = append(dd, tin.PackageDetails{
+			Package:       tin.Package{Name: f[0], Version: f[1]},
+			Description:   f[6],
+			InstalledSize: size * 1024,
+			Explicit:      explicit[f[0]],
+			Dependencies:  deps,
+		})
+	}
+
+	for i, d := range dd {
+		dd[i].RequiredBy = requiredBy[d.Name]
+		if dd[i].RequiredBy == nil {
+			dd[i].RequiredBy = []string{}
+		}
+	}
+	return dd
+}
+
+// dependencyNames removes the version constraints from the dependencies.
+//
+// Pacman uses None for an empty list.
+// Example of a dependency: glibc>=2.31
+func dependencyNames(deps []string) []string {
+	nn := []string{}
+	for _, d := range deps {
+		if d == "None" {
+			continue
+		}
+
+		if i := strings.IndexAny(d, "<>="); i > 0 {
+			d = d[:i]
+		}
+		nn = append(nn, d)
+	}
+	return nn
+}
+
+// parseSize parses a human-readable size into bytes.
+//
+// Both binary (KiB) and short (KB) units are interpreted as powers of 1024.
+// Example of a size: 80.35 MiB
+func parseSize(v string) (int64, error) {
+	v = strings.ReplaceAll(strings.TrimSpace(v), " ", "")
+	i := strings.IndexFunc(v, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i < 0 {
+		i = len(v)
+	}
+
+	n, err := strconv.ParseFloat(v[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %v: %w", v, err)
+	}
+
+	units := map[string]float64{"": 1, "B": 1, "K": 1 << 10, "M": 1 << 20, "G": 1 << 30, "T": 1 << 40}
+	unit, ok := units[strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(v[i:]), "B"), "I")]
+	if !ok {
+		return 0, fmt.Errorf("invalid size unit %v", v)
+	}
+
+	return int64(n * unit), nil
+}
+
+// parseDate parses the value using the first matching layout.
+//
+// It returns a zero time.Time when none of the layouts matches.
+func parseDate(v string, layouts ...string) time.Time {
+	for _, l := range layouts {
+		if t, err := time.ParseInLocation(l, v, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
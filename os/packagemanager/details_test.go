@@ -0,0 +1,233 @@
+package packagemanager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+func TestArchDetails(t *testing.T) {
+	execCommand = fakeExecCommand("TestDetailsCommandSuccess")
+	defer func() { execCommand = exec.Command }()
+
+	a := &Arch{}
+	got, err := a.Details("linux", "kmod")
+	if err != nil {
+		t.Fatalf("want %v, got %v", nil, err)
+	}
+
+	want := []tin.PackageDetails{
+		{
+			Package:       tin.Package{Name: "linux", Version: "5.6.10.arch1-1"},
+			Description:   "The Linux kernel and modules",
+			InstalledSize: 84253081,
+			InstallDate:   time.Date(2020, 5, 4, 10, 0, 0, 0, time.Local),
+			Explicit:      true,
+			Dependencies:  []string{"coreutils", "kmod", "initramfs"},
+			RequiredBy:    []string{},
+		},
+		{
+			Package:       tin.Package{Name: "kmod", Version: "27-2"},
+			Description:   "Linux kernel module management tools and library",
+			InstalledSize: 344064,
+			InstallDate:   time.Date(2020, 3, 5, 12, 0, 0, 0, time.Local),
+			Explicit:      false,
+			Dependencies:  []string{"glibc", "zlib", "openssl", "xz"},
+			RequiredBy:    []string{"linux", "mkinitcpio", "systemd"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestArchDetailsNotInstalled(t *testing.T) {
+	execCommand = fakeExecCommand("TestDetailsNotInstalledCommand")
+	defer func() { execCommand = exec.Command }()
+
+	got, err := (&Arch{}).Details("missing")
+	if err != nil {
+		t.Fatalf("want %v, got %v", nil, err)
+	}
+	if len(got) != 0 {
+		t.Errorf("want %v, got %v", 0, len(got))
+	}
+}
+
+func TestXBPSDetails(t *testing.T) {
+	execCommand = fakeExecCommand("TestDetailsCommandSuccess")
+	defer func() { execCommand = exec.Command }()
+
+	x := &XBPS{}
+	got, err := x.Details("linux5.6")
+	if err != nil {
+		t.Fatalf("want %v, got %v", nil, err)
+	}
+
+	want := []tin.PackageDetails{
+		{
+			Package:       tin.Package{Name: "linux5.6", Version: "5.6.10_1"},
+			Description:   "Linux kernel and modules (5.6 series)",
+			InstalledSize: 83886080,
+			InstallDate:   time.Date(2020, 5, 2, 11, 0, 0, 0, time.UTC),
+			Explicit:      false,
+			Dependencies:  []string{"kmod", "xz"},
+			RequiredBy:    []string{"linux"},
+		},
+	}
+	if len(got) != 1 || !got[0].InstallDate.Equal(want[0].InstallDate) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	got[0].InstallDate = want[0].InstallDate
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestAptDetails(t *testing.T) {
+	execCommand = fakeExecCommand("TestDetailsCommandSuccess")
+	defer func() { execCommand = exec.Command }()
+
+	dir, err := ioutil.TempDir("", "dpkg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(dir+"/libssl1.1:amd64.list", []byte{}, 0644)
+	installed := time.Date(2020, 5, 1, 10, 0, 0, 0, time.Local)
+	os.Chtimes(dir+"/libssl1.1:amd64.list", installed, installed)
+
+	dpkgInfoPath = dir
+	defer func() { dpkgInfoPath = "/var/lib/dpkg/info" }()
+
+	a := &Apt{}
+	got, err := a.Details("libssl1.1", "openssl")
+	if err != nil {
+		t.Fatalf("want %v, got %v", nil, err)
+	}
+
+	want := []tin.PackageDetails{
+		{
+			Package:       tin.Package{Name: "openssl", Version: "1.1.1d-0+deb10u3"},
+			Description:   "Secure Sockets Layer toolkit - cryptographic utility",
+			InstalledSize: 1460 * 1024,
+			Explicit:      true,
+			Dependencies:  []string{"libc6", "libssl1.1"},
+			RequiredBy:    []string{},
+		},
+		{
+			Package:       tin.Package{Name: "libssl1.1", Version: "1.1.1d-0+deb10u3"},
+			Description:   "Secure Sockets Layer toolkit - shared libraries",
+			InstalledSize: 4100 * 1024,
+			InstallDate:   installed,
+			Explicit:      false,
+			Dependencies:  []string{"libc6", "debconf"},
+			RequiredBy:    []string{"openssl"},
+		},
+	}
+	if len(got) == 2 && got[1].InstallDate.Equal(installed) {
+		got[1].InstallDate = installed
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestDetailsError(t *testing.T) {
+	tests := []tin.PackageDetailer{&Arch{}, &XBPS{}, &Apt{}}
+
+	for _, pm := range tests {
+		execCommand = fakeExecCommand("TestCommandError")
+
+		_, got := pm.Details("package")
+		if got == nil {
+			t.Errorf("want %v, got %v", "error", got)
+		}
+
+		execCommand = exec.Command
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		v    string
+		want int64
+	}{
+		{v: "512 B", want: 512},
+		{v: "336.00 KiB", want: 344064},
+		{v: "1017KB", want: 1041408},
+		{v: "1.5 GiB", want: 1610612736},
+		{v: "42", want: 42},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSize(tt.v)
+		if err != nil || got != tt.want {
+			t.Errorf("%v: want %v, got %v, %v", tt.v, tt.want, got, err)
+		}
+	}
+
+	for _, v := range []string{"", "MiB", "12 parsecs"} {
+		if _, err := parseSize(v); err == nil {
+			t.Errorf("%v: want %v, got %v", v, "error", err)
+		}
+	}
+}
+
+func TestDetailsNotInstalledCommand(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, "error: package 'missing' was not found")
+	os.Exit(1)
+}
+
+func TestDetailsCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fixtures := map[string]string{
+		"pacman":     "testdata/pacman-qi.txt",
+		"dpkg-query": "testdata/dpkg-query.txt",
+	}
+
+	// Output of xbps-query -p property -s "" by property.
+	xbpsProperties := map[string]string{
+		"short_desc": `linux5.6-5.6.10_1: Linux kernel and modules (5.6 series) ((null))
+linux-5.6_1: The Linux kernel and modules (meta package) ((null))`,
+		"installed_size": `linux5.6-5.6.10_1: 80MB ((null))
+linux-5.6_1: 512B ((null))`,
+		"install-date": `linux5.6-5.6.10_1: 2020-05-02 11:00 UTC ((null))
+linux-5.6_1: 2020-05-02 11:00 UTC ((null))`,
+		"automatic-install": `linux5.6-5.6.10_1: true ((null))`,
+		"run_depends": `linux5.6-5.6.10_1: kmod>=11_1 ((null))
+linux5.6-5.6.10_1: xz>=0 ((null))
+linux-5.6_1: linux5.6>=0 ((null))`,
+	}
+
+	args := fakeCommandArgs()
+	switch {
+	case args[0] == "xbps-query" && args[1] == "-p":
+		fmt.Println(xbpsProperties[args[2]])
+		os.Exit(0)
+	case args[0] == "apt-mark":
+		fmt.Println("openssl")
+		os.Exit(0)
+	}
+
+	data, err := ioutil.ReadFile(fixtures[args[0]])
+	if err != nil {
+		os.Exit(1)
+	}
+
+	fmt.Print(string(data))
+	os.Exit(0)
+}
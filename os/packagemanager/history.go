@@ -0,0 +1,272 @@
+package packagemanager
+
+import (
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+var osStat = os.Stat
+var readFile = ioutil.ReadFile
+
+// Represents the default log paths.
+const (
+	pacmanLogPath     = "/var/log/pacman.log"
+	xbpsLogPath       = "/var/log/socklog/xbps/current"
+	dpkgLogPath       = "/var/log/dpkg.log"
+	aptHistoryLogPath = "/var/log/apt/history.log"
+)
+
+// NewHistoryReader returns a tin.PackageHistoryReader.
+//
+// If a supported log couldn't be resolved it will return nil.
+func NewHistoryReader() tin.PackageHistoryReader {
+	if _, err := osStat(pacmanLogPath); err == nil {
+		return &PacmanHistory{Path: pacmanLogPath}
+	}
+
+	if _, err := osStat(xbpsLogPath); err == nil {
+		return &XBPSHistory{Path: xbpsLogPath}
+	}
+
+	if _, err := osStat(dpkgLogPath); err == nil {
+		return &DpkgHistory{Path: dpkgLogPath, AptPath: aptHistoryLogPath}
+	}
+
+	return nil
+}
+
+// PacmanHistory implements tin.PackageHistoryReader.
+type PacmanHistory struct {
+	Path string
+}
+
+// pacmanLogLine matches a line of the pacman log.
+//
+// Example of a line: [2020-05-01T10:00:00+0200] [ALPM] upgraded name (1.0-1 -> 1.1-1)
+// Example of a line: [2019-01-01 10:00] [ALPM] installed name (1.0-1)
+var pacmanLogLine = regexp.MustCompile(`^\[([^\]]+)\] \[([A-Z-]+)\] (.*)$`)
+
+// pacmanLogAction matches the ALPM action of a line of the pacman log.
+var pacmanLogAction = regexp.MustCompile(`^(installed|upgraded|downgraded|removed) (\S+) \((.+)\)$`)
+
+// History returns a tin.PackageHistory.
+//
+// A full system upgrade is recorded by pacman as "starting full system upgrade".
+// Lines that can't be parsed are ignored.
+func (p *PacmanHistory) History() (tin.PackageHistory, error) {
+	bytes, err := readFile(p.Path)
+	if err != nil {
+		return tin.PackageHistory{}, err
+	}
+
+	h := tin.PackageHistory{Events: []tin.PackageEvent{}, FullUpgrades: []time.Time{}}
+	for _, v := range strings.Split(string(bytes), "\n") {
+		m := pacmanLogLine.FindStringSubmatch(v)
+		if m == nil {
+			continue
+		}
+
+		t, err := p.parseTime(m[1])
+		if err != nil {
+			continue
+		}
+
+		if m[2] == "PACMAN" && m[3] == "starting full system upgrade" {
+			h.FullUpgrades = append(h.FullUpgrades, t)
+			continue
+		}
+
+		a := pacmanLogAction.FindStringSubmatch(m[3])
+		if m[2] != "ALPM" || a == nil {
+			continue
+		}
+
+		e := tin.PackageEvent{Time: t, Action: tin.PackageAction(a[1]), Name: a[2]}
+		switch e.Action {
+		case tin.PackageInstalled:
+			e.To = a[3]
+		case tin.PackageRemoved:
+			e.From = a[3]
+		default:
+			versions := strings.SplitN(a[3], " -> ", 2)
+			if len(versions) != 2 {
+				continue
+			}
+			e.From, e.To = versions[0], versions[1]
+		}
+		h.Events = append(h.Events, e)
+	}
+
+	return h, nil
+}
+
+// parseTime parses the timestamp of a line of the pacman log.
+//
+// Pacman 5.1 changed the format from local time without seconds to ISO 8601.
+func (p *PacmanHistory) parseTime(v string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02T15:04:05-0700", v); err == nil {
+		return t, nil
+	}
+
+	return time.ParseInLocation("2006-01-02 15:04", v, time.Local)
+}
+
+// XBPSHistory implements tin.PackageHistoryReader.
+//
+// XBPS logs to syslog, it assumes the log is written by socklog.
+type XBPSHistory struct {
+	Path string
+}
+
+// xbpsLogLine matches a line of the xbps log.
+//
+// Example of a line: 2020-05-01T10:00:00.12345 user.notice: xbps-install: Updated `name-1.1_1' successfully (rootdir: /)
+var xbpsLogLine = regexp.MustCompile("^(\\S+) \\S+: xbps-[a-z]+: (Installed|Updated|Removed) `([^']+)' successfully")
+
+// History returns a tin.PackageHistory.
+//
+// XBPS only logs the new version, the previous version is derived from earlier
+// events in the log. There is no record of a full system upgrade, so every
+// moment at which packages have been updated is considered one.
+// Lines that can't be parsed are ignored.
+func (x *XBPSHistory) History() (tin.PackageHistory, error) {
+	bytes, err := readFile(x.Path)
+	if err != nil {
+		return tin.PackageHistory{}, err
+	}
+
+	h := tin.PackageHistory{Events: []tin.PackageEvent{}, FullUpgrades: []time.Time{}}
+	versions := map[string]string{}
+	for _, v := range strings.Split(string(bytes), "\n") {
+		m := xbpsLogLine.FindStringSubmatch(v)
+		if m == nil {
+			continue
+		}
+
+		t, err := time.Parse("2006-01-02T15:04:05.999999999", m[1])
+		if err != nil {
+			continue
+		}
+
+		i := strings.LastIndex(m[3], "-")
+		if i < 1 {
+			continue
+		}
+		name, version := m[3][:i], m[3][i+1:]
+
+		e := tin.PackageEvent{Time: t, Name: name}
+		switch m[2] {
+		case "Installed":
+			e.Action, e.To = tin.PackageInstalled, version
+			versions[name] = version
+		case "Updated":
+			e.Action, e.From, e.To = tin.PackageUpgraded, versions[name], version
+			versions[name] = version
+			if n := len(h.FullUpgrades); n == 0 || t.Sub(h.FullUpgrades[n-1]) > time.Hour {
+				h.FullUpgrades = append(h.FullUpgrades, t)
+			}
+		case "Removed":
+			e.Action, e.From = tin.PackageRemoved, version
+			delete(versions, name)
+		}
+		h.Events = append(h.Events, e)
+	}
+
+	return h, nil
+}
+
+// DpkgHistory implements tin.PackageHistoryReader.
+//
+// The package events are read from the dpkg log, the full system upgrades
+// from the apt history log.
+type DpkgHistory struct {
+	Path    string
+	AptPath string
+}
+
+// History returns a tin.PackageHistory.
+//
+// Example of a line: 2020-05-01 10:00:00 upgrade name:amd64 1.0-1 1.1-1
+// Lines that can't be parsed are ignored, a missing apt history log is not an error.
+func (d *DpkgHistory) History() (tin.PackageHistory, error) {
+	bytes, err := readFile(d.Path)
+	if err != nil {
+		return tin.PackageHistory{}, err
+	}
+
+	h := tin.PackageHistory{Events: []tin.PackageEvent{}, FullUpgrades: []time.Time{}}
+	for _, v := range strings.Split(string(bytes), "\n") {
+		f := strings.Fields(v)
+		if len(f) != 6 {
+			continue
+		}
+
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", f[0]+" "+f[1], time.Local)
+		if err != nil {
+			continue
+		}
+
+		e := tin.PackageEvent{Time: t, Name: strings.SplitN(f[3], ":", 2)[0]}
+		switch f[2] {
+		case "install":
+			e.Action, e.To = tin.PackageInstalled, f[5]
+		case "upgrade":
+			e.Action, e.From, e.To = tin.PackageUpgraded, f[4], f[5]
+		case "remove":
+			e.Action, e.From = tin.PackageRemoved, f[4]
+		default:
+			continue
+		}
+		h.Events = append(h.Events, e)
+	}
+
+	if d.AptPath == "" {
+		return h, nil
+	}
+
+	bytes, err = readFile(d.AptPath)
+	if err != nil {
+		return h, nil
+	}
+	h.FullUpgrades = d.parseAptHistory(string(bytes))
+
+	return h, nil
+}
+
+// parseAptHistory returns the start dates of the apt upgrade commands.
+//
+// Example of an entry:
+// Start-Date: 2020-05-01  10:00:00
+// Commandline: apt-get dist-upgrade
+func (d *DpkgHistory) parseAptHistory(output string) []time.Time {
+	tt := []time.Time{}
+	var start time.Time
+	for _, v := range strings.Split(output, "\n") {
+		if strings.HasPrefix(v, "Start-Date: ") {
+			f := strings.Fields(strings.TrimPrefix(v, "Start-Date: "))
+			if len(f) != 2 {
+				start = time.Time{}
+				continue
+			}
+			start, _ = time.ParseInLocation("2006-01-02 15:04:05", f[0]+" "+f[1], time.Local)
+			continue
+		}
+
+		if !strings.HasPrefix(v, "Commandline: ") || start.IsZero() {
+			continue
+		}
+
+		for _, a := range strings.Fields(v)[1:] {
+			if a == "upgrade" || a == "dist-upgrade" || a == "full-upgrade" {
+				tt = append(tt, start)
+				break
+			}
+		}
+	}
+	return tt
+}
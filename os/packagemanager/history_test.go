@@ -0,0 +1,156 @@
+package packagemanager
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+func fakeOsStat(path string) func(name string) (os.FileInfo, error) {
+	return func(name string) (os.FileInfo, error) {
+		if name != path {
+			return nil, errors.New("file doesn't exists")
+		}
+
+		return nil, nil
+	}
+}
+
+func TestNewHistoryReader(t *testing.T) {
+	tests := []struct {
+		want       tin.PackageHistoryReader
+		fakeOsStat func(name string) (os.FileInfo, error)
+	}{
+		{
+			want:       &PacmanHistory{},
+			fakeOsStat: fakeOsStat(pacmanLogPath),
+		},
+		{
+			want:       &XBPSHistory{},
+			fakeOsStat: fakeOsStat(xbpsLogPath),
+		},
+		{
+			want:       &DpkgHistory{},
+			fakeOsStat: fakeOsStat(dpkgLogPath),
+		},
+		{
+			want:       nil,
+			fakeOsStat: fakeOsStat(""),
+		},
+	}
+
+	for _, tt := range tests {
+		osStat = tt.fakeOsStat
+
+		got := reflect.TypeOf(NewHistoryReader())
+		want := reflect.TypeOf(tt.want)
+		if got != want {
+			t.Errorf("want %v, got %v", want, got)
+		}
+
+		osStat = os.Stat
+	}
+}
+
+func TestPacmanHistory(t *testing.T) {
+	r := &PacmanHistory{Path: "testdata/pacman.log"}
+	got, err := r.History()
+	if err != nil {
+		t.Fatalf("want %v, got %v", nil, err)
+	}
+
+	zone := time.FixedZone("", 2*60*60)
+	want := tin.PackageHistory{
+		Events: []tin.PackageEvent{
+			{Time: time.Date(2019, 1, 1, 10, 0, 0, 0, time.Local), Action: tin.PackageInstalled, Name: "legacy", To: "1.0-1"},
+			{Time: time.Date(2020, 5, 1, 10, 0, 5, 0, zone), Action: tin.PackageUpgraded, Name: "linux", From: "5.6.8.arch1-1", To: "5.6.10.arch1-1"},
+			{Time: time.Date(2020, 5, 1, 10, 0, 6, 0, zone), Action: tin.PackageDowngraded, Name: "firefox", From: "76.0-1", To: "75.0-2"},
+			{Time: time.Date(2020, 5, 3, 18, 30, 2, 0, zone), Action: tin.PackageInstalled, Name: "htop", To: "2.2.0-3"},
+			{Time: time.Date(2020, 5, 4, 9, 15, 1, 0, zone), Action: tin.PackageRemoved, Name: "nano", From: "4.9.2-1"},
+		},
+		FullUpgrades: []time.Time{time.Date(2020, 5, 1, 10, 0, 1, 0, zone)},
+	}
+	assertHistory(t, want, got)
+}
+
+func TestXBPSHistory(t *testing.T) {
+	r := &XBPSHistory{Path: "testdata/xbps.log"}
+	got, err := r.History()
+	if err != nil {
+		t.Fatalf("want %v, got %v", nil, err)
+	}
+
+	want := tin.PackageHistory{
+		Events: []tin.PackageEvent{
+			{Time: time.Date(2020, 5, 1, 8, 0, 0, 123450000, time.UTC), Action: tin.PackageInstalled, Name: "htop", To: "2.2.0_1"},
+			{Time: time.Date(2020, 5, 1, 8, 0, 0, 234560000, time.UTC), Action: tin.PackageInstalled, Name: "linux5.6", To: "5.6.8_1"},
+			{Time: time.Date(2020, 5, 2, 8, 0, 0, 123450000, time.UTC), Action: tin.PackageUpgraded, Name: "linux5.6", From: "5.6.8_1", To: "5.6.10_1"},
+			{Time: time.Date(2020, 5, 2, 8, 0, 1, 123450000, time.UTC), Action: tin.PackageUpgraded, Name: "htop", From: "2.2.0_1", To: "2.2.0_2"},
+			{Time: time.Date(2020, 5, 3, 8, 0, 0, 123450000, time.UTC), Action: tin.PackageRemoved, Name: "htop", From: "2.2.0_2"},
+		},
+		FullUpgrades: []time.Time{time.Date(2020, 5, 2, 8, 0, 0, 123450000, time.UTC)},
+	}
+	assertHistory(t, want, got)
+}
+
+func TestDpkgHistory(t *testing.T) {
+	r := &DpkgHistory{Path: "testdata/dpkg.log", AptPath: "testdata/apt-history.log"}
+	got, err := r.History()
+	if err != nil {
+		t.Fatalf("want %v, got %v", nil, err)
+	}
+
+	want := tin.PackageHistory{
+		Events: []tin.PackageEvent{
+			{Time: time.Date(2020, 5, 1, 10, 0, 1, 0, time.Local), Action: tin.PackageUpgraded, Name: "openssl", From: "1.1.1f-1", To: "1.1.1g-1"},
+			{Time: time.Date(2020, 5, 3, 18, 30, 0, 0, time.Local), Action: tin.PackageInstalled, Name: "htop", To: "2.2.0-2"},
+			{Time: time.Date(2020, 5, 4, 9, 15, 0, 0, time.Local), Action: tin.PackageRemoved, Name: "nano", From: "4.8-1"},
+		},
+		FullUpgrades: []time.Time{time.Date(2020, 5, 1, 10, 0, 0, 0, time.Local)},
+	}
+	assertHistory(t, want, got)
+}
+
+func TestHistoryError(t *testing.T) {
+	tests := []tin.PackageHistoryReader{
+		&PacmanHistory{Path: "testdata/missing.log"},
+		&XBPSHistory{Path: "testdata/missing.log"},
+		&DpkgHistory{Path: "testdata/missing.log"},
+	}
+
+	for _, r := range tests {
+		_, got := r.History()
+		if got == nil {
+			t.Errorf("want %v, got %v", "error", got)
+		}
+	}
+}
+
+func assertHistory(t *testing.T, want tin.PackageHistory, got tin.PackageHistory) {
+	t.Helper()
+
+	if len(got.Events) != len(want.Events) {
+		t.Fatalf("want %v, got %v", want.Events, got.Events)
+	}
+
+	for i, e := range want.Events {
+		g := got.Events[i]
+		if !g.Time.Equal(e.Time) || g.Action != e.Action || g.Name != e.Name || g.From != e.From || g.To != e.To {
+			t.Errorf("want %v, got %v", e, g)
+		}
+	}
+
+	if len(got.FullUpgrades) != len(want.FullUpgrades) {
+		t.Fatalf("want %v, got %v", want.FullUpgrades, got.FullUpgrades)
+	}
+
+	for i, u := range want.FullUpgrades {
+		if !got.FullUpgrades[i].Equal(u) {
+			t.Errorf("want %v, got %v", u, got.FullUpgrades[i])
+		}
+	}
+}
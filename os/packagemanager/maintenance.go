@@ -0,0 +1,135 @@
+package packagemanager
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+var walk = filepath.Walk
+
+// Represents the package cache paths.
+var (
+	pacmanCachePath = "/var/cache/pacman/pkg"
+	xbpsCachePath   = "/var/cache/xbps"
+	aptCachePath    = "/var/cache/apt/archives"
+)
+
+// Orphans returns a slice of tin.Package.
+//
+// Pacman exits with code 1 when there are no orphans.
+func (a *Arch) Orphans() ([]tin.Package, error) {
+	return pacmanQuery("-Qdt")
+}
+
+// Foreign returns a slice of tin.Package.
+//
+// Foreign packages are usually installed from the AUR.
+func (a *Arch) Foreign() ([]tin.Package, error) {
+	return foreignPackages()
+}
+
+// CacheSize returns the size of the pacman package cache in bytes.
+func (a *Arch) CacheSize() (int64, error) {
+	return dirSize(pacmanCachePath)
+}
+
+// foreignPackages returns the packages that aren't in the sync databases,
+// tagged with tin.PackageSourceAUR.
+func foreignPackages() ([]tin.Package, error) {
+	packages, err := pacmanQuery("-Qm")
+	for i := range packages {
+		packages[i].Source = tin.PackageSourceAUR
+	}
+	return packages, err
+}
+
+// pacmanQuery returns the packages of a pacman query.
+//
+// Pacman exits with code 1 and without output when no package matches the query.
+func pacmanQuery(flags string) ([]tin.Package, error) {
+	output, err := run("pacman", flags)
+	if err != nil {
+		var e *exec.ExitError
+		if errors.As(err, &e) && e.ExitCode() == 1 && len(output) == 0 {
+			return []tin.Package{}, nil
+		}
+		return []tin.Package{}, err
+	}
+
+	return parsePackages(string(output))
+}
+
+// Orphans returns a slice of tin.Package.
+//
+// Example of a line: package-name-3.5.2_1 remove x86_64 https://alpha.de.repo.voidlinux.org/current 182572180
+func (x *XBPS) Orphans() ([]tin.Package, error) {
+	output, err := run("xbps-remove", "-o", "-n")
+	if err != nil {
+		return []tin.Package{}, err
+	}
+
+	return x.parse(string(output))
+}
+
+// CacheSize returns the size of the xbps package cache in bytes.
+func (x *XBPS) CacheSize() (int64, error) {
+	return dirSize(xbpsCachePath)
+}
+
+// aptRemvLine matches a line of a simulated apt-get autoremove.
+//
+// Example of a line: Remv libfoo1 [1.0-1]
+var aptRemvLine = regexp.MustCompile(`^Remv (\S+)(?: \[([^\]]*)\])?`)
+
+// Orphans returns a slice of tin.Package.
+func (a *Apt) Orphans() ([]tin.Package, error) {
+	output, err := run("apt-get", "-s", "-o", "Debug::NoLocking=1", "autoremove")
+	if err != nil {
+		return []tin.Package{}, err
+	}
+
+	pp := []tin.Package{}
+	for _, v := range strings.Split(string(output), "\n") {
+		if m := aptRemvLine.FindStringSubmatch(v); m != nil {
+			pp = append(pp, tin.Package{Name: m[1], Version: m[2]})
+		}
+	}
+	return pp, nil
+}
+
+// CacheSize returns the size of the apt package cache in bytes.
+func (a *Apt) CacheSize() (int64, error) {
+	return dirSize(aptCachePath)
+}
+
+// dirSize returns the total size of the regular files in the directory in bytes.
+//
+// Directories that can't be read are skipped, for example the partial
+// directory of apt which is only readable by the _apt user.
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil && os.IsPermission(err) {
+			if info != nil && info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+
+	return size, err
+}
@@ -0,0 +1,168 @@
+package packagemanager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+func TestOrphans(t *testing.T) {
+	tests := []struct {
+		pm              tin.OrphanLister
+		fakeExecCommand func(name string, args ...string) *exec.Cmd
+		want            []tin.Package
+	}{
+		{
+			pm:              &Arch{},
+			fakeExecCommand: fakeExecCommand("TestArchCommandSuccess"),
+			want:            []tin.Package{{Name: "package-name", Version: "3.5.2-1"}},
+		},
+		{
+			pm:              &Arch{},
+			fakeExecCommand: fakeExecCommand("TestCommandError"),
+			want:            []tin.Package{},
+		},
+		{
+			pm:              &XBPS{},
+			fakeExecCommand: fakeExecCommand("TestXBPSOrphansCommandSuccess"),
+			want:            []tin.Package{{Name: "package-name", Version: "3.5.2_1"}},
+		},
+		{
+			pm:              &Apt{},
+			fakeExecCommand: fakeExecCommand("TestAptOrphansCommandSuccess"),
+			want:            []tin.Package{{Name: "libfoo1", Version: "1.0-1"}, {Name: "libbar2", Version: ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		execCommand = tt.fakeExecCommand
+
+		got, err := tt.pm.Orphans()
+		if err != nil {
+			t.Errorf("want %v, got %v", nil, err)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("want %v, got %v", tt.want, got)
+		}
+
+		execCommand = exec.Command
+	}
+}
+
+func TestOrphansError(t *testing.T) {
+	tests := []tin.OrphanLister{&XBPS{}, &Apt{}}
+
+	for _, pm := range tests {
+		execCommand = fakeExecCommand("TestCommandExitCode2")
+
+		_, got := pm.Orphans()
+		if got == nil {
+			t.Errorf("want %v, got %v", "error", got)
+		}
+
+		execCommand = exec.Command
+	}
+}
+
+func TestForeign(t *testing.T) {
+	execCommand = fakeExecCommand("TestArchCommandSuccess")
+	defer func() { execCommand = exec.Command }()
+
+	a := &Arch{}
+	want := []tin.Package{{Name: "package-name", Version: "3.5.2-1", Source: tin.PackageSourceAUR}}
+	got, err := a.Foreign()
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v, %v", want, got, err)
+	}
+}
+
+func TestCacheSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	os.MkdirAll(filepath.Join(dir, "partial"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "a.pkg.tar.zst"), make([]byte, 100), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "partial", "b.deb"), make([]byte, 23), 0644)
+
+	pacmanCachePath, xbpsCachePath, aptCachePath = dir, dir, dir
+	defer func() {
+		pacmanCachePath, xbpsCachePath, aptCachePath = "/var/cache/pacman/pkg", "/var/cache/xbps", "/var/cache/apt/archives"
+	}()
+
+	tests := []tin.CacheSizer{&Arch{}, &XBPS{}, &Apt{}}
+	for _, pm := range tests {
+		want := int64(123)
+		got, err := pm.CacheSize()
+		if err != nil || got != want {
+			t.Errorf("want %v, got %v, %v", want, got, err)
+		}
+	}
+}
+
+func TestCacheSizePermission(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	os.MkdirAll(filepath.Join(dir, "partial"), 0700)
+	ioutil.WriteFile(filepath.Join(dir, "a.deb"), make([]byte, 100), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "partial", "b.deb"), make([]byte, 23), 0644)
+
+	// The partial directory can't be read, like as a user other than _apt.
+	walk = func(root string, fn filepath.WalkFunc) error {
+		return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if filepath.Base(path) == "partial" {
+				err = &os.PathError{Op: "open", Path: path, Err: os.ErrPermission}
+			}
+			return fn(path, info, err)
+		})
+	}
+	defer func() { walk = filepath.Walk }()
+
+	want := int64(100)
+	got, err := dirSize(dir)
+	if err != nil || got != want {
+		t.Errorf("want %v, got %v, %v", want, got, err)
+	}
+}
+
+func TestCacheSizeError(t *testing.T) {
+	_, got := dirSize("testdata/missing")
+	if got == nil {
+		t.Errorf("want %v, got %v", "error", got)
+	}
+}
+
+func TestXBPSOrphansCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Println("package-name-3.5.2_1 remove x86_64 https://alpha.de.repo.voidlinux.org/current 182572180")
+	os.Exit(0)
+}
+
+func TestAptOrphansCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Println("Reading package lists...")
+	fmt.Println("The following packages will be REMOVED:")
+	fmt.Println("  libbar2 libfoo1")
+	fmt.Println("Remv libfoo1 [1.0-1]")
+	fmt.Println("Remv libbar2")
+	os.Exit(0)
+}
@@ -2,7 +2,9 @@ package packagemanager
 
 import (
 	"errors"
+	"fmt"
 	"os/exec"
+	"regexp"
 	"strings"
 
 	"github.com/sjengpho/tin/tin"
@@ -11,19 +13,62 @@ import (
 var execCommand = exec.Command
 var lookPath = exec.LookPath
 
+// ParseError represents a line of output that couldn't be parsed.
+type ParseError struct {
+	Line   string
+	Reason string
+}
+
+// Error implements error.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failed parsing %q: %v", e.Line, e.Reason)
+}
+
+// ansiEscape matches the escape sequences of colored output.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// lines returns the non-blank lines of the output without colors.
+//
+// Progress messages of pacman and the AUR helpers start with :: and are ignored.
+func lines(output string) []string {
+	ll := []string{}
+	for _, v := range strings.Split(ansiEscape.ReplaceAllString(output, ""), "\n") {
+		v = strings.TrimSpace(v)
+		if v == "" || strings.HasPrefix(v, "::") {
+			continue
+		}
+		ll = append(ll, v)
+	}
+	return ll
+}
+
 // New returns a tin.PackageManager.
 //
+// The securityFeed is the URL or path of the Arch Linux security advisories,
+// the aurHelper is the name of the AUR helper, see NewAURHelper.
 // If a manager couldn't be resolved it will return nil.
-func New() tin.PackageManager {
+func New(securityFeed string, aurHelper string) tin.PackageManager {
 	if _, err := lookPath("xbps-install"); err == nil {
 		return &XBPS{}
 	}
 
 	if _, err := lookPath("checkupdates"); err == nil {
-		return &Arch{
-			Pacman: Pacman{},
-			AUR:    &Yay{},
+		a := &Arch{
+			Pacman:     Pacman{},
+			Advisories: NewArchAdvisories(securityFeed),
+		}
+		if h := NewAURHelper(aurHelper); h != nil {
+			a.AUR = h
 		}
+		return a
+	}
+
+	if _, err := lookPath("apt-get"); err == nil {
+		return &Apt{}
+	}
+
+	if _, err := lookPath("dnf"); err == nil {
+		return &Dnf{}
 	}
 
 	return nil
@@ -34,76 +79,167 @@ type XBPS struct{}
 
 // AvailableUpdates returns a slice of tin.Package.
 func (x *XBPS) AvailableUpdates() ([]tin.Package, error) {
-	output, err := execCommand("xbps-install", "-Mun").Output()
+	output, err := run("xbps-install", "-Mun")
 	if err != nil {
 		return []tin.Package{}, err
 	}
 
-	return x.parse(string(output)), nil
+	return x.parse(string(output))
 }
 
 // Installed returns a slice of tin.Package.
 func (x *XBPS) Installed() ([]tin.Package, error) {
-	output, err := execCommand("xbps-query", "-m").Output()
+	output, err := run("xbps-query", "-m")
 	if err != nil {
 		return []tin.Package{}, err
 	}
 
-	return x.parse(string(output)), nil
+	return x.parse(string(output))
 }
 
 // parse parses the string into a slice of tin.Package.
 //
 // It assumes that the output contains a multiline string of packages,
-// separated by newlines. Blank lines are ignored.
+// separated by newlines. Blank lines are ignored, lines that couldn't
+// be parsed are returned as tin.Warnings.
 // Example of a line: package-name-3.5.2_1
-func (x *XBPS) parse(output string) []tin.Package {
+func (x *XBPS) parse(output string) ([]tin.Package, error) {
 	pp := []tin.Package{}
-	for _, v := range strings.Split(output, "\n") {
-		if v == "" {
+	var warnings tin.Warnings
+	for _, v := range lines(output) {
+		p := strings.Fields(v)[0]      // Removing everything after the first white space.
+		i := strings.LastIndex(p, "-") // Getting the index of the separator between the package name and version.
+		if i < 1 || i == len(p)-1 {
+			warnings = append(warnings, &ParseError{Line: v, Reason: "missing version"})
 			continue
 		}
 
-		p := strings.Split(v, " ")[0]  // Removing everything after the first white space.
-		i := strings.LastIndex(p, "-") // Getting the index of the separator between the package name and version.
 		pp = append(pp, tin.Package{
 			Name:    p[:i],   // Extracting everything from the begin until the index of the separator.
 			Version: p[i+1:], // Extracting everything after the index of the separator until the end.
 		})
 	}
-	return pp
+	return pp, warnings.Err()
 }
 
-// Arch implements tin.PackageManager.
+// Arch implements tin.PackageManager and tin.UpdateClassifier.
+//
+// The AUR is optional, without it only the updates of the official
+// repositories are available.
 type Arch struct {
-	Pacman Pacman
-	AUR    tin.PackageManager
+	Pacman     Pacman
+	AUR        tin.PackageManager
+	Advisories *ArchAdvisories
 }
 
 // AvailableUpdates returns a slice of tin.Package.
 func (a *Arch) AvailableUpdates() ([]tin.Package, error) {
+	var warnings tin.Warnings
 	pacmanPackages, err := a.Pacman.AvailableUpdates()
-	if err != nil {
+	if err != nil && !tin.IsPartial(err) {
 		return []tin.Package{}, err
 	}
+	warnings = warnings.Append(err)
+
+	if a.AUR == nil {
+		return pacmanPackages, warnings.Err()
+	}
 
+	// Failing AUR updates don't affect the pacman updates.
 	aurPackages, err := a.AUR.AvailableUpdates()
+	if err != nil && !tin.IsPartial(err) {
+		err = fmt.Errorf("failed fetching AUR updates: %w", err)
+	}
+	warnings = warnings.Append(err)
+
+	return append(pacmanPackages, aurPackages...), warnings.Err()
+}
+
+// ClassifiedUpdates returns a slice of tin.Update.
+//
+// The pacman updates are classified using the security advisories,
+// the AUR updates are unclassified.
+func (a *Arch) ClassifiedUpdates() ([]tin.Update, error) {
+	output, err := run("checkupdates")
 	if err != nil {
-		return []tin.Package{}, err
+		var e *exec.ExitError
+		// Assuming exit code 2 means no updates.
+		if !errors.As(err, &e) || e.ExitCode() != 2 {
+			return []tin.Update{}, err
+		}
 	}
 
-	return append(pacmanPackages, aurPackages...), nil
+	var warnings tin.Warnings
+	updates, err := parseUpdates(string(output))
+	warnings = warnings.Append(err)
+	if a.Advisories != nil {
+		updates = a.Advisories.Classify(updates)
+	}
+
+	if a.AUR == nil {
+		return updates, warnings.Err()
+	}
+
+	// Failing AUR updates don't affect the pacman updates.
+	aurUpdates, err := a.aurUpdates()
+	if err != nil && !tin.IsPartial(err) {
+		err = fmt.Errorf("failed fetching AUR updates: %w", err)
+	}
+	warnings = warnings.Append(err)
+
+	return append(updates, aurUpdates...), warnings.Err()
+}
+
+// aurUpdates returns the unclassified updates of the AUR helper.
+//
+// The installed version is only known when the helper implements tin.UpdateClassifier.
+func (a *Arch) aurUpdates() ([]tin.Update, error) {
+	if c, ok := a.AUR.(tin.UpdateClassifier); ok {
+		return c.ClassifiedUpdates()
+	}
+
+	packages, err := a.AUR.AvailableUpdates()
+	uu := []tin.Update{}
+	for _, p := range packages {
+		uu = append(uu, tin.Update{Name: p.Name, To: p.Version, Kind: tin.UpdateUnclassified})
+	}
+	return uu, err
 }
 
 // Installed returns a slice of tin.Package.
+//
+// Foreign packages are tagged with tin.PackageSourceAUR, a failure to list
+// them leaves the packages untagged.
 func (a *Arch) Installed() ([]tin.Package, error) {
-	p := Pacman{}
-	packages, err := p.Installed()
-	if err != nil {
+	var warnings tin.Warnings
+	packages, err := a.Pacman.Installed()
+	if err != nil && !tin.IsPartial(err) {
 		return []tin.Package{}, err
 	}
+	warnings = warnings.Append(err)
 
-	return packages, nil
+	var foreign []tin.Package
+	if a.AUR != nil {
+		foreign, err = a.AUR.Installed()
+	} else {
+		foreign, err = foreignPackages()
+	}
+	if err != nil && !tin.IsPartial(err) {
+		err = fmt.Errorf("failed listing foreign packages: %w", err)
+	}
+	warnings = warnings.Append(err)
+
+	aur := map[string]bool{}
+	for _, p := range foreign {
+		aur[p.Name] = true
+	}
+	for i, p := range packages {
+		if aur[p.Name] {
+			packages[i].Source = tin.PackageSourceAUR
+		}
+	}
+
+	return packages, warnings.Err()
 }
 
 // Pacman implements tin.PackageManager.
@@ -111,7 +247,7 @@ type Pacman struct{}
 
 // AvailableUpdates returns a slice of tin.Package.
 func (p *Pacman) AvailableUpdates() ([]tin.Package, error) {
-	output, err := execCommand("checkupdates").Output()
+	output, err := run("checkupdates")
 	if err != nil {
 		var e *exec.ExitError
 		// Assuming exit code 2 means no updates.
@@ -121,75 +257,339 @@ func (p *Pacman) AvailableUpdates() ([]tin.Package, error) {
 		return []tin.Package{}, err
 	}
 
-	return p.parse(string(output)), nil
+	return parsePackages(string(output))
 }
 
 // Installed returns a slice of tin.Package.
 func (p *Pacman) Installed() ([]tin.Package, error) {
-	output, err := execCommand("pacman", "-Qe").Output()
+	output, err := run("pacman", "-Qe")
 	if err != nil {
 		return []tin.Package{}, err
 	}
 
-	return p.parse(string(output)), nil
+	return parsePackages(string(output))
 }
 
-// parse parses the string into a slice of tin.Package.
+// AURHelper implements tin.PackageManager and tin.UpdateClassifier
+// for the packages from the AUR.
+type AURHelper struct {
+	Name string   // Name of the executable.
+	Args []string // Arguments to list the available AUR updates.
+}
+
+// AURHelpers are the supported AUR helpers in order of preference.
+var AURHelpers = []AURHelper{
+	{Name: "yay", Args: []string{"-Qum"}},
+	{Name: "paru", Args: []string{"-Qum"}},
+	{Name: "pikaur", Args: []string{"-Qua"}},
+}
+
+// NewAURHelper returns the AUR helper with the name.
 //
-// It assumes that the output contains a multiline string of packages,
-// separated by newlines. Blank lines are ignored.
-// Example of a line: package-name 1.2.0-1
-func (p *Pacman) parse(output string) []tin.Package {
+// An empty name or auto returns the first installed helper of AURHelpers.
+// If the helper is none, unsupported or not installed it will return nil.
+func NewAURHelper(name string) *AURHelper {
+	for _, h := range AURHelpers {
+		if name != "" && name != "auto" && name != h.Name {
+			continue
+		}
+
+		if _, err := lookPath(h.Name); err == nil {
+			h := h
+			return &h
+		}
+	}
+	return nil
+}
+
+// isAURHelper reports whether the executable is one of the AURHelpers.
+func isAURHelper(name string) bool {
+	for _, h := range AURHelpers {
+		if h.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AvailableUpdates returns a slice of tin.Package.
+func (h *AURHelper) AvailableUpdates() ([]tin.Package, error) {
+	output, err := h.updates()
+	if err != nil {
+		return []tin.Package{}, err
+	}
+
+	return parsePackages(string(output))
+}
+
+// ClassifiedUpdates returns a slice of tin.Update.
+//
+// The AUR has no advisories, so the updates are unclassified.
+func (h *AURHelper) ClassifiedUpdates() ([]tin.Update, error) {
+	output, err := h.updates()
+	if err != nil {
+		return []tin.Update{}, err
+	}
+
+	return parseUpdates(string(output))
+}
+
+// Installed returns a slice of tin.Package.
+//
+// The AUR packages are the foreign packages known by pacman.
+func (h *AURHelper) Installed() ([]tin.Package, error) {
+	return foreignPackages()
+}
+
+// updates returns the output of the helper listing the available AUR updates.
+//
+// Like pacman the helpers exit with code 1 and without output when there are no updates.
+func (h *AURHelper) updates() ([]byte, error) {
+	output, err := run(h.Name, h.Args...)
+	if err != nil {
+		var e *exec.ExitError
+		if errors.As(err, &e) && e.ExitCode() == 1 && len(lines(string(output))) == 0 {
+			return []byte{}, nil
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// Apt implements tin.PackageManager and tin.UpdateClassifier.
+type Apt struct{}
+
+// aptInstLine matches a line of a simulated apt-get upgrade.
+//
+// Example of a line: Inst libssl1.1 [1.1.1d-0+deb10u2] (1.1.1d-0+deb10u3 Debian-Security:10/stable [amd64])
+var aptInstLine = regexp.MustCompile(`^Inst (\S+)(?: \[([^\]]*)\])? \((\S+) ([^\[]*)`)
+
+// AvailableUpdates returns a slice of tin.Package.
+func (a *Apt) AvailableUpdates() ([]tin.Package, error) {
+	updates, err := a.ClassifiedUpdates()
+	if err != nil {
+		return []tin.Package{}, err
+	}
+
 	pp := []tin.Package{}
-	for _, v := range strings.Split(output, "\n") {
-		if v == "" {
+	for _, u := range updates {
+		pp = append(pp, tin.Package{Name: u.Name, Version: u.To})
+	}
+	return pp, nil
+}
+
+// ClassifiedUpdates returns a slice of tin.Update.
+//
+// Updates from the security pocket are classified as security updates,
+// apt doesn't distinguish between bugfixes and enhancements.
+func (a *Apt) ClassifiedUpdates() ([]tin.Update, error) {
+	output, err := run("apt-get", "-s", "-o", "Debug::NoLocking=1", "upgrade")
+	if err != nil {
+		return []tin.Update{}, err
+	}
+
+	uu := []tin.Update{}
+	for _, v := range strings.Split(string(output), "\n") {
+		m := aptInstLine.FindStringSubmatch(v)
+		if m == nil {
 			continue
 		}
 
-		p := strings.Split(v, " ")
-		pp = append(pp, tin.Package{
-			Name:    p[0],
-			Version: p[1],
-		})
+		kind := tin.UpdateUnclassified
+		if strings.Contains(strings.ToLower(m[4]), "security") {
+			kind = tin.UpdateSecurity
+		}
+		uu = append(uu, tin.Update{Name: m[1], From: m[2], To: m[3], Kind: kind})
+	}
+	return uu, nil
+}
+
+// Installed returns a slice of tin.Package.
+func (a *Apt) Installed() ([]tin.Package, error) {
+	output, err := run("dpkg-query", "-W", "-f=${Package} ${Version}\n")
+	if err != nil {
+		return []tin.Package{}, err
 	}
-	return pp
+
+	return parsePackages(string(output))
 }
 
-// Yay implements tin.PackageManager.
-type Yay struct{}
+// Dnf implements tin.PackageManager and tin.UpdateClassifier.
+type Dnf struct{}
 
 // AvailableUpdates returns a slice of tin.Package.
-func (y *Yay) AvailableUpdates() ([]tin.Package, error) {
-	output, err := execCommand("yay", "-Qum").Output()
+func (d *Dnf) AvailableUpdates() ([]tin.Package, error) {
+	updates, err := d.checkUpdate()
 	if err != nil {
 		return []tin.Package{}, err
 	}
 
-	return y.parse(string(output)), nil
+	pp := []tin.Package{}
+	for _, u := range updates {
+		pp = append(pp, tin.Package{Name: u.Name, Version: u.To})
+	}
+	return pp, nil
+}
+
+// ClassifiedUpdates returns a slice of tin.Update.
+//
+// The kind is derived from the advisories, a package with multiple advisories
+// gets the most important kind.
+func (d *Dnf) ClassifiedUpdates() ([]tin.Update, error) {
+	updates, err := d.checkUpdate()
+	if err != nil {
+		return []tin.Update{}, err
+	}
+
+	output, err := run("dnf", "-q", "updateinfo", "list")
+	if err != nil {
+		return []tin.Update{}, err
+	}
+
+	kinds := d.parseUpdateInfo(string(output))
+	for i, u := range updates {
+		if k, ok := kinds[u.Name]; ok {
+			updates[i].Kind = k
+		}
+	}
+	return updates, nil
 }
 
 // Installed returns a slice of tin.Package.
-func (y *Yay) Installed() ([]tin.Package, error) {
-	return []tin.Package{}, errors.New("unimplemented")
+func (d *Dnf) Installed() ([]tin.Package, error) {
+	output, err := run("dnf", "-q", "repoquery", "--userinstalled", "--qf", "%{name} %{evr}")
+	if err != nil {
+		return []tin.Package{}, err
+	}
+
+	return parsePackages(string(output))
 }
 
-// parse parses the string into a slice of tin.Package.
+// checkUpdate returns the unclassified updates.
+//
+// Exit code 100 means there are updates available.
+// Example of a line: openssl.x86_64  1:1.1.1g-1.fc32  updates
+func (d *Dnf) checkUpdate() ([]tin.Update, error) {
+	output, err := run("dnf", "-q", "check-update")
+	if err != nil {
+		var e *exec.ExitError
+		if !errors.As(err, &e) || e.ExitCode() != 100 {
+			return []tin.Update{}, err
+		}
+	}
+
+	uu := []tin.Update{}
+	for _, v := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(v, "Obsoleting Packages") {
+			break
+		}
+
+		f := strings.Fields(v)
+		if len(f) != 3 {
+			continue
+		}
+
+		// Removing the architecture from the package name.
+		i := strings.LastIndex(f[0], ".")
+		if i < 1 {
+			continue
+		}
+		uu = append(uu, tin.Update{Name: f[0][:i], To: f[1], Kind: tin.UpdateUnclassified})
+	}
+	return uu, nil
+}
+
+// parseUpdateInfo returns the kind of update by package name.
+//
+// Example of a line: FEDORA-2020-1234 Important/Sec. openssl-1:1.1.1g-1.fc32.x86_64
+// Example of a line: FEDORA-2020-5678 bugfix         kernel-5.6.11-300.fc32.x86_64
+func (d *Dnf) parseUpdateInfo(output string) map[string]tin.UpdateKind {
+	priority := map[tin.UpdateKind]int{tin.UpdateSecurity: 3, tin.UpdateBugfix: 2, tin.UpdateEnhancement: 1}
+	kinds := map[string]tin.UpdateKind{}
+	for _, v := range strings.Split(output, "\n") {
+		f := strings.Fields(v)
+		if len(f) != 3 {
+			continue
+		}
+
+		var kind tin.UpdateKind
+		switch t := strings.ToLower(f[1]); {
+		case t == "security" || strings.HasSuffix(t, "/sec."):
+			kind = tin.UpdateSecurity
+		case t == "bugfix":
+			kind = tin.UpdateBugfix
+		case t == "enhancement":
+			kind = tin.UpdateEnhancement
+		default:
+			continue
+		}
+
+		// Removing the architecture, release and version from the NEVRA.
+		nevra := f[2]
+		if i := strings.LastIndex(nevra, "."); i > 0 {
+			nevra = nevra[:i]
+		}
+		parts := strings.Split(nevra, "-")
+		if len(parts) < 3 {
+			continue
+		}
+		name := strings.Join(parts[:len(parts)-2], "-")
+
+		if priority[kind] > priority[kinds[name]] {
+			kinds[name] = kind
+		}
+	}
+	return kinds
+}
+
+// parsePackages parses the string into a slice of tin.Package.
 //
 // It assumes that the output contains a multiline string of packages,
-// separated by newlines. Blank lines are ignored.
+// separated by newlines. Updates contain both versions, in which case the
+// new version is used, and ignored updates are skipped. Blank lines are
+// ignored, lines that couldn't be parsed are returned as tin.Warnings.
 // Example of a line: package-name 1.2.0-1
-func (y *Yay) parse(output string) []tin.Package {
+// Example of a line: package-name 1.2.0-1 -> 1.3.0-1 [ignored]
+func parsePackages(output string) ([]tin.Package, error) {
 	pp := []tin.Package{}
-	for _, v := range strings.Split(output, "\n") {
-		if v == "" {
+	var warnings tin.Warnings
+	for _, v := range lines(output) {
+		f := strings.Fields(v)
+		switch {
+		case len(f) == 2:
+			pp = append(pp, tin.Package{Name: f[0], Version: f[1]})
+		case len(f) >= 4 && f[2] == "->":
+			if f[len(f)-1] == "[ignored]" {
+				continue
+			}
+			pp = append(pp, tin.Package{Name: f[0], Version: f[3]})
+		default:
+			warnings = append(warnings, &ParseError{Line: v, Reason: "expected name and version"})
+		}
+	}
+	return pp, warnings.Err()
+}
+
+// parseUpdates parses the string into a slice of tin.Update.
+//
+// It assumes that the output contains a multiline string of updates,
+// separated by newlines. Ignored updates are skipped. Blank lines are
+// ignored, lines that couldn't be parsed are returned as tin.Warnings.
+// Example of a line: package-name 1.2.0-1 -> 1.3.0-1
+func parseUpdates(output string) ([]tin.Update, error) {
+	uu := []tin.Update{}
+	var warnings tin.Warnings
+	for _, v := range lines(output) {
+		f := strings.Fields(v)
+		if len(f) < 4 || f[2] != "->" {
+			warnings = append(warnings, &ParseError{Line: v, Reason: "expected name, old and new version"})
 			continue
 		}
 
-		p := strings.Split(v, " ")
-		pp = append(pp, tin.Package{
-			Name:    p[0],
-			Version: p[1],
-		})
+		if f[len(f)-1] == "[ignored]" {
+			continue
+		}
+		uu = append(uu, tin.Update{Name: f[0], From: f[1], To: f[3], Kind: tin.UpdateUnclassified})
 	}
-	return pp
+	return uu, warnings.Err()
 }
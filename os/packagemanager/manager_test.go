@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/sjengpho/tin/tin"
@@ -27,20 +28,41 @@ func fakeLookPathPacman(file string) (string, error) {
 	return "fake-path", nil
 }
 
+func fakeLookPathApt(file string) (string, error) {
+	if file != "apt-get" {
+		return "", errors.New("executeable doesn't exists")
+	}
+
+	return "fake-path", nil
+}
+
+func fakeLookPathDnf(file string) (string, error) {
+	if file != "dnf" {
+		return "", errors.New("executeable doesn't exists")
+	}
+
+	return "fake-path", nil
+}
+
 func fakeLookPathError(file string) (string, error) {
 	return "", errors.New("executeable doesn't exists")
 }
 
-type fakeYay struct{}
+type fakeAURHelper struct{}
 
-func (f fakeYay) Installed() ([]tin.Package, error) {
+func (f fakeAURHelper) Installed() ([]tin.Package, error) {
 	return []tin.Package{}, errors.New("error")
 }
 
-func (f fakeYay) AvailableUpdates() ([]tin.Package, error) {
+func (f fakeAURHelper) AvailableUpdates() ([]tin.Package, error) {
 	return []tin.Package{}, errors.New("error")
 }
 
+// yay returns the yay tin.AURHelper.
+func yay() *AURHelper {
+	return &AURHelper{Name: "yay", Args: []string{"-Qum"}}
+}
+
 func fakeExecCommand(commandName string) func(name string, args ...string) *exec.Cmd {
 	return func(name string, args ...string) *exec.Cmd {
 		cs := []string{fmt.Sprintf("-test.run=%v", commandName), "--", name}
@@ -64,12 +86,20 @@ func TestNewSuccess(t *testing.T) {
 			want:         &Arch{},
 			fakeLookPath: fakeLookPathPacman,
 		},
+		{
+			want:         &Apt{},
+			fakeLookPath: fakeLookPathApt,
+		},
+		{
+			want:         &Dnf{},
+			fakeLookPath: fakeLookPathDnf,
+		},
 	}
 
 	for _, tt := range tests {
 		lookPath = tt.fakeLookPath
 
-		got := reflect.TypeOf(New())
+		got := reflect.TypeOf(New("", ""))
 		want := reflect.TypeOf(tt.want)
 		if got != want {
 			t.Errorf("want %v, got %v", want, got)
@@ -84,7 +114,7 @@ func TestNewError(t *testing.T) {
 	defer func() { lookPath = exec.LookPath }()
 
 	want := reflect.TypeOf(nil)
-	got := reflect.TypeOf(New())
+	got := reflect.TypeOf(New("", ""))
 	if got != want {
 		t.Errorf("want %v, got %v", want, got)
 	}
@@ -100,7 +130,7 @@ func TestAvailableUpdatesSuccess(t *testing.T) {
 			fakeExecCommand: fakeExecCommand("TestXBPSAvailableUpdatesCommandSuccess"),
 		},
 		{
-			pm:              &Arch{Pacman: Pacman{}, AUR: &Yay{}},
+			pm:              &Arch{Pacman: Pacman{}, AUR: yay()},
 			fakeExecCommand: fakeExecCommand("TestArchCommandSuccess"),
 		},
 		{
@@ -112,7 +142,7 @@ func TestAvailableUpdatesSuccess(t *testing.T) {
 			fakeExecCommand: fakeExecCommand("TestCommandExitCode2"),
 		},
 		{
-			pm:              &Yay{},
+			pm:              yay(),
 			fakeExecCommand: fakeExecCommand("TestArchCommandSuccess"),
 		},
 	}
@@ -139,20 +169,16 @@ func TestAvailableUpdatesError(t *testing.T) {
 			fakeExecCommand: fakeExecCommand("TestCommandError"),
 		},
 		{
-			pm:              &Arch{Pacman: Pacman{}, AUR: &Yay{}},
+			pm:              &Arch{Pacman: Pacman{}, AUR: yay()},
 			fakeExecCommand: fakeExecCommand("TestCommandError"),
 		},
-		{
-			pm:              &Arch{Pacman: Pacman{}, AUR: fakeYay{}},
-			fakeExecCommand: fakeExecCommand("TestArchCommandSuccess"),
-		},
 		{
 			pm:              &Pacman{},
 			fakeExecCommand: fakeExecCommand("TestCommandError"),
 		},
 		{
-			pm:              &Yay{},
-			fakeExecCommand: fakeExecCommand("TestCommandError"),
+			pm:              yay(),
+			fakeExecCommand: fakeExecCommand("TestCommandExitCode2"),
 		},
 	}
 
@@ -168,6 +194,76 @@ func TestAvailableUpdatesError(t *testing.T) {
 	}
 }
 
+func TestArchAURFailure(t *testing.T) {
+	execCommand = fakeExecCommand("TestArchCommandSuccess")
+	defer func() { execCommand = exec.Command }()
+
+	a := &Arch{Pacman: Pacman{}, AUR: fakeAURHelper{}}
+	want := []tin.Package{{Name: "package-name", Version: "3.5.2-1"}}
+
+	got, err := a.AvailableUpdates()
+	if !reflect.DeepEqual(got, want) || !tin.IsPartial(err) {
+		t.Errorf("want %v, got %v, %v", want, got, err)
+	}
+
+	got, err = a.Installed()
+	if !reflect.DeepEqual(got, want) || !tin.IsPartial(err) {
+		t.Errorf("want %v, got %v, %v", want, got, err)
+	}
+}
+
+func TestArchInstalledAUR(t *testing.T) {
+	execCommand = fakeExecCommand("TestArchCommandSuccess")
+	defer func() { execCommand = exec.Command }()
+
+	for _, a := range []*Arch{{Pacman: Pacman{}, AUR: yay()}, {Pacman: Pacman{}}} {
+		want := []tin.Package{{Name: "package-name", Version: "3.5.2-1", Source: tin.PackageSourceAUR}}
+		got, err := a.Installed()
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("want %v, got %v, %v", want, got, err)
+		}
+	}
+}
+
+func TestArchWithoutAUR(t *testing.T) {
+	execCommand = fakeExecCommand("TestArchUpdatesCommandSuccess")
+	defer func() { execCommand = exec.Command }()
+
+	a := &Arch{Pacman: Pacman{}}
+	got, err := a.ClassifiedUpdates()
+	if err != nil || len(got) != 2 {
+		t.Errorf("want %v, got %v, %v", "2 updates", got, err)
+	}
+}
+
+func TestNewAURHelper(t *testing.T) {
+	lookPath = func(file string) (string, error) {
+		if file != "paru" && file != "pikaur" {
+			return "", errors.New("executeable doesn't exists")
+		}
+		return "fake-path", nil
+	}
+	defer func() { lookPath = exec.LookPath }()
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "", want: "paru"},
+		{name: "auto", want: "paru"},
+		{name: "pikaur", want: "pikaur"},
+		{name: "yay", want: ""},
+		{name: "none", want: ""},
+	}
+
+	for _, tt := range tests {
+		got := NewAURHelper(tt.name)
+		if (got == nil && tt.want != "") || (got != nil && got.Name != tt.want) {
+			t.Errorf("%v: want %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
 func TestInstalledSuccess(t *testing.T) {
 	tests := []struct {
 		pm              tin.PackageManager
@@ -178,7 +274,7 @@ func TestInstalledSuccess(t *testing.T) {
 			fakeExecCommand: fakeExecCommand("TestXBPSInstalledCommandSuccess"),
 		},
 		{
-			pm:              &Arch{Pacman: Pacman{}, AUR: &Yay{}},
+			pm:              &Arch{Pacman: Pacman{}, AUR: yay()},
 			fakeExecCommand: fakeExecCommand("TestArchCommandSuccess"),
 		},
 		{
@@ -209,7 +305,7 @@ func TestInstalledError(t *testing.T) {
 			fakeExecCommand: fakeExecCommand("TestCommandError"),
 		},
 		{
-			pm:              &Arch{Pacman: Pacman{}, AUR: &Yay{}},
+			pm:              &Arch{Pacman: Pacman{}, AUR: yay()},
 			fakeExecCommand: fakeExecCommand("TestCommandError"),
 		},
 		{
@@ -217,8 +313,8 @@ func TestInstalledError(t *testing.T) {
 			fakeExecCommand: fakeExecCommand("TestCommandError"),
 		},
 		{
-			pm:              &Yay{},
-			fakeExecCommand: fakeExecCommand("TestCommandError"),
+			pm:              yay(),
+			fakeExecCommand: fakeExecCommand("TestCommandExitCode2"),
 		},
 	}
 
@@ -276,3 +372,250 @@ func TestArchCommandSuccess(t *testing.T) {
 	fmt.Println("package-name 3.5.2-1")
 	os.Exit(0)
 }
+
+func TestClassifiedUpdatesSuccess(t *testing.T) {
+	tests := []struct {
+		pm              tin.UpdateClassifier
+		fakeExecCommand func(name string, args ...string) *exec.Cmd
+		want            []tin.Update
+	}{
+		{
+			pm:              &Arch{Pacman: Pacman{}, AUR: yay(), Advisories: NewArchAdvisories("testdata/arch-security.json")},
+			fakeExecCommand: fakeExecCommand("TestArchUpdatesCommandSuccess"),
+			want: []tin.Update{
+				{Name: "openssl", From: "1.1.1f-1", To: "1.1.1g-1", Kind: tin.UpdateSecurity},
+				{Name: "htop", From: "2.2.0-2", To: "2.2.0-3", Kind: tin.UpdateUnclassified},
+				{Name: "openssl", From: "1.1.1f-1", To: "1.1.1g-1", Kind: tin.UpdateUnclassified},
+				{Name: "htop", From: "2.2.0-2", To: "2.2.0-3", Kind: tin.UpdateUnclassified},
+			},
+		},
+		{
+			pm:              &Apt{},
+			fakeExecCommand: fakeExecCommand("TestAptCommandSuccess"),
+			want: []tin.Update{
+				{Name: "libssl1.1", From: "1.1.1d-0+deb10u2", To: "1.1.1d-0+deb10u3", Kind: tin.UpdateSecurity},
+				{Name: "tzdata", From: "2019c-0+deb10u1", To: "2020a-0+deb10u1", Kind: tin.UpdateUnclassified},
+				{Name: "libnew1", To: "1.0-1", Kind: tin.UpdateUnclassified},
+			},
+		},
+		{
+			pm:              &Dnf{},
+			fakeExecCommand: fakeExecCommand("TestDnfCommandSuccess"),
+			want: []tin.Update{
+				{Name: "openssl", To: "1:1.1.1g-1.fc32", Kind: tin.UpdateSecurity},
+				{Name: "kernel", To: "5.6.11-300.fc32", Kind: tin.UpdateBugfix},
+				{Name: "python3-dnf", To: "4.2.21-1.fc32", Kind: tin.UpdateEnhancement},
+				{Name: "htop", To: "2.2.0-7.fc32", Kind: tin.UpdateUnclassified},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		execCommand = tt.fakeExecCommand
+
+		got, err := tt.pm.ClassifiedUpdates()
+		if err != nil {
+			t.Errorf("want %v, got %v", nil, err)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("want %v, got %v", tt.want, got)
+		}
+
+		execCommand = exec.Command
+	}
+}
+
+func TestClassifiedUpdatesError(t *testing.T) {
+	tests := []tin.UpdateClassifier{
+		&Arch{Pacman: Pacman{}, AUR: yay()},
+		&Apt{},
+		&Dnf{},
+	}
+
+	for _, pm := range tests {
+		execCommand = fakeExecCommand("TestCommandError")
+
+		_, got := pm.ClassifiedUpdates()
+		if got == nil {
+			t.Errorf("want %v, got %v", "error", got)
+		}
+
+		execCommand = exec.Command
+	}
+}
+
+func TestAptDnfSuccess(t *testing.T) {
+	tests := []struct {
+		pm              tin.PackageManager
+		fakeExecCommand func(name string, args ...string) *exec.Cmd
+	}{
+		{
+			pm:              &Apt{},
+			fakeExecCommand: fakeExecCommand("TestAptCommandSuccess"),
+		},
+		{
+			pm:              &Dnf{},
+			fakeExecCommand: fakeExecCommand("TestDnfCommandSuccess"),
+		},
+	}
+
+	for _, tt := range tests {
+		execCommand = tt.fakeExecCommand
+
+		updates, err := tt.pm.AvailableUpdates()
+		if err != nil || len(updates) == 0 {
+			t.Errorf("want %v, got %v, %v", "updates", updates, err)
+		}
+
+		installed, err := tt.pm.Installed()
+		if err != nil || len(installed) == 0 {
+			t.Errorf("want %v, got %v, %v", "packages", installed, err)
+		}
+
+		execCommand = exec.Command
+	}
+}
+
+func TestParsePackages(t *testing.T) {
+	output := strings.Join([]string{
+		":: Searching AUR for updates...",
+		"\x1b[1mpackage-name\x1b[0m \x1b[32m3.5.2-1\x1b[0m",
+		"htop 2.2.0-2 -> 2.2.0-3",
+		"linux 5.6.10.arch1-1 -> 5.6.11.arch1-1 [ignored]",
+		"",
+		"warning: database file for 'core' does not exist",
+	}, "\n")
+
+	want := []tin.Package{
+		{Name: "package-name", Version: "3.5.2-1"},
+		{Name: "htop", Version: "2.2.0-3"},
+	}
+
+	got, err := parsePackages(output)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	var w tin.Warnings
+	if !errors.As(err, &w) || len(w) != 1 {
+		t.Errorf("want %v, got %v", "1 warning", err)
+	}
+}
+
+func TestParseUpdates(t *testing.T) {
+	output := "openssl 1.1.1f-1 -> 1.1.1g-1\nlinux 5.6.10.arch1-1 -> 5.6.11.arch1-1 [ignored]\nopenssl\n"
+	want := []tin.Update{{Name: "openssl", From: "1.1.1f-1", To: "1.1.1g-1", Kind: tin.UpdateUnclassified}}
+
+	got, err := parseUpdates(output)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if !tin.IsPartial(err) {
+		t.Errorf("want %v, got %v", "warnings", err)
+	}
+}
+
+func TestXBPSParse(t *testing.T) {
+	x := &XBPS{}
+	want := []tin.Package{{Name: "package-name", Version: "3.5.2_1"}}
+
+	got, err := x.parse("package-name-3.5.2_1 update x86_64\npackagename\n-\npackage-\n")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	var w tin.Warnings
+	if !errors.As(err, &w) || len(w) != 3 {
+		t.Errorf("want %v, got %v", "3 warnings", err)
+	}
+}
+
+func TestCommandEnv(t *testing.T) {
+	execCommand = fakeExecCommand("TestEnvCommandSuccess")
+	defer func() { execCommand = exec.Command }()
+
+	want := "C"
+	got, err := command("checkupdates").Output()
+	if err != nil || strings.TrimSpace(string(got)) != want {
+		t.Errorf("want %v, got %s, %v", want, got, err)
+	}
+}
+
+func TestEnvCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Println(os.Getenv("LC_ALL"))
+	os.Exit(0)
+}
+
+// fakeCommandArgs returns the arguments of the faked command.
+func fakeCommandArgs() []string {
+	for i, a := range os.Args {
+		if a == "--" {
+			return os.Args[i+1:]
+		}
+	}
+	return []string{}
+}
+
+func TestArchUpdatesCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Println("openssl 1.1.1f-1 -> 1.1.1g-1")
+	fmt.Println("htop 2.2.0-2 -> 2.2.0-3")
+	os.Exit(0)
+}
+
+func TestAptCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	if fakeCommandArgs()[0] == "dpkg-query" {
+		fmt.Println("libssl1.1 1.1.1d-0+deb10u2")
+		fmt.Println("tzdata 2019c-0+deb10u1")
+		os.Exit(0)
+	}
+
+	fmt.Println("Reading package lists...")
+	fmt.Println("The following packages will be upgraded:")
+	fmt.Println("  libssl1.1 tzdata")
+	fmt.Println("Inst libssl1.1 [1.1.1d-0+deb10u2] (1.1.1d-0+deb10u3 Debian-Security:10/stable [amd64])")
+	fmt.Println("Inst tzdata [2019c-0+deb10u1] (2020a-0+deb10u1 Debian:10.4/stable [all])")
+	fmt.Println("Inst libnew1 (1.0-1 Debian:10.4/stable [amd64])")
+	fmt.Println("Conf libssl1.1 (1.1.1d-0+deb10u3 Debian-Security:10/stable [amd64])")
+	os.Exit(0)
+}
+
+func TestDnfCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	args := fakeCommandArgs()
+	switch args[2] {
+	case "check-update":
+		fmt.Println("")
+		fmt.Println("openssl.x86_64          1:1.1.1g-1.fc32          updates")
+		fmt.Println("kernel.x86_64           5.6.11-300.fc32          updates")
+		fmt.Println("python3-dnf.noarch      4.2.21-1.fc32            updates")
+		fmt.Println("htop.x86_64             2.2.0-7.fc32             updates")
+		fmt.Println("Obsoleting Packages")
+		fmt.Println("grub2-tools.x86_64      1:2.04-16.fc32           updates")
+		os.Exit(100)
+	case "updateinfo":
+		fmt.Println("FEDORA-2020-1234 bugfix         openssl-1:1.1.1g-1.fc32.x86_64")
+		fmt.Println("FEDORA-2020-2345 Important/Sec. openssl-1:1.1.1g-1.fc32.x86_64")
+		fmt.Println("FEDORA-2020-3456 bugfix         kernel-5.6.11-300.fc32.x86_64")
+		fmt.Println("FEDORA-2020-4567 enhancement    python3-dnf-4.2.21-1.fc32.noarch")
+	case "repoquery":
+		fmt.Println("htop 2.2.0-6.fc32")
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,245 @@
+package packagemanager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+var readDir = ioutil.ReadDir
+var glob = filepath.Glob
+
+// NewRebootChecker returns a tin.RebootChecker.
+//
+// When libraries is true the processes are checked for deleted shared libraries as well.
+func NewRebootChecker(libraries bool) tin.RebootChecker {
+	return &RebootCheck{
+		ModulesPath:        "/usr/lib/modules",
+		RebootRequiredPath: "/var/run/reboot-required",
+		ProcPath:           "/proc",
+		Libraries:          libraries,
+	}
+}
+
+// RebootCheck implements tin.RebootChecker.
+//
+// The installed kernels are derived from the module directories, which are
+// owned by the kernel packages of every supported package manager.
+type RebootCheck struct {
+	ModulesPath        string
+	RebootRequiredPath string
+	ProcPath           string
+	Libraries          bool
+}
+
+// Check returns a tin.RebootRequired.
+func (r *RebootCheck) Check() (tin.RebootRequired, error) {
+	running, err := r.runningKernel()
+	if err != nil {
+		return tin.RebootRequired{}, fmt.Errorf("failed getting the running kernel: %w", err)
+	}
+
+	reasons := []string{}
+	kernels, err := r.installedKernels()
+	if err != nil {
+		return tin.RebootRequired{}, fmt.Errorf("failed getting the installed kernels: %w", err)
+	}
+	reasons = append(reasons, r.kernelReasons(running, kernels)...)
+
+	if bytes, err := readFile(r.RebootRequiredPath); err == nil {
+		reason := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(string(bytes)), "***"))
+		reason = strings.TrimSpace(strings.TrimSuffix(reason, "***"))
+		if reason == "" {
+			reason = "System restart required"
+		}
+		if pkgs, err := readFile(r.RebootRequiredPath + ".pkgs"); err == nil {
+			if f := strings.Fields(string(pkgs)); len(f) > 0 {
+				reason = fmt.Sprintf("%v by %v", reason, strings.Join(f, ", "))
+			}
+		}
+		reasons = append(reasons, reason)
+	} else if _, err := osStat(r.RebootRequiredPath); err == nil {
+		reasons = append(reasons, "System restart required")
+	}
+
+	if r.Libraries {
+		if reason := r.librariesReason(); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+
+	return tin.RebootRequired{Required: len(reasons) > 0, Reasons: reasons}, nil
+}
+
+// runningKernel returns the release of the running kernel.
+//
+// It uses uname and falls back to /proc/version.
+// Example of /proc/version: Linux version 5.6.10-arch1-1 (linux@archlinux) ...
+func (r *RebootCheck) runningKernel() (string, error) {
+	if output, err := command("uname", "-r").Output(); err == nil {
+		if v := strings.TrimSpace(string(output)); v != "" {
+			return v, nil
+		}
+	}
+
+	bytes, err := readFile(filepath.Join(r.ProcPath, "version"))
+	if err != nil {
+		return "", err
+	}
+
+	f := strings.Fields(string(bytes))
+	if len(f) < 3 {
+		return "", fmt.Errorf("unexpected format: %v", string(bytes))
+	}
+
+	return f[2], nil
+}
+
+// installedKernels returns the releases of the installed kernels.
+func (r *RebootCheck) installedKernels() ([]string, error) {
+	ff, err := readDir(r.ModulesPath)
+	if err != nil {
+		return []string{}, err
+	}
+
+	kk := []string{}
+	for _, f := range ff {
+		if f.IsDir() {
+			kk = append(kk, f.Name())
+		}
+	}
+	return kk, nil
+}
+
+// kernelReasons compares the running kernel with the installed kernels.
+//
+// Only kernels of the same flavour are compared, which prevents an installed
+// newer mainline kernel from requiring a reboot while running a LTS kernel.
+func (r *RebootCheck) kernelReasons(running string, installed []string) []string {
+	reasons := []string{}
+	found := false
+	newer := []string{}
+	for _, k := range installed {
+		if k == running {
+			found = true
+			continue
+		}
+
+		if kernelFlavour(k) == kernelFlavour(running) && compareVersions(k, running) > 0 {
+			newer = append(newer, k)
+		}
+	}
+
+	if !found {
+		reasons = append(reasons, fmt.Sprintf("Modules of the running kernel %v have been removed", running))
+	}
+
+	if len(newer) > 0 {
+		sort.Slice(newer, func(i, j int) bool { return compareVersions(newer[i], newer[j]) > 0 })
+		reasons = append(reasons, fmt.Sprintf("Kernel %v is installed but %v is running", newer[0], running))
+	}
+
+	return reasons
+}
+
+// librariesReason returns a reason when processes map deleted shared libraries.
+//
+// Processes that can't be read, for example those of other users, are ignored.
+// Example of a line: 7f0d1c2e5000-7f0d1c30a000 r--p 00000000 00:1b 12 /usr/lib/libssl.so.1.1 (deleted)
+func (r *RebootCheck) librariesReason() string {
+	maps, err := glob(filepath.Join(r.ProcPath, "[0-9]*", "maps"))
+	if err != nil {
+		return ""
+	}
+
+	processes := []string{}
+	for _, m := range maps {
+		bytes, err := readFile(m)
+		if err != nil {
+			continue
+		}
+
+		for _, v := range strings.Split(string(bytes), "\n") {
+			if strings.HasSuffix(v, " (deleted)") && strings.Contains(v, ".so") {
+				pid := filepath.Base(filepath.Dir(m))
+				name := pid
+				if comm, err := readFile(filepath.Join(filepath.Dir(m), "comm")); err == nil {
+					name = fmt.Sprintf("%v (%v)", strings.TrimSpace(string(comm)), pid)
+				}
+				processes = append(processes, name)
+				break
+			}
+		}
+	}
+
+	if len(processes) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("Processes use deleted libraries: %v", strings.Join(processes, ", "))
+}
+
+// kernelFlavour returns the flavour of a kernel release.
+//
+// The flavour is the last dash separated part when it starts with a letter.
+// Example: 5.4.40-1-lts returns lts, 5.6.10-arch1-1 returns an empty string.
+func kernelFlavour(release string) string {
+	i := strings.LastIndex(release, "-")
+	if i < 0 || i == len(release)-1 || !unicode.IsLetter(rune(release[i+1])) {
+		return ""
+	}
+	return release[i+1:]
+}
+
+// compareVersions compares two versions by their numeric and non-numeric parts.
+//
+// It returns a negative number when a is lower than b, a positive number
+// when a is higher than b and zero when both are equal.
+func compareVersions(a string, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		switch {
+		case errA == nil && errB == nil && na != nb:
+			return na - nb
+		case (errA != nil || errB != nil) && pa[i] != pb[i]:
+			return strings.Compare(pa[i], pb[i])
+		}
+	}
+	return len(pa) - len(pb)
+}
+
+// versionParts splits a version into runs of digits and runs of letters.
+func versionParts(v string) []string {
+	parts := []string{}
+	current := []rune{}
+	digit := false
+	for _, c := range v {
+		if !unicode.IsDigit(c) && !unicode.IsLetter(c) {
+			if len(current) > 0 {
+				parts = append(parts, string(current))
+				current = current[:0]
+			}
+			continue
+		}
+
+		if len(current) > 0 && unicode.IsDigit(c) != digit {
+			parts = append(parts, string(current))
+			current = current[:0]
+		}
+		digit = unicode.IsDigit(c)
+		current = append(current, c)
+	}
+
+	if len(current) > 0 {
+		parts = append(parts, string(current))
+	}
+	return parts
+}
@@ -0,0 +1,171 @@
+package packagemanager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fakeRoot creates a directory with the files used by the reboot check.
+func fakeRoot(t *testing.T, modules []string, files map[string]string) (string, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "reboot")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, m := range modules {
+		if err := os.MkdirAll(filepath.Join(dir, "modules", m), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestRebootCheck(t *testing.T) {
+	execCommand = fakeExecCommand("TestUnameCommandSuccess")
+	defer func() { execCommand = exec.Command }()
+
+	tests := []struct {
+		modules   []string
+		files     map[string]string
+		libraries bool
+		want      []string
+	}{
+		{
+			modules: []string{"5.6.10-arch1-1", "5.4.40-1-lts"},
+			want:    []string{},
+		},
+		{
+			modules: []string{"5.6.11-arch1-1", "5.4.40-1-lts"},
+			want: []string{
+				"Modules of the running kernel 5.6.10-arch1-1 have been removed",
+				"Kernel 5.6.11-arch1-1 is installed but 5.6.10-arch1-1 is running",
+			},
+		},
+		{
+			modules: []string{"5.6.10-arch1-1", "5.6.10-arch2-1", "5.6.9-arch1-1"},
+			want:    []string{"Kernel 5.6.10-arch2-1 is installed but 5.6.10-arch1-1 is running"},
+		},
+		{
+			modules: []string{"5.6.10-arch1-1"},
+			files: map[string]string{
+				"reboot-required":      "*** System restart required ***\n",
+				"reboot-required.pkgs": "libc6\nlinux-image-amd64\n",
+			},
+			want: []string{"System restart required by libc6, linux-image-amd64"},
+		},
+		{
+			modules: []string{"5.6.10-arch1-1"},
+			files: map[string]string{
+				"proc/1/maps": "7f0d1c2e5000-7f0d1c30a000 r--p 00000000 00:1b 12 /usr/lib/libc.so.6\n",
+				"proc/2/maps": "7f0d1c2e5000-7f0d1c30a000 r--p 00000000 00:1b 12 /usr/lib/libssl.so.1.1 (deleted)\n",
+				"proc/2/comm": "sshd\n",
+				"proc/3/maps": "7f0d1c2e5000-7f0d1c30a000 r--p 00000000 00:1b 12 /tmp/file (deleted)\n",
+			},
+			libraries: true,
+			want:      []string{"Processes use deleted libraries: sshd (2)"},
+		},
+		{
+			modules: []string{"5.6.10-arch1-1"},
+			files: map[string]string{
+				"proc/2/maps": "7f0d1c2e5000-7f0d1c30a000 r--p 00000000 00:1b 12 /usr/lib/libssl.so.1.1 (deleted)\n",
+			},
+			libraries: false,
+			want:      []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		dir, cleanup := fakeRoot(t, tt.modules, tt.files)
+		r := &RebootCheck{
+			ModulesPath:        filepath.Join(dir, "modules"),
+			RebootRequiredPath: filepath.Join(dir, "reboot-required"),
+			ProcPath:           filepath.Join(dir, "proc"),
+			Libraries:          tt.libraries,
+		}
+
+		got, err := r.Check()
+		if err != nil {
+			t.Errorf("want %v, got %v", nil, err)
+		}
+
+		if !reflect.DeepEqual(got.Reasons, tt.want) || got.Required != (len(tt.want) > 0) {
+			t.Errorf("want %v, got %v", tt.want, got)
+		}
+		cleanup()
+	}
+}
+
+func TestRebootCheckProcVersion(t *testing.T) {
+	execCommand = fakeExecCommand("TestCommandError")
+	defer func() { execCommand = exec.Command }()
+
+	dir, cleanup := fakeRoot(t, []string{"5.6.10_1"}, map[string]string{
+		"proc/version": "Linux version 5.6.10_1 (voidlinux@voidlinux) (gcc version 9.3.0 (GCC)) #1 SMP 1588262400\n",
+	})
+	defer cleanup()
+
+	r := &RebootCheck{ModulesPath: filepath.Join(dir, "modules"), ProcPath: filepath.Join(dir, "proc")}
+	got, err := r.Check()
+	if err != nil || got.Required {
+		t.Errorf("want %v, got %v, %v", false, got, err)
+	}
+}
+
+func TestRebootCheckError(t *testing.T) {
+	execCommand = fakeExecCommand("TestUnameCommandSuccess")
+	defer func() { execCommand = exec.Command }()
+
+	r := &RebootCheck{ModulesPath: "testdata/missing"}
+	_, got := r.Check()
+	if got == nil {
+		t.Errorf("want %v, got %v", "error", got)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{a: "5.6.10-arch1-1", b: "5.6.9-arch1-1", want: 1},
+		{a: "5.6.10-arch1-1", b: "5.6.10-arch1-1", want: 0},
+		{a: "5.6.10-arch1-1", b: "5.6.10-arch2-1", want: -1},
+		{a: "5.6.10_1", b: "5.6.10_2", want: -1},
+		{a: "5.6.10", b: "5.6.10_1", want: -1},
+	}
+
+	for _, tt := range tests {
+		got := compareVersions(tt.a, tt.b)
+		if (got > 0) != (tt.want > 0) || (got < 0) != (tt.want < 0) {
+			t.Errorf("%v %v: want %v, got %v", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
+
+func TestUnameCommandSuccess(t *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+
+	fmt.Println("5.6.10-arch1-1")
+	os.Exit(0)
+}
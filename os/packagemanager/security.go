@@ -0,0 +1,143 @@
+package packagemanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// DefaultArchSecurityFeed is the JSON feed of the Arch Linux security tracker.
+const DefaultArchSecurityFeed = "https://security.archlinux.org/all.json"
+
+// NewArchAdvisories returns a ArchAdvisories.
+//
+// The source is a URL or a path to a local mirror of the feed.
+// The feed is fetched at most once an hour.
+func NewArchAdvisories(source string) *ArchAdvisories {
+	if source == "" {
+		source = DefaultArchSecurityFeed
+	}
+
+	return &ArchAdvisories{Source: source, TTL: time.Hour}
+}
+
+// ArchAdvisories classifies updates using the Arch Linux security tracker.
+type ArchAdvisories struct {
+	Source string
+	TTL    time.Duration
+
+	mutex   sync.Mutex
+	groups  []archAdvisoryGroup
+	fetched time.Time
+}
+
+// archAdvisoryGroup represents an advisory group of the security tracker.
+type archAdvisoryGroup struct {
+	Name     string   `json:"name"`
+	Packages []string `json:"packages"`
+	Status   string   `json:"status"`
+	Affected string   `json:"affected"`
+	Fixed    string   `json:"fixed"`
+}
+
+// Classify returns the updates with the security updates classified.
+//
+// An update is a security update when it upgrades an affected version to
+// a fixed version. A failure to fetch the feed leaves the updates unclassified.
+func (a *ArchAdvisories) Classify(updates []tin.Update) []tin.Update {
+	groups, err := a.load()
+	if err != nil {
+		return updates
+	}
+
+	fixed := map[string][]string{}
+	for _, g := range groups {
+		if g.Fixed == "" {
+			continue
+		}
+		for _, p := range g.Packages {
+			fixed[p] = append(fixed[p], g.Fixed)
+		}
+	}
+
+	for i, u := range updates {
+		if u.From == "" {
+			continue
+		}
+		for _, f := range fixed[u.Name] {
+			if compareVersions(u.From, f) < 0 && compareVersions(u.To, f) >= 0 {
+				updates[i].Kind = tin.UpdateSecurity
+				break
+			}
+		}
+	}
+	return updates
+}
+
+// load returns the advisory groups, fetching the feed when the cache has expired.
+//
+// When fetching fails the previous advisory groups are returned if there are any.
+func (a *ArchAdvisories) load() ([]archAdvisoryGroup, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.groups != nil && time.Since(a.fetched) < a.TTL {
+		return a.groups, nil
+	}
+
+	groups, err := a.fetch()
+	if err != nil {
+		if a.groups != nil {
+			return a.groups, nil
+		}
+		return nil, err
+	}
+
+	a.groups = groups
+	a.fetched = time.Now()
+
+	return a.groups, nil
+}
+
+// fetch reads the feed from the URL or the local path.
+func (a *ArchAdvisories) fetch() ([]archAdvisoryGroup, error) {
+	var data []byte
+	if strings.HasPrefix(a.Source, "http://") || strings.HasPrefix(a.Source, "https://") {
+		resp, err := httpClient.Get(a.Source)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed fetching %v: %v", a.Source, resp.Status)
+		}
+
+		bytes, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		data = bytes
+	} else {
+		bytes, err := readFile(strings.TrimPrefix(a.Source, "file://"))
+		if err != nil {
+			return nil, err
+		}
+		data = bytes
+	}
+
+	groups := []archAdvisoryGroup{}
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return nil, fmt.Errorf("failed parsing %v: %w", a.Source, err)
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,91 @@
+package packagemanager
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sjengpho/tin/tin"
+)
+
+func TestArchAdvisoriesClassify(t *testing.T) {
+	feed, err := ioutil.ReadFile("testdata/arch-security.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write(feed)
+	}))
+	defer func() { testServer.Close() }()
+
+	updates := func() []tin.Update {
+		return []tin.Update{
+			{Name: "openssl", From: "1.1.1f-1", To: "1.1.1g-1", Kind: tin.UpdateUnclassified},
+			{Name: "firefox", From: "76.0-1", To: "76.0.1-1", Kind: tin.UpdateUnclassified},
+			{Name: "linux", From: "5.6.8.arch1-1", To: "5.6.10.arch1-1", Kind: tin.UpdateUnclassified},
+			{Name: "htop", From: "2.2.0-2", To: "2.2.0-3", Kind: tin.UpdateUnclassified},
+		}
+	}
+
+	want := updates()
+	want[0].Kind = tin.UpdateSecurity
+
+	for _, source := range []string{testServer.URL, "testdata/arch-security.json", "file://testdata/arch-security.json"} {
+		a := NewArchAdvisories(source)
+
+		got := a.Classify(updates())
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%v: want %v, got %v", source, want, got)
+		}
+	}
+}
+
+func TestArchAdvisoriesClassifyError(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "", http.StatusInternalServerError)
+	}))
+	defer func() { testServer.Close() }()
+
+	want := []tin.Update{{Name: "openssl", From: "1.1.1f-1", To: "1.1.1g-1", Kind: tin.UpdateUnclassified}}
+	for _, source := range []string{testServer.URL, "testdata/missing.json", "testdata/xbps.log"} {
+		a := NewArchAdvisories(source)
+
+		got := a.Classify([]tin.Update{{Name: "openssl", From: "1.1.1f-1", To: "1.1.1g-1", Kind: tin.UpdateUnclassified}})
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%v: want %v, got %v", source, want, got)
+		}
+	}
+}
+
+func TestArchAdvisoriesCache(t *testing.T) {
+	requests := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		if requests > 1 {
+			http.Error(w, "", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`[{"name":"AVG-1","packages":["openssl"],"status":"Fixed","fixed":"1.1.1g-1"}]`))
+	}))
+	defer func() { testServer.Close() }()
+
+	a := NewArchAdvisories(testServer.URL)
+	a.Classify([]tin.Update{})
+	a.Classify([]tin.Update{})
+
+	want := 1
+	if requests != want {
+		t.Errorf("want %v, got %v", want, requests)
+	}
+
+	// An expired cache is used when fetching fails.
+	a.TTL = time.Nanosecond
+	got := a.Classify([]tin.Update{{Name: "openssl", From: "1.1.1f-1", To: "1.1.1g-1"}})
+	if got[0].Kind != tin.UpdateSecurity {
+		t.Errorf("want %v, got %v", tin.UpdateSecurity, got[0].Kind)
+	}
+}
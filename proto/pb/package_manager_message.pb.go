@@ -30,8 +30,10 @@ type Package struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Name          string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	InstalledSize int64  `protobuf:"varint,3,opt,name=installed_size,json=installedSize,proto3" json:"installed_size,omitempty"`
+	Source        string `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
 }
 
 func (x *Package) Reset() {
@@ -80,29 +82,827 @@ func (x *Package) GetVersion() string {
 	return ""
 }
 
+func (x *Package) GetInstalledSize() int64 {
+	if x != nil {
+		return x.InstalledSize
+	}
+	return 0
+}
+
+func (x *Package) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
 type AvailableUpdatesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *AvailableUpdatesRequest) Reset() {
-	*x = AvailableUpdatesRequest{}
+func (x *AvailableUpdatesRequest) Reset() {
+	*x = AvailableUpdatesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_package_manager_message_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AvailableUpdatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AvailableUpdatesRequest) ProtoMessage() {}
+
+func (x *AvailableUpdatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AvailableUpdatesRequest.ProtoReflect.Descriptor instead.
+func (*AvailableUpdatesRequest) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{1}
+}
+
+type AvailableUpdatesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value       int32 `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	Security    int32 `protobuf:"varint,2,opt,name=security,proto3" json:"security,omitempty"`
+	Bugfix      int32 `protobuf:"varint,3,opt,name=bugfix,proto3" json:"bugfix,omitempty"`
+	Enhancement int32 `protobuf:"varint,4,opt,name=enhancement,proto3" json:"enhancement,omitempty"`
+}
+
+func (x *AvailableUpdatesResponse) Reset() {
+	*x = AvailableUpdatesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_package_manager_message_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AvailableUpdatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AvailableUpdatesResponse) ProtoMessage() {}
+
+func (x *AvailableUpdatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AvailableUpdatesResponse.ProtoReflect.Descriptor instead.
+func (*AvailableUpdatesResponse) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AvailableUpdatesResponse) GetValue() int32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *AvailableUpdatesResponse) GetSecurity() int32 {
+	if x != nil {
+		return x.Security
+	}
+	return 0
+}
+
+func (x *AvailableUpdatesResponse) GetBugfix() int32 {
+	if x != nil {
+		return x.Bugfix
+	}
+	return 0
+}
+
+func (x *AvailableUpdatesResponse) GetEnhancement() int32 {
+	if x != nil {
+		return x.Enhancement
+	}
+	return 0
+}
+
+type InstalledPackagesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sort string `protobuf:"bytes,1,opt,name=sort,proto3" json:"sort,omitempty"`
+}
+
+func (x *InstalledPackagesRequest) Reset() {
+	*x = InstalledPackagesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_package_manager_message_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InstalledPackagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstalledPackagesRequest) ProtoMessage() {}
+
+func (x *InstalledPackagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstalledPackagesRequest.ProtoReflect.Descriptor instead.
+func (*InstalledPackagesRequest) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *InstalledPackagesRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type InstalledPackagesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Packages []*Package `protobuf:"bytes,1,rep,name=packages,proto3" json:"packages,omitempty"`
+}
+
+func (x *InstalledPackagesResponse) Reset() {
+	*x = InstalledPackagesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_package_manager_message_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InstalledPackagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstalledPackagesResponse) ProtoMessage() {}
+
+func (x *InstalledPackagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstalledPackagesResponse.ProtoReflect.Descriptor instead.
+func (*InstalledPackagesResponse) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *InstalledPackagesResponse) GetPackages() []*Package {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
+}
+
+type PackageUpgrade struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	FromVersion string `protobuf:"bytes,2,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"`
+	ToVersion   string `protobuf:"bytes,3,opt,name=to_version,json=toVersion,proto3" json:"to_version,omitempty"`
+}
+
+func (x *PackageUpgrade) Reset() {
+	*x = PackageUpgrade{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_package_manager_message_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PackageUpgrade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackageUpgrade) ProtoMessage() {}
+
+func (x *PackageUpgrade) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackageUpgrade.ProtoReflect.Descriptor instead.
+func (*PackageUpgrade) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PackageUpgrade) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PackageUpgrade) GetFromVersion() string {
+	if x != nil {
+		return x.FromVersion
+	}
+	return ""
+}
+
+func (x *PackageUpgrade) GetToVersion() string {
+	if x != nil {
+		return x.ToVersion
+	}
+	return ""
+}
+
+type InstalledPackagesChangesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *InstalledPackagesChangesRequest) Reset() {
+	*x = InstalledPackagesChangesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_package_manager_message_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InstalledPackagesChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstalledPackagesChangesRequest) ProtoMessage() {}
+
+func (x *InstalledPackagesChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstalledPackagesChangesRequest.ProtoReflect.Descriptor instead.
+func (*InstalledPackagesChangesRequest) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{6}
+}
+
+type InstalledPackagesChangesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timestamp int64             `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Added     []*Package        `protobuf:"bytes,2,rep,name=added,proto3" json:"added,omitempty"`
+	Removed   []*Package        `protobuf:"bytes,3,rep,name=removed,proto3" json:"removed,omitempty"`
+	Upgraded  []*PackageUpgrade `protobuf:"bytes,4,rep,name=upgraded,proto3" json:"upgraded,omitempty"`
+}
+
+func (x *InstalledPackagesChangesResponse) Reset() {
+	*x = InstalledPackagesChangesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_package_manager_message_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InstalledPackagesChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstalledPackagesChangesResponse) ProtoMessage() {}
+
+func (x *InstalledPackagesChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstalledPackagesChangesResponse.ProtoReflect.Descriptor instead.
+func (*InstalledPackagesChangesResponse) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *InstalledPackagesChangesResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *InstalledPackagesChangesResponse) GetAdded() []*Package {
+	if x != nil {
+		return x.Added
+	}
+	return nil
+}
+
+func (x *InstalledPackagesChangesResponse) GetRemoved() []*Package {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
+func (x *InstalledPackagesChangesResponse) GetUpgraded() []*PackageUpgrade {
+	if x != nil {
+		return x.Upgraded
+	}
+	return nil
+}
+
+type PackageEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timestamp   int64  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Action      string `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Name        string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	FromVersion string `protobuf:"bytes,4,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"`
+	ToVersion   string `protobuf:"bytes,5,opt,name=to_version,json=toVersion,proto3" json:"to_version,omitempty"`
+}
+
+func (x *PackageEvent) Reset() {
+	*x = PackageEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_package_manager_message_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PackageEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackageEvent) ProtoMessage() {}
+
+func (x *PackageEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackageEvent.ProtoReflect.Descriptor instead.
+func (*PackageEvent) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PackageEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *PackageEvent) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *PackageEvent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PackageEvent) GetFromVersion() string {
+	if x != nil {
+		return x.FromVersion
+	}
+	return ""
+}
+
+func (x *PackageEvent) GetToVersion() string {
+	if x != nil {
+		return x.ToVersion
+	}
+	return ""
+}
+
+type PackageHistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From int64  `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   int64  `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	Name string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *PackageHistoryRequest) Reset() {
+	*x = PackageHistoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_package_manager_message_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PackageHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackageHistoryRequest) ProtoMessage() {}
+
+func (x *PackageHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackageHistoryRequest.ProtoReflect.Descriptor instead.
+func (*PackageHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PackageHistoryRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *PackageHistoryRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *PackageHistoryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type PackageHistoryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Events []*PackageEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+}
+
+func (x *PackageHistoryResponse) Reset() {
+	*x = PackageHistoryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_package_manager_message_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PackageHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PackageHistoryResponse) ProtoMessage() {}
+
+func (x *PackageHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PackageHistoryResponse.ProtoReflect.Descriptor instead.
+func (*PackageHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PackageHistoryResponse) GetEvents() []*PackageEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type LastFullUpgradeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *LastFullUpgradeRequest) Reset() {
+	*x = LastFullUpgradeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_package_manager_message_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LastFullUpgradeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LastFullUpgradeRequest) ProtoMessage() {}
+
+func (x *LastFullUpgradeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LastFullUpgradeRequest.ProtoReflect.Descriptor instead.
+func (*LastFullUpgradeRequest) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{11}
+}
+
+type LastFullUpgradeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *LastFullUpgradeResponse) Reset() {
+	*x = LastFullUpgradeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_package_manager_message_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LastFullUpgradeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LastFullUpgradeResponse) ProtoMessage() {}
+
+func (x *LastFullUpgradeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LastFullUpgradeResponse.ProtoReflect.Descriptor instead.
+func (*LastFullUpgradeResponse) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LastFullUpgradeResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type RebootRequiredRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RebootRequiredRequest) Reset() {
+	*x = RebootRequiredRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_package_manager_message_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RebootRequiredRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebootRequiredRequest) ProtoMessage() {}
+
+func (x *RebootRequiredRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RebootRequiredRequest.ProtoReflect.Descriptor instead.
+func (*RebootRequiredRequest) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{13}
+}
+
+type RebootRequiredResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Required bool     `protobuf:"varint,1,opt,name=required,proto3" json:"required,omitempty"`
+	Reasons  []string `protobuf:"bytes,2,rep,name=reasons,proto3" json:"reasons,omitempty"`
+}
+
+func (x *RebootRequiredResponse) Reset() {
+	*x = RebootRequiredResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_package_manager_message_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RebootRequiredResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebootRequiredResponse) ProtoMessage() {}
+
+func (x *RebootRequiredResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RebootRequiredResponse.ProtoReflect.Descriptor instead.
+func (*RebootRequiredResponse) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RebootRequiredResponse) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *RebootRequiredResponse) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+type MaintenanceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *MaintenanceRequest) Reset() {
+	*x = MaintenanceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_package_manager_message_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MaintenanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaintenanceRequest) ProtoMessage() {}
+
+func (x *MaintenanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaintenanceRequest.ProtoReflect.Descriptor instead.
+func (*MaintenanceRequest) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{15}
+}
+
+type MaintenanceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Orphans   []*Package `protobuf:"bytes,1,rep,name=orphans,proto3" json:"orphans,omitempty"`
+	Foreign   []*Package `protobuf:"bytes,2,rep,name=foreign,proto3" json:"foreign,omitempty"`
+	CacheSize int64      `protobuf:"varint,3,opt,name=cache_size,json=cacheSize,proto3" json:"cache_size,omitempty"`
+}
+
+func (x *MaintenanceResponse) Reset() {
+	*x = MaintenanceResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_package_manager_message_proto_msgTypes[1]
+		mi := &file_package_manager_message_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AvailableUpdatesRequest) String() string {
+func (x *MaintenanceResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AvailableUpdatesRequest) ProtoMessage() {}
+func (*MaintenanceResponse) ProtoMessage() {}
 
-func (x *AvailableUpdatesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_package_manager_message_proto_msgTypes[1]
+func (x *MaintenanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -113,36 +913,64 @@ func (x *AvailableUpdatesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AvailableUpdatesRequest.ProtoReflect.Descriptor instead.
-func (*AvailableUpdatesRequest) Descriptor() ([]byte, []int) {
-	return file_package_manager_message_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use MaintenanceResponse.ProtoReflect.Descriptor instead.
+func (*MaintenanceResponse) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{16}
 }
 
-type AvailableUpdatesResponse struct {
+func (x *MaintenanceResponse) GetOrphans() []*Package {
+	if x != nil {
+		return x.Orphans
+	}
+	return nil
+}
+
+func (x *MaintenanceResponse) GetForeign() []*Package {
+	if x != nil {
+		return x.Foreign
+	}
+	return nil
+}
+
+func (x *MaintenanceResponse) GetCacheSize() int64 {
+	if x != nil {
+		return x.CacheSize
+	}
+	return 0
+}
+
+type PackageDetails struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Value int32 `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	Name          string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       string   `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Description   string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	InstalledSize int64    `protobuf:"varint,4,opt,name=installed_size,json=installedSize,proto3" json:"installed_size,omitempty"`
+	InstallDate   int64    `protobuf:"varint,5,opt,name=install_date,json=installDate,proto3" json:"install_date,omitempty"`
+	Explicit      bool     `protobuf:"varint,6,opt,name=explicit,proto3" json:"explicit,omitempty"`
+	Dependencies  []string `protobuf:"bytes,7,rep,name=dependencies,proto3" json:"dependencies,omitempty"`
+	RequiredBy    []string `protobuf:"bytes,8,rep,name=required_by,json=requiredBy,proto3" json:"required_by,omitempty"`
 }
 
-func (x *AvailableUpdatesResponse) Reset() {
-	*x = AvailableUpdatesResponse{}
+func (x *PackageDetails) Reset() {
+	*x = PackageDetails{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_package_manager_message_proto_msgTypes[2]
+		mi := &file_package_manager_message_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AvailableUpdatesResponse) String() string {
+func (x *PackageDetails) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AvailableUpdatesResponse) ProtoMessage() {}
+func (*PackageDetails) ProtoMessage() {}
 
-func (x *AvailableUpdatesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_package_manager_message_proto_msgTypes[2]
+func (x *PackageDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -153,41 +981,92 @@ func (x *AvailableUpdatesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AvailableUpdatesResponse.ProtoReflect.Descriptor instead.
-func (*AvailableUpdatesResponse) Descriptor() ([]byte, []int) {
-	return file_package_manager_message_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use PackageDetails.ProtoReflect.Descriptor instead.
+func (*PackageDetails) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *AvailableUpdatesResponse) GetValue() int32 {
+func (x *PackageDetails) GetName() string {
 	if x != nil {
-		return x.Value
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PackageDetails) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *PackageDetails) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *PackageDetails) GetInstalledSize() int64 {
+	if x != nil {
+		return x.InstalledSize
 	}
 	return 0
 }
 
-type InstalledPackagesRequest struct {
+func (x *PackageDetails) GetInstallDate() int64 {
+	if x != nil {
+		return x.InstallDate
+	}
+	return 0
+}
+
+func (x *PackageDetails) GetExplicit() bool {
+	if x != nil {
+		return x.Explicit
+	}
+	return false
+}
+
+func (x *PackageDetails) GetDependencies() []string {
+	if x != nil {
+		return x.Dependencies
+	}
+	return nil
+}
+
+func (x *PackageDetails) GetRequiredBy() []string {
+	if x != nil {
+		return x.RequiredBy
+	}
+	return nil
+}
+
+type PackageInfoRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 }
 
-func (x *InstalledPackagesRequest) Reset() {
-	*x = InstalledPackagesRequest{}
+func (x *PackageInfoRequest) Reset() {
+	*x = PackageInfoRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_package_manager_message_proto_msgTypes[3]
+		mi := &file_package_manager_message_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *InstalledPackagesRequest) String() string {
+func (x *PackageInfoRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InstalledPackagesRequest) ProtoMessage() {}
+func (*PackageInfoRequest) ProtoMessage() {}
 
-func (x *InstalledPackagesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_package_manager_message_proto_msgTypes[3]
+func (x *PackageInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -198,36 +1077,43 @@ func (x *InstalledPackagesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InstalledPackagesRequest.ProtoReflect.Descriptor instead.
-func (*InstalledPackagesRequest) Descriptor() ([]byte, []int) {
-	return file_package_manager_message_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use PackageInfoRequest.ProtoReflect.Descriptor instead.
+func (*PackageInfoRequest) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{18}
 }
 
-type InstalledPackagesResponse struct {
+func (x *PackageInfoRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type PackageInfoResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Packages []*Package `protobuf:"bytes,1,rep,name=packages,proto3" json:"packages,omitempty"`
+	Details *PackageDetails `protobuf:"bytes,1,opt,name=details,proto3" json:"details,omitempty"`
 }
 
-func (x *InstalledPackagesResponse) Reset() {
-	*x = InstalledPackagesResponse{}
+func (x *PackageInfoResponse) Reset() {
+	*x = PackageInfoResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_package_manager_message_proto_msgTypes[4]
+		mi := &file_package_manager_message_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *InstalledPackagesResponse) String() string {
+func (x *PackageInfoResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InstalledPackagesResponse) ProtoMessage() {}
+func (*PackageInfoResponse) ProtoMessage() {}
 
-func (x *InstalledPackagesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_package_manager_message_proto_msgTypes[4]
+func (x *PackageInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_package_manager_message_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -238,14 +1124,14 @@ func (x *InstalledPackagesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InstalledPackagesResponse.ProtoReflect.Descriptor instead.
-func (*InstalledPackagesResponse) Descriptor() ([]byte, []int) {
-	return file_package_manager_message_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use PackageInfoResponse.ProtoReflect.Descriptor instead.
+func (*PackageInfoResponse) Descriptor() ([]byte, []int) {
+	return file_package_manager_message_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *InstalledPackagesResponse) GetPackages() []*Package {
+func (x *PackageInfoResponse) GetDetails() *PackageDetails {
 	if x != nil {
-		return x.Packages
+		return x.Details
 	}
 	return nil
 }
@@ -255,22 +1141,118 @@ var File_package_manager_message_proto protoreflect.FileDescriptor
 var file_package_manager_message_proto_rawDesc = []byte{
 	0x0a, 0x1d, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x5f, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65,
 	0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x03, 0x74, 0x69, 0x6e, 0x22, 0x37, 0x0a, 0x07, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12,
+	0x03, 0x74, 0x69, 0x6e, 0x22, 0x76, 0x0a, 0x07, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12,
 	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
 	0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x19, 0x0a,
-	0x17, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x30, 0x0a, 0x18, 0x41, 0x76, 0x61, 0x69,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a,
+	0x0e, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0x19, 0x0a, 0x17,
+	0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x86, 0x01, 0x0a, 0x18, 0x41, 0x76, 0x61, 0x69,
 	0x6c, 0x61, 0x62, 0x6c, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
 	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x1a, 0x0a, 0x18, 0x49, 0x6e,
-	0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x45, 0x0a, 0x19, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c,
-	0x6c, 0x65, 0x64, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x50, 0x61, 0x63, 0x6b,
-	0x61, 0x67, 0x65, 0x52, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x42, 0x06, 0x5a,
-	0x04, 0x2e, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65,
+	0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x73, 0x65,
+	0x63, 0x75, 0x72, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x75, 0x67, 0x66, 0x69, 0x78,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x62, 0x75, 0x67, 0x66, 0x69, 0x78, 0x12, 0x20,
+	0x0a, 0x0b, 0x65, 0x6e, 0x68, 0x61, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0b, 0x65, 0x6e, 0x68, 0x61, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x22, 0x2e, 0x0a, 0x18, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x61, 0x63,
+	0x6b, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x73, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6f, 0x72, 0x74,
+	0x22, 0x45, 0x0a, 0x19, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x61, 0x63,
+	0x6b, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a,
+	0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0c, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x08, 0x70,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x22, 0x66, 0x0a, 0x0e, 0x50, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x66, 0x72, 0x6f, 0x6d, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x6f, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22,
+	0x21, 0x0a, 0x1f, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x61, 0x63, 0x6b,
+	0x61, 0x67, 0x65, 0x73, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x22, 0xbd, 0x01, 0x0a, 0x20, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64,
+	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x22, 0x0a, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x52, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x07, 0x72, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x69, 0x6e,
+	0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x64, 0x12, 0x2f, 0x0a, 0x08, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x64, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67,
+	0x65, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x52, 0x08, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64,
+	0x65, 0x64, 0x22, 0x9a, 0x01, 0x0a, 0x0c, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a,
+	0x0c, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x66, 0x72, 0x6f, 0x6d, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x6f, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22,
+	0x4f, 0x0a, 0x15, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72,
+	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02,
+	0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x22, 0x43, 0x0a, 0x16, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x69, 0x6e,
+	0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x18, 0x0a, 0x16, 0x4c, 0x61, 0x73, 0x74, 0x46, 0x75, 0x6c,
+	0x6c, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
+	0x37, 0x0a, 0x17, 0x4c, 0x61, 0x73, 0x74, 0x46, 0x75, 0x6c, 0x6c, 0x55, 0x70, 0x67, 0x72, 0x61,
+	0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x17, 0x0a, 0x15, 0x52, 0x65, 0x62, 0x6f,
+	0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x22, 0x4e, 0x0a, 0x16, 0x52, 0x65, 0x62, 0x6f, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x69,
+	0x72, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72,
+	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72,
+	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x73, 0x22, 0x14, 0x0a, 0x12, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x84, 0x01, 0x0a, 0x13, 0x4d, 0x61, 0x69, 0x6e,
+	0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x26, 0x0a, 0x07, 0x6f, 0x72, 0x70, 0x68, 0x61, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0c, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x07,
+	0x6f, 0x72, 0x70, 0x68, 0x61, 0x6e, 0x73, 0x12, 0x26, 0x0a, 0x07, 0x66, 0x6f, 0x72, 0x65, 0x69,
+	0x67, 0x6e, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x50,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x07, 0x66, 0x6f, 0x72, 0x65, 0x69, 0x67, 0x6e, 0x12,
+	0x1d, 0x0a, 0x0a, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x61, 0x63, 0x68, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x8b,
+	0x02, 0x0a, 0x0e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61,
+	0x6c, 0x6c, 0x65, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x73, 0x74,
+	0x61, 0x6c, 0x6c, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b,
+	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x44, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x65,
+	0x78, 0x70, 0x6c, 0x69, 0x63, 0x69, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x65,
+	0x78, 0x70, 0x6c, 0x69, 0x63, 0x69, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x65, 0x70, 0x65, 0x6e,
+	0x64, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x64,
+	0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x72,
+	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x42, 0x79, 0x22, 0x28, 0x0a, 0x12,
+	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x44, 0x0a, 0x13, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67,
+	0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a,
+	0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x44, 0x65, 0x74, 0x61,
+	0x69, 0x6c, 0x73, 0x52, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x42, 0x06, 0x5a, 0x04,
+	0x2e, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -285,21 +1267,43 @@ func file_package_manager_message_proto_rawDescGZIP() []byte {
 	return file_package_manager_message_proto_rawDescData
 }
 
-var file_package_manager_message_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_package_manager_message_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_package_manager_message_proto_goTypes = []interface{}{
-	(*Package)(nil),                   // 0: tin.Package
-	(*AvailableUpdatesRequest)(nil),   // 1: tin.AvailableUpdatesRequest
-	(*AvailableUpdatesResponse)(nil),  // 2: tin.AvailableUpdatesResponse
-	(*InstalledPackagesRequest)(nil),  // 3: tin.InstalledPackagesRequest
-	(*InstalledPackagesResponse)(nil), // 4: tin.InstalledPackagesResponse
+	(*Package)(nil),                          // 0: tin.Package
+	(*AvailableUpdatesRequest)(nil),          // 1: tin.AvailableUpdatesRequest
+	(*AvailableUpdatesResponse)(nil),         // 2: tin.AvailableUpdatesResponse
+	(*InstalledPackagesRequest)(nil),         // 3: tin.InstalledPackagesRequest
+	(*InstalledPackagesResponse)(nil),        // 4: tin.InstalledPackagesResponse
+	(*PackageUpgrade)(nil),                   // 5: tin.PackageUpgrade
+	(*InstalledPackagesChangesRequest)(nil),  // 6: tin.InstalledPackagesChangesRequest
+	(*InstalledPackagesChangesResponse)(nil), // 7: tin.InstalledPackagesChangesResponse
+	(*PackageEvent)(nil),                     // 8: tin.PackageEvent
+	(*PackageHistoryRequest)(nil),            // 9: tin.PackageHistoryRequest
+	(*PackageHistoryResponse)(nil),           // 10: tin.PackageHistoryResponse
+	(*LastFullUpgradeRequest)(nil),           // 11: tin.LastFullUpgradeRequest
+	(*LastFullUpgradeResponse)(nil),          // 12: tin.LastFullUpgradeResponse
+	(*RebootRequiredRequest)(nil),            // 13: tin.RebootRequiredRequest
+	(*RebootRequiredResponse)(nil),           // 14: tin.RebootRequiredResponse
+	(*MaintenanceRequest)(nil),               // 15: tin.MaintenanceRequest
+	(*MaintenanceResponse)(nil),              // 16: tin.MaintenanceResponse
+	(*PackageDetails)(nil),                   // 17: tin.PackageDetails
+	(*PackageInfoRequest)(nil),               // 18: tin.PackageInfoRequest
+	(*PackageInfoResponse)(nil),              // 19: tin.PackageInfoResponse
 }
 var file_package_manager_message_proto_depIdxs = []int32{
-	0, // 0: tin.InstalledPackagesResponse.packages:type_name -> tin.Package
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: tin.InstalledPackagesResponse.packages:type_name -> tin.Package
+	0,  // 1: tin.InstalledPackagesChangesResponse.added:type_name -> tin.Package
+	0,  // 2: tin.InstalledPackagesChangesResponse.removed:type_name -> tin.Package
+	5,  // 3: tin.InstalledPackagesChangesResponse.upgraded:type_name -> tin.PackageUpgrade
+	8,  // 4: tin.PackageHistoryResponse.events:type_name -> tin.PackageEvent
+	0,  // 5: tin.MaintenanceResponse.orphans:type_name -> tin.Package
+	0,  // 6: tin.MaintenanceResponse.foreign:type_name -> tin.Package
+	17, // 7: tin.PackageInfoResponse.details:type_name -> tin.PackageDetails
+	8,  // [8:8] is the sub-list for method output_type
+	8,  // [8:8] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_package_manager_message_proto_init() }
@@ -368,6 +1372,186 @@ func file_package_manager_message_proto_init() {
 				return nil
 			}
 		}
+		file_package_manager_message_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PackageUpgrade); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_package_manager_message_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InstalledPackagesChangesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_package_manager_message_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InstalledPackagesChangesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_package_manager_message_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PackageEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_package_manager_message_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PackageHistoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_package_manager_message_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PackageHistoryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_package_manager_message_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LastFullUpgradeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_package_manager_message_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LastFullUpgradeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_package_manager_message_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RebootRequiredRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_package_manager_message_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RebootRequiredResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_package_manager_message_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MaintenanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_package_manager_message_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MaintenanceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_package_manager_message_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PackageDetails); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_package_manager_message_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PackageInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_package_manager_message_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PackageInfoResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -375,7 +1559,7 @@ func file_package_manager_message_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_package_manager_message_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -33,104 +33,236 @@ var File_tin_service_proto protoreflect.FileDescriptor
 var file_tin_service_proto_rawDesc = []byte{
 	0x0a, 0x11, 0x74, 0x69, 0x6e, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x12, 0x03, 0x74, 0x69, 0x6e, 0x1a, 0x13, 0x67, 0x6d, 0x61, 0x69, 0x6c, 0x5f,
-	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1d, 0x70,
-	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x5f, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x5f, 0x6d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x19, 0x74, 0x65,
-	0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x15, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
-	0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x14,
-	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x32, 0xc0, 0x05, 0x0a, 0x0a, 0x54, 0x69, 0x6e, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x40, 0x0a, 0x0b, 0x47, 0x6d, 0x61, 0x69, 0x6c, 0x55, 0x6e, 0x72, 0x65,
-	0x61, 0x64, 0x12, 0x17, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x47, 0x6d, 0x61, 0x69, 0x6c, 0x55, 0x6e,
-	0x72, 0x65, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x74, 0x69,
-	0x6e, 0x2e, 0x47, 0x6d, 0x61, 0x69, 0x6c, 0x55, 0x6e, 0x72, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0c, 0x47, 0x6d, 0x61, 0x69, 0x6c, 0x41, 0x75,
-	0x74, 0x68, 0x55, 0x52, 0x4c, 0x12, 0x18, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x47, 0x6d, 0x61, 0x69,
-	0x6c, 0x41, 0x75, 0x74, 0x68, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x19, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x47, 0x6d, 0x61, 0x69, 0x6c, 0x41, 0x75, 0x74, 0x68, 0x55,
-	0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x0d, 0x47, 0x6d,
-	0x61, 0x69, 0x6c, 0x41, 0x75, 0x74, 0x68, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x19, 0x2e, 0x74, 0x69,
-	0x6e, 0x2e, 0x47, 0x6d, 0x61, 0x69, 0x6c, 0x41, 0x75, 0x74, 0x68, 0x43, 0x6f, 0x64, 0x65, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x47, 0x6d, 0x61,
-	0x69, 0x6c, 0x41, 0x75, 0x74, 0x68, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x4f, 0x0a, 0x10, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x55,
-	0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x41, 0x76, 0x61,
-	0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x41, 0x76, 0x61, 0x69, 0x6c,
-	0x61, 0x62, 0x6c, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x52, 0x0a, 0x11, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64,
-	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x12, 0x1d, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x49,
-	0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x49, 0x6e,
-	0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5d, 0x0a, 0x1a, 0x49, 0x6e, 0x73, 0x74, 0x61,
-	0x6c, 0x6c, 0x65, 0x64, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x53, 0x75, 0x62, 0x73,
-	0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x1d, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x49, 0x6e, 0x73, 0x74,
-	0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61,
-	0x6c, 0x6c, 0x65, 0x64, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x40, 0x0a, 0x0b, 0x54, 0x65, 0x6d, 0x70, 0x65, 0x72,
-	0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x17, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x54, 0x65, 0x6d, 0x70,
-	0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18,
-	0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x45, 0x53, 0x53, 0x49,
-	0x44, 0x12, 0x11, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x45, 0x53, 0x53, 0x49, 0x44, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x45, 0x53, 0x53, 0x49, 0x44,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x09, 0x49, 0x50, 0x41, 0x64,
-	0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x15, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x49, 0x50, 0x41, 0x64,
-	0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x74,
-	0x69, 0x6e, 0x2e, 0x49, 0x50, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x12,
-	0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x13, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x06, 0x5a, 0x04, 0x2e, 0x3b, 0x70, 0x62, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x12, 0x6d,
+	0x61, 0x69, 0x6c, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x1d, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x5f, 0x6d, 0x61, 0x6e, 0x61, 0x67,
+	0x65, 0x72, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x19, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x15, 0x6e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x14, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0x88, 0x0e, 0x0a, 0x0a, 0x54, 0x69, 0x6e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x40, 0x0a, 0x0b, 0x47, 0x6d, 0x61, 0x69, 0x6c,
+	0x55, 0x6e, 0x72, 0x65, 0x61, 0x64, 0x12, 0x17, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x47, 0x6d, 0x61,
+	0x69, 0x6c, 0x55, 0x6e, 0x72, 0x65, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x18, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x47, 0x6d, 0x61, 0x69, 0x6c, 0x55, 0x6e, 0x72, 0x65, 0x61,
+	0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0c, 0x47, 0x6d, 0x61,
+	0x69, 0x6c, 0x41, 0x75, 0x74, 0x68, 0x55, 0x52, 0x4c, 0x12, 0x18, 0x2e, 0x74, 0x69, 0x6e, 0x2e,
+	0x47, 0x6d, 0x61, 0x69, 0x6c, 0x41, 0x75, 0x74, 0x68, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x47, 0x6d, 0x61, 0x69, 0x6c, 0x41,
+	0x75, 0x74, 0x68, 0x55, 0x52, 0x4c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46,
+	0x0a, 0x0d, 0x47, 0x6d, 0x61, 0x69, 0x6c, 0x41, 0x75, 0x74, 0x68, 0x43, 0x6f, 0x64, 0x65, 0x12,
+	0x19, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x47, 0x6d, 0x61, 0x69, 0x6c, 0x41, 0x75, 0x74, 0x68, 0x43,
+	0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x74, 0x69, 0x6e,
+	0x2e, 0x47, 0x6d, 0x61, 0x69, 0x6c, 0x41, 0x75, 0x74, 0x68, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x0a, 0x4d, 0x61, 0x69, 0x6c, 0x55, 0x6e,
+	0x72, 0x65, 0x61, 0x64, 0x12, 0x16, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x4d, 0x61, 0x69, 0x6c, 0x55,
+	0x6e, 0x72, 0x65, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x74,
+	0x69, 0x6e, 0x2e, 0x4d, 0x61, 0x69, 0x6c, 0x55, 0x6e, 0x72, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0c, 0x4d, 0x61, 0x69, 0x6c, 0x41, 0x63, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x18, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x4d, 0x61, 0x69, 0x6c,
+	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x19, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x4d, 0x61, 0x69, 0x6c, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x0b, 0x55, 0x6e,
+	0x72, 0x65, 0x61, 0x64, 0x4d, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x17, 0x2e, 0x74, 0x69, 0x6e, 0x2e,
+	0x55, 0x6e, 0x72, 0x65, 0x61, 0x64, 0x4d, 0x61, 0x69, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x18, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x55, 0x6e, 0x72, 0x65, 0x61, 0x64, 0x4d,
+	0x61, 0x69, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4f, 0x0a, 0x10,
+	0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73,
+	0x12, 0x1c, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d,
+	0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x52, 0x0a,
+	0x11, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67,
+	0x65, 0x73, 0x12, 0x1d, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c,
+	0x65, 0x64, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1e, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65,
+	0x64, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x5d, 0x0a, 0x1a, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x61,
+	0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12,
+	0x1d, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e,
+	0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x61,
+	0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01,
+	0x12, 0x69, 0x0a, 0x18, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x61, 0x63,
+	0x6b, 0x61, 0x67, 0x65, 0x73, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x24, 0x2e, 0x74,
+	0x69, 0x6e, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x50, 0x61, 0x63, 0x6b,
+	0x61, 0x67, 0x65, 0x73, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x25, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c,
+	0x65, 0x64, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x49, 0x0a, 0x0e, 0x50,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1a, 0x2e,
+	0x74, 0x69, 0x6e, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x74, 0x69, 0x6e, 0x2e,
+	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4c, 0x0a, 0x0f, 0x4c, 0x61, 0x73, 0x74, 0x46, 0x75,
+	0x6c, 0x6c, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x12, 0x1b, 0x2e, 0x74, 0x69, 0x6e, 0x2e,
+	0x4c, 0x61, 0x73, 0x74, 0x46, 0x75, 0x6c, 0x6c, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x4c, 0x61, 0x73,
+	0x74, 0x46, 0x75, 0x6c, 0x6c, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x0e, 0x52, 0x65, 0x62, 0x6f, 0x6f, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x1a, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x52, 0x65, 0x62,
+	0x6f, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x52, 0x65, 0x62, 0x6f, 0x6f, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x40, 0x0a, 0x0b, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x17,
+	0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x4d, 0x61,
+	0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x40, 0x0a, 0x0b, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x49, 0x6e, 0x66, 0x6f,
+	0x12, 0x17, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x74, 0x69, 0x6e, 0x2e,
+	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x0b, 0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x12, 0x17, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x74, 0x69,
+	0x6e, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x45, 0x53, 0x53, 0x49, 0x44, 0x12, 0x11,
+	0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x45, 0x53, 0x53, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x12, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x45, 0x53, 0x53, 0x49, 0x44, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x09, 0x49, 0x50, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x15, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x49, 0x50, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x74, 0x69, 0x6e, 0x2e,
+	0x49, 0x50, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x3d, 0x0a, 0x0a, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x73, 0x12,
+	0x16, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x31, 0x0a, 0x06, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x12, 0x12, 0x2e, 0x74, 0x69, 0x6e,
+	0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13,
+	0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x0a, 0x54, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x70, 0x75,
+	0x74, 0x12, 0x16, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x54, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x70,
+	0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x74, 0x69, 0x6e, 0x2e,
+	0x54, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x70, 0x75, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x48, 0x0a, 0x13, 0x54, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x70, 0x75, 0x74,
+	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x16, 0x2e, 0x74, 0x69, 0x6e, 0x2e,
+	0x54, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x70, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x17, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x54, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x70,
+	0x75, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x43, 0x0a, 0x0c,
+	0x57, 0x69, 0x72, 0x65, 0x6c, 0x65, 0x73, 0x73, 0x4c, 0x69, 0x6e, 0x6b, 0x12, 0x18, 0x2e, 0x74,
+	0x69, 0x6e, 0x2e, 0x57, 0x69, 0x72, 0x65, 0x6c, 0x65, 0x73, 0x73, 0x4c, 0x69, 0x6e, 0x6b, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x57, 0x69, 0x72,
+	0x65, 0x6c, 0x65, 0x73, 0x73, 0x4c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x43, 0x0a, 0x0c, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74,
+	0x79, 0x12, 0x18, 0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
+	0x76, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x69,
+	0x6e, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x03, 0x56, 0x50, 0x4e, 0x12, 0x0f, 0x2e,
+	0x74, 0x69, 0x6e, 0x2e, 0x56, 0x50, 0x4e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10,
+	0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x56, 0x50, 0x4e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x31, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x12, 0x2e, 0x74, 0x69, 0x6e,
+	0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13,
+	0x2e, 0x74, 0x69, 0x6e, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x42, 0x06, 0x5a, 0x04, 0x2e, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var file_tin_service_proto_goTypes = []interface{}{
-	(*GmailUnreadRequest)(nil),        // 0: tin.GmailUnreadRequest
-	(*GmailAuthURLRequest)(nil),       // 1: tin.GmailAuthURLRequest
-	(*GmailAuthCodeRequest)(nil),      // 2: tin.GmailAuthCodeRequest
-	(*AvailableUpdatesRequest)(nil),   // 3: tin.AvailableUpdatesRequest
-	(*InstalledPackagesRequest)(nil),  // 4: tin.InstalledPackagesRequest
-	(*TemperatureRequest)(nil),        // 5: tin.TemperatureRequest
-	(*ESSIDRequest)(nil),              // 6: tin.ESSIDRequest
-	(*IPAddressRequest)(nil),          // 7: tin.IPAddressRequest
-	(*ConfigRequest)(nil),             // 8: tin.ConfigRequest
-	(*GmailUnreadResponse)(nil),       // 9: tin.GmailUnreadResponse
-	(*GmailAuthURLResponse)(nil),      // 10: tin.GmailAuthURLResponse
-	(*GmailAuthCodeResponse)(nil),     // 11: tin.GmailAuthCodeResponse
-	(*AvailableUpdatesResponse)(nil),  // 12: tin.AvailableUpdatesResponse
-	(*InstalledPackagesResponse)(nil), // 13: tin.InstalledPackagesResponse
-	(*TemperatureResponse)(nil),       // 14: tin.TemperatureResponse
-	(*ESSIDResponse)(nil),             // 15: tin.ESSIDResponse
-	(*IPAddressResponse)(nil),         // 16: tin.IPAddressResponse
-	(*ConfigResponse)(nil),            // 17: tin.ConfigResponse
+	(*GmailUnreadRequest)(nil),               // 0: tin.GmailUnreadRequest
+	(*GmailAuthURLRequest)(nil),              // 1: tin.GmailAuthURLRequest
+	(*GmailAuthCodeRequest)(nil),             // 2: tin.GmailAuthCodeRequest
+	(*MailUnreadRequest)(nil),                // 3: tin.MailUnreadRequest
+	(*MailAccountsRequest)(nil),              // 4: tin.MailAccountsRequest
+	(*UnreadMailsRequest)(nil),               // 5: tin.UnreadMailsRequest
+	(*AvailableUpdatesRequest)(nil),          // 6: tin.AvailableUpdatesRequest
+	(*InstalledPackagesRequest)(nil),         // 7: tin.InstalledPackagesRequest
+	(*InstalledPackagesChangesRequest)(nil),  // 8: tin.InstalledPackagesChangesRequest
+	(*PackageHistoryRequest)(nil),            // 9: tin.PackageHistoryRequest
+	(*LastFullUpgradeRequest)(nil),           // 10: tin.LastFullUpgradeRequest
+	(*RebootRequiredRequest)(nil),            // 11: tin.RebootRequiredRequest
+	(*MaintenanceRequest)(nil),               // 12: tin.MaintenanceRequest
+	(*PackageInfoRequest)(nil),               // 13: tin.PackageInfoRequest
+	(*TemperatureRequest)(nil),               // 14: tin.TemperatureRequest
+	(*ESSIDRequest)(nil),                     // 15: tin.ESSIDRequest
+	(*IPAddressRequest)(nil),                 // 16: tin.IPAddressRequest
+	(*InterfacesRequest)(nil),                // 17: tin.InterfacesRequest
+	(*RoutesRequest)(nil),                    // 18: tin.RoutesRequest
+	(*ThroughputRequest)(nil),                // 19: tin.ThroughputRequest
+	(*WirelessLinkRequest)(nil),              // 20: tin.WirelessLinkRequest
+	(*ConnectivityRequest)(nil),              // 21: tin.ConnectivityRequest
+	(*VPNRequest)(nil),                       // 22: tin.VPNRequest
+	(*ConfigRequest)(nil),                    // 23: tin.ConfigRequest
+	(*GmailUnreadResponse)(nil),              // 24: tin.GmailUnreadResponse
+	(*GmailAuthURLResponse)(nil),             // 25: tin.GmailAuthURLResponse
+	(*GmailAuthCodeResponse)(nil),            // 26: tin.GmailAuthCodeResponse
+	(*MailUnreadResponse)(nil),               // 27: tin.MailUnreadResponse
+	(*MailAccountsResponse)(nil),             // 28: tin.MailAccountsResponse
+	(*UnreadMailsResponse)(nil),              // 29: tin.UnreadMailsResponse
+	(*AvailableUpdatesResponse)(nil),         // 30: tin.AvailableUpdatesResponse
+	(*InstalledPackagesResponse)(nil),        // 31: tin.InstalledPackagesResponse
+	(*InstalledPackagesChangesResponse)(nil), // 32: tin.InstalledPackagesChangesResponse
+	(*PackageHistoryResponse)(nil),           // 33: tin.PackageHistoryResponse
+	(*LastFullUpgradeResponse)(nil),          // 34: tin.LastFullUpgradeResponse
+	(*RebootRequiredResponse)(nil),           // 35: tin.RebootRequiredResponse
+	(*MaintenanceResponse)(nil),              // 36: tin.MaintenanceResponse
+	(*PackageInfoResponse)(nil),              // 37: tin.PackageInfoResponse
+	(*TemperatureResponse)(nil),              // 38: tin.TemperatureResponse
+	(*ESSIDResponse)(nil),                    // 39: tin.ESSIDResponse
+	(*IPAddressResponse)(nil),                // 40: tin.IPAddressResponse
+	(*InterfacesResponse)(nil),               // 41: tin.InterfacesResponse
+	(*RoutesResponse)(nil),                   // 42: tin.RoutesResponse
+	(*ThroughputResponse)(nil),               // 43: tin.ThroughputResponse
+	(*WirelessLinkResponse)(nil),             // 44: tin.WirelessLinkResponse
+	(*ConnectivityResponse)(nil),             // 45: tin.ConnectivityResponse
+	(*VPNResponse)(nil),                      // 46: tin.VPNResponse
+	(*ConfigResponse)(nil),                   // 47: tin.ConfigResponse
 }
 var file_tin_service_proto_depIdxs = []int32{
 	0,  // 0: tin.TinService.GmailUnread:input_type -> tin.GmailUnreadRequest
 	1,  // 1: tin.TinService.GmailAuthURL:input_type -> tin.GmailAuthURLRequest
 	2,  // 2: tin.TinService.GmailAuthCode:input_type -> tin.GmailAuthCodeRequest
-	3,  // 3: tin.TinService.AvailableUpdates:input_type -> tin.AvailableUpdatesRequest
-	4,  // 4: tin.TinService.InstalledPackages:input_type -> tin.InstalledPackagesRequest
-	4,  // 5: tin.TinService.InstalledPackagesSubscribe:input_type -> tin.InstalledPackagesRequest
-	5,  // 6: tin.TinService.Temperature:input_type -> tin.TemperatureRequest
-	6,  // 7: tin.TinService.ESSID:input_type -> tin.ESSIDRequest
-	7,  // 8: tin.TinService.IPAddress:input_type -> tin.IPAddressRequest
-	8,  // 9: tin.TinService.Config:input_type -> tin.ConfigRequest
-	9,  // 10: tin.TinService.GmailUnread:output_type -> tin.GmailUnreadResponse
-	10, // 11: tin.TinService.GmailAuthURL:output_type -> tin.GmailAuthURLResponse
-	11, // 12: tin.TinService.GmailAuthCode:output_type -> tin.GmailAuthCodeResponse
-	12, // 13: tin.TinService.AvailableUpdates:output_type -> tin.AvailableUpdatesResponse
-	13, // 14: tin.TinService.InstalledPackages:output_type -> tin.InstalledPackagesResponse
-	13, // 15: tin.TinService.InstalledPackagesSubscribe:output_type -> tin.InstalledPackagesResponse
-	14, // 16: tin.TinService.Temperature:output_type -> tin.TemperatureResponse
-	15, // 17: tin.TinService.ESSID:output_type -> tin.ESSIDResponse
-	16, // 18: tin.TinService.IPAddress:output_type -> tin.IPAddressResponse
-	17, // 19: tin.TinService.Config:output_type -> tin.ConfigResponse
-	10, // [10:20] is the sub-list for method output_type
-	0,  // [0:10] is the sub-list for method input_type
+	3,  // 3: tin.TinService.MailUnread:input_type -> tin.MailUnreadRequest
+	4,  // 4: tin.TinService.MailAccounts:input_type -> tin.MailAccountsRequest
+	5,  // 5: tin.TinService.UnreadMails:input_type -> tin.UnreadMailsRequest
+	6,  // 6: tin.TinService.AvailableUpdates:input_type -> tin.AvailableUpdatesRequest
+	7,  // 7: tin.TinService.InstalledPackages:input_type -> tin.InstalledPackagesRequest
+	7,  // 8: tin.TinService.InstalledPackagesSubscribe:input_type -> tin.InstalledPackagesRequest
+	8,  // 9: tin.TinService.InstalledPackagesChanges:input_type -> tin.InstalledPackagesChangesRequest
+	9,  // 10: tin.TinService.PackageHistory:input_type -> tin.PackageHistoryRequest
+	10, // 11: tin.TinService.LastFullUpgrade:input_type -> tin.LastFullUpgradeRequest
+	11, // 12: tin.TinService.RebootRequired:input_type -> tin.RebootRequiredRequest
+	12, // 13: tin.TinService.Maintenance:input_type -> tin.MaintenanceRequest
+	13, // 14: tin.TinService.PackageInfo:input_type -> tin.PackageInfoRequest
+	14, // 15: tin.TinService.Temperature:input_type -> tin.TemperatureRequest
+	15, // 16: tin.TinService.ESSID:input_type -> tin.ESSIDRequest
+	16, // 17: tin.TinService.IPAddress:input_type -> tin.IPAddressRequest
+	17, // 18: tin.TinService.Interfaces:input_type -> tin.InterfacesRequest
+	18, // 19: tin.TinService.Routes:input_type -> tin.RoutesRequest
+	19, // 20: tin.TinService.Throughput:input_type -> tin.ThroughputRequest
+	19, // 21: tin.TinService.ThroughputSubscribe:input_type -> tin.ThroughputRequest
+	20, // 22: tin.TinService.WirelessLink:input_type -> tin.WirelessLinkRequest
+	21, // 23: tin.TinService.Connectivity:input_type -> tin.ConnectivityRequest
+	22, // 24: tin.TinService.VPN:input_type -> tin.VPNRequest
+	23, // 25: tin.TinService.Config:input_type -> tin.ConfigRequest
+	24, // 26: tin.TinService.GmailUnread:output_type -> tin.GmailUnreadResponse
+	25, // 27: tin.TinService.GmailAuthURL:output_type -> tin.GmailAuthURLResponse
+	26, // 28: tin.TinService.GmailAuthCode:output_type -> tin.GmailAuthCodeResponse
+	27, // 29: tin.TinService.MailUnread:output_type -> tin.MailUnreadResponse
+	28, // 30: tin.TinService.MailAccounts:output_type -> tin.MailAccountsResponse
+	29, // 31: tin.TinService.UnreadMails:output_type -> tin.UnreadMailsResponse
+	30, // 32: tin.TinService.AvailableUpdates:output_type -> tin.AvailableUpdatesResponse
+	31, // 33: tin.TinService.InstalledPackages:output_type -> tin.InstalledPackagesResponse
+	31, // 34: tin.TinService.InstalledPackagesSubscribe:output_type -> tin.InstalledPackagesResponse
+	32, // 35: tin.TinService.InstalledPackagesChanges:output_type -> tin.InstalledPackagesChangesResponse
+	33, // 36: tin.TinService.PackageHistory:output_type -> tin.PackageHistoryResponse
+	34, // 37: tin.TinService.LastFullUpgrade:output_type -> tin.LastFullUpgradeResponse
+	35, // 38: tin.TinService.RebootRequired:output_type -> tin.RebootRequiredResponse
+	36, // 39: tin.TinService.Maintenance:output_type -> tin.MaintenanceResponse
+	37, // 40: tin.TinService.PackageInfo:output_type -> tin.PackageInfoResponse
+	38, // 41: tin.TinService.Temperature:output_type -> tin.TemperatureResponse
+	39, // 42: tin.TinService.ESSID:output_type -> tin.ESSIDResponse
+	40, // 43: tin.TinService.IPAddress:output_type -> tin.IPAddressResponse
+	41, // 44: tin.TinService.Interfaces:output_type -> tin.InterfacesResponse
+	42, // 45: tin.TinService.Routes:output_type -> tin.RoutesResponse
+	43, // 46: tin.TinService.Throughput:output_type -> tin.ThroughputResponse
+	43, // 47: tin.TinService.ThroughputSubscribe:output_type -> tin.ThroughputResponse
+	44, // 48: tin.TinService.WirelessLink:output_type -> tin.WirelessLinkResponse
+	45, // 49: tin.TinService.Connectivity:output_type -> tin.ConnectivityResponse
+	46, // 50: tin.TinService.VPN:output_type -> tin.VPNResponse
+	47, // 51: tin.TinService.Config:output_type -> tin.ConfigResponse
+	26, // [26:52] is the sub-list for method output_type
+	0,  // [0:26] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -142,6 +274,7 @@ func file_tin_service_proto_init() {
 		return
 	}
 	file_gmail_message_proto_init()
+	file_mail_message_proto_init()
 	file_package_manager_message_proto_init()
 	file_temperature_message_proto_init()
 	file_network_message_proto_init()
@@ -180,12 +313,28 @@ type TinServiceClient interface {
 	GmailUnread(ctx context.Context, in *GmailUnreadRequest, opts ...grpc.CallOption) (*GmailUnreadResponse, error)
 	GmailAuthURL(ctx context.Context, in *GmailAuthURLRequest, opts ...grpc.CallOption) (*GmailAuthURLResponse, error)
 	GmailAuthCode(ctx context.Context, in *GmailAuthCodeRequest, opts ...grpc.CallOption) (*GmailAuthCodeResponse, error)
+	MailUnread(ctx context.Context, in *MailUnreadRequest, opts ...grpc.CallOption) (*MailUnreadResponse, error)
+	MailAccounts(ctx context.Context, in *MailAccountsRequest, opts ...grpc.CallOption) (*MailAccountsResponse, error)
+	UnreadMails(ctx context.Context, in *UnreadMailsRequest, opts ...grpc.CallOption) (*UnreadMailsResponse, error)
 	AvailableUpdates(ctx context.Context, in *AvailableUpdatesRequest, opts ...grpc.CallOption) (*AvailableUpdatesResponse, error)
 	InstalledPackages(ctx context.Context, in *InstalledPackagesRequest, opts ...grpc.CallOption) (*InstalledPackagesResponse, error)
 	InstalledPackagesSubscribe(ctx context.Context, in *InstalledPackagesRequest, opts ...grpc.CallOption) (TinService_InstalledPackagesSubscribeClient, error)
+	InstalledPackagesChanges(ctx context.Context, in *InstalledPackagesChangesRequest, opts ...grpc.CallOption) (TinService_InstalledPackagesChangesClient, error)
+	PackageHistory(ctx context.Context, in *PackageHistoryRequest, opts ...grpc.CallOption) (*PackageHistoryResponse, error)
+	LastFullUpgrade(ctx context.Context, in *LastFullUpgradeRequest, opts ...grpc.CallOption) (*LastFullUpgradeResponse, error)
+	RebootRequired(ctx context.Context, in *RebootRequiredRequest, opts ...grpc.CallOption) (*RebootRequiredResponse, error)
+	Maintenance(ctx context.Context, in *MaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceResponse, error)
+	PackageInfo(ctx context.Context, in *PackageInfoRequest, opts ...grpc.CallOption) (*PackageInfoResponse, error)
 	Temperature(ctx context.Context, in *TemperatureRequest, opts ...grpc.CallOption) (*TemperatureResponse, error)
 	ESSID(ctx context.Context, in *ESSIDRequest, opts ...grpc.CallOption) (*ESSIDResponse, error)
 	IPAddress(ctx context.Context, in *IPAddressRequest, opts ...grpc.CallOption) (*IPAddressResponse, error)
+	Interfaces(ctx context.Context, in *InterfacesRequest, opts ...grpc.CallOption) (*InterfacesResponse, error)
+	Routes(ctx context.Context, in *RoutesRequest, opts ...grpc.CallOption) (*RoutesResponse, error)
+	Throughput(ctx context.Context, in *ThroughputRequest, opts ...grpc.CallOption) (*ThroughputResponse, error)
+	ThroughputSubscribe(ctx context.Context, in *ThroughputRequest, opts ...grpc.CallOption) (TinService_ThroughputSubscribeClient, error)
+	WirelessLink(ctx context.Context, in *WirelessLinkRequest, opts ...grpc.CallOption) (*WirelessLinkResponse, error)
+	Connectivity(ctx context.Context, in *ConnectivityRequest, opts ...grpc.CallOption) (*ConnectivityResponse, error)
+	VPN(ctx context.Context, in *VPNRequest, opts ...grpc.CallOption) (*VPNResponse, error)
 	Config(ctx context.Context, in *ConfigRequest, opts ...grpc.CallOption) (*ConfigResponse, error)
 }
 
@@ -224,6 +373,33 @@ func (c *tinServiceClient) GmailAuthCode(ctx context.Context, in *GmailAuthCodeR
 	return out, nil
 }
 
+func (c *tinServiceClient) MailUnread(ctx context.Context, in *MailUnreadRequest, opts ...grpc.CallOption) (*MailUnreadResponse, error) {
+	out := new(MailUnreadResponse)
+	err := c.cc.Invoke(ctx, "/tin.TinService/MailUnread", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinServiceClient) MailAccounts(ctx context.Context, in *MailAccountsRequest, opts ...grpc.CallOption) (*MailAccountsResponse, error) {
+	out := new(MailAccountsResponse)
+	err := c.cc.Invoke(ctx, "/tin.TinService/MailAccounts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinServiceClient) UnreadMails(ctx context.Context, in *UnreadMailsRequest, opts ...grpc.CallOption) (*UnreadMailsResponse, error) {
+	out := new(UnreadMailsResponse)
+	err := c.cc.Invoke(ctx, "/tin.TinService/UnreadMails", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *tinServiceClient) AvailableUpdates(ctx context.Context, in *AvailableUpdatesRequest, opts ...grpc.CallOption) (*AvailableUpdatesResponse, error) {
 	out := new(AvailableUpdatesResponse)
 	err := c.cc.Invoke(ctx, "/tin.TinService/AvailableUpdates", in, out, opts...)
@@ -274,6 +450,83 @@ func (x *tinServiceInstalledPackagesSubscribeClient) Recv() (*InstalledPackagesR
 	return m, nil
 }
 
+func (c *tinServiceClient) InstalledPackagesChanges(ctx context.Context, in *InstalledPackagesChangesRequest, opts ...grpc.CallOption) (TinService_InstalledPackagesChangesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TinService_serviceDesc.Streams[1], "/tin.TinService/InstalledPackagesChanges", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tinServiceInstalledPackagesChangesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TinService_InstalledPackagesChangesClient interface {
+	Recv() (*InstalledPackagesChangesResponse, error)
+	grpc.ClientStream
+}
+
+type tinServiceInstalledPackagesChangesClient struct {
+	grpc.ClientStream
+}
+
+func (x *tinServiceInstalledPackagesChangesClient) Recv() (*InstalledPackagesChangesResponse, error) {
+	m := new(InstalledPackagesChangesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tinServiceClient) PackageHistory(ctx context.Context, in *PackageHistoryRequest, opts ...grpc.CallOption) (*PackageHistoryResponse, error) {
+	out := new(PackageHistoryResponse)
+	err := c.cc.Invoke(ctx, "/tin.TinService/PackageHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinServiceClient) LastFullUpgrade(ctx context.Context, in *LastFullUpgradeRequest, opts ...grpc.CallOption) (*LastFullUpgradeResponse, error) {
+	out := new(LastFullUpgradeResponse)
+	err := c.cc.Invoke(ctx, "/tin.TinService/LastFullUpgrade", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinServiceClient) RebootRequired(ctx context.Context, in *RebootRequiredRequest, opts ...grpc.CallOption) (*RebootRequiredResponse, error) {
+	out := new(RebootRequiredResponse)
+	err := c.cc.Invoke(ctx, "/tin.TinService/RebootRequired", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinServiceClient) Maintenance(ctx context.Context, in *MaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceResponse, error) {
+	out := new(MaintenanceResponse)
+	err := c.cc.Invoke(ctx, "/tin.TinService/Maintenance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinServiceClient) PackageInfo(ctx context.Context, in *PackageInfoRequest, opts ...grpc.CallOption) (*PackageInfoResponse, error) {
+	out := new(PackageInfoResponse)
+	err := c.cc.Invoke(ctx, "/tin.TinService/PackageInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *tinServiceClient) Temperature(ctx context.Context, in *TemperatureRequest, opts ...grpc.CallOption) (*TemperatureResponse, error) {
 	out := new(TemperatureResponse)
 	err := c.cc.Invoke(ctx, "/tin.TinService/Temperature", in, out, opts...)
@@ -301,6 +554,92 @@ func (c *tinServiceClient) IPAddress(ctx context.Context, in *IPAddressRequest,
 	return out, nil
 }
 
+func (c *tinServiceClient) Interfaces(ctx context.Context, in *InterfacesRequest, opts ...grpc.CallOption) (*InterfacesResponse, error) {
+	out := new(InterfacesResponse)
+	err := c.cc.Invoke(ctx, "/tin.TinService/Interfaces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinServiceClient) Routes(ctx context.Context, in *RoutesRequest, opts ...grpc.CallOption) (*RoutesResponse, error) {
+	out := new(RoutesResponse)
+	err := c.cc.Invoke(ctx, "/tin.TinService/Routes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinServiceClient) Throughput(ctx context.Context, in *ThroughputRequest, opts ...grpc.CallOption) (*ThroughputResponse, error) {
+	out := new(ThroughputResponse)
+	err := c.cc.Invoke(ctx, "/tin.TinService/Throughput", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinServiceClient) ThroughputSubscribe(ctx context.Context, in *ThroughputRequest, opts ...grpc.CallOption) (TinService_ThroughputSubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TinService_serviceDesc.Streams[2], "/tin.TinService/ThroughputSubscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tinServiceThroughputSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TinService_ThroughputSubscribeClient interface {
+	Recv() (*ThroughputResponse, error)
+	grpc.ClientStream
+}
+
+type tinServiceThroughputSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *tinServiceThroughputSubscribeClient) Recv() (*ThroughputResponse, error) {
+	m := new(ThroughputResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tinServiceClient) WirelessLink(ctx context.Context, in *WirelessLinkRequest, opts ...grpc.CallOption) (*WirelessLinkResponse, error) {
+	out := new(WirelessLinkResponse)
+	err := c.cc.Invoke(ctx, "/tin.TinService/WirelessLink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinServiceClient) Connectivity(ctx context.Context, in *ConnectivityRequest, opts ...grpc.CallOption) (*ConnectivityResponse, error) {
+	out := new(ConnectivityResponse)
+	err := c.cc.Invoke(ctx, "/tin.TinService/Connectivity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tinServiceClient) VPN(ctx context.Context, in *VPNRequest, opts ...grpc.CallOption) (*VPNResponse, error) {
+	out := new(VPNResponse)
+	err := c.cc.Invoke(ctx, "/tin.TinService/VPN", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *tinServiceClient) Config(ctx context.Context, in *ConfigRequest, opts ...grpc.CallOption) (*ConfigResponse, error) {
 	out := new(ConfigResponse)
 	err := c.cc.Invoke(ctx, "/tin.TinService/Config", in, out, opts...)
@@ -315,12 +654,28 @@ type TinServiceServer interface {
 	GmailUnread(context.Context, *GmailUnreadRequest) (*GmailUnreadResponse, error)
 	GmailAuthURL(context.Context, *GmailAuthURLRequest) (*GmailAuthURLResponse, error)
 	GmailAuthCode(context.Context, *GmailAuthCodeRequest) (*GmailAuthCodeResponse, error)
+	MailUnread(context.Context, *MailUnreadRequest) (*MailUnreadResponse, error)
+	MailAccounts(context.Context, *MailAccountsRequest) (*MailAccountsResponse, error)
+	UnreadMails(context.Context, *UnreadMailsRequest) (*UnreadMailsResponse, error)
 	AvailableUpdates(context.Context, *AvailableUpdatesRequest) (*AvailableUpdatesResponse, error)
 	InstalledPackages(context.Context, *InstalledPackagesRequest) (*InstalledPackagesResponse, error)
 	InstalledPackagesSubscribe(*InstalledPackagesRequest, TinService_InstalledPackagesSubscribeServer) error
+	InstalledPackagesChanges(*InstalledPackagesChangesRequest, TinService_InstalledPackagesChangesServer) error
+	PackageHistory(context.Context, *PackageHistoryRequest) (*PackageHistoryResponse, error)
+	LastFullUpgrade(context.Context, *LastFullUpgradeRequest) (*LastFullUpgradeResponse, error)
+	RebootRequired(context.Context, *RebootRequiredRequest) (*RebootRequiredResponse, error)
+	Maintenance(context.Context, *MaintenanceRequest) (*MaintenanceResponse, error)
+	PackageInfo(context.Context, *PackageInfoRequest) (*PackageInfoResponse, error)
 	Temperature(context.Context, *TemperatureRequest) (*TemperatureResponse, error)
 	ESSID(context.Context, *ESSIDRequest) (*ESSIDResponse, error)
 	IPAddress(context.Context, *IPAddressRequest) (*IPAddressResponse, error)
+	Interfaces(context.Context, *InterfacesRequest) (*InterfacesResponse, error)
+	Routes(context.Context, *RoutesRequest) (*RoutesResponse, error)
+	Throughput(context.Context, *ThroughputRequest) (*ThroughputResponse, error)
+	ThroughputSubscribe(*ThroughputRequest, TinService_ThroughputSubscribeServer) error
+	WirelessLink(context.Context, *WirelessLinkRequest) (*WirelessLinkResponse, error)
+	Connectivity(context.Context, *ConnectivityRequest) (*ConnectivityResponse, error)
+	VPN(context.Context, *VPNRequest) (*VPNResponse, error)
 	Config(context.Context, *ConfigRequest) (*ConfigResponse, error)
 }
 
@@ -337,6 +692,15 @@ func (*UnimplementedTinServiceServer) GmailAuthURL(context.Context, *GmailAuthUR
 func (*UnimplementedTinServiceServer) GmailAuthCode(context.Context, *GmailAuthCodeRequest) (*GmailAuthCodeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GmailAuthCode not implemented")
 }
+func (*UnimplementedTinServiceServer) MailUnread(context.Context, *MailUnreadRequest) (*MailUnreadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MailUnread not implemented")
+}
+func (*UnimplementedTinServiceServer) MailAccounts(context.Context, *MailAccountsRequest) (*MailAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MailAccounts not implemented")
+}
+func (*UnimplementedTinServiceServer) UnreadMails(context.Context, *UnreadMailsRequest) (*UnreadMailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnreadMails not implemented")
+}
 func (*UnimplementedTinServiceServer) AvailableUpdates(context.Context, *AvailableUpdatesRequest) (*AvailableUpdatesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AvailableUpdates not implemented")
 }
@@ -346,6 +710,24 @@ func (*UnimplementedTinServiceServer) InstalledPackages(context.Context, *Instal
 func (*UnimplementedTinServiceServer) InstalledPackagesSubscribe(*InstalledPackagesRequest, TinService_InstalledPackagesSubscribeServer) error {
 	return status.Errorf(codes.Unimplemented, "method InstalledPackagesSubscribe not implemented")
 }
+func (*UnimplementedTinServiceServer) InstalledPackagesChanges(*InstalledPackagesChangesRequest, TinService_InstalledPackagesChangesServer) error {
+	return status.Errorf(codes.Unimplemented, "method InstalledPackagesChanges not implemented")
+}
+func (*UnimplementedTinServiceServer) PackageHistory(context.Context, *PackageHistoryRequest) (*PackageHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PackageHistory not implemented")
+}
+func (*UnimplementedTinServiceServer) LastFullUpgrade(context.Context, *LastFullUpgradeRequest) (*LastFullUpgradeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LastFullUpgrade not implemented")
+}
+func (*UnimplementedTinServiceServer) RebootRequired(context.Context, *RebootRequiredRequest) (*RebootRequiredResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RebootRequired not implemented")
+}
+func (*UnimplementedTinServiceServer) Maintenance(context.Context, *MaintenanceRequest) (*MaintenanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Maintenance not implemented")
+}
+func (*UnimplementedTinServiceServer) PackageInfo(context.Context, *PackageInfoRequest) (*PackageInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PackageInfo not implemented")
+}
 func (*UnimplementedTinServiceServer) Temperature(context.Context, *TemperatureRequest) (*TemperatureResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Temperature not implemented")
 }
@@ -355,6 +737,27 @@ func (*UnimplementedTinServiceServer) ESSID(context.Context, *ESSIDRequest) (*ES
 func (*UnimplementedTinServiceServer) IPAddress(context.Context, *IPAddressRequest) (*IPAddressResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method IPAddress not implemented")
 }
+func (*UnimplementedTinServiceServer) Interfaces(context.Context, *InterfacesRequest) (*InterfacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Interfaces not implemented")
+}
+func (*UnimplementedTinServiceServer) Routes(context.Context, *RoutesRequest) (*RoutesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Routes not implemented")
+}
+func (*UnimplementedTinServiceServer) Throughput(context.Context, *ThroughputRequest) (*ThroughputResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Throughput not implemented")
+}
+func (*UnimplementedTinServiceServer) ThroughputSubscribe(*ThroughputRequest, TinService_ThroughputSubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method ThroughputSubscribe not implemented")
+}
+func (*UnimplementedTinServiceServer) WirelessLink(context.Context, *WirelessLinkRequest) (*WirelessLinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WirelessLink not implemented")
+}
+func (*UnimplementedTinServiceServer) Connectivity(context.Context, *ConnectivityRequest) (*ConnectivityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Connectivity not implemented")
+}
+func (*UnimplementedTinServiceServer) VPN(context.Context, *VPNRequest) (*VPNResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VPN not implemented")
+}
 func (*UnimplementedTinServiceServer) Config(context.Context, *ConfigRequest) (*ConfigResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Config not implemented")
 }
@@ -417,6 +820,60 @@ func _TinService_GmailAuthCode_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TinService_MailUnread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MailUnreadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinServiceServer).MailUnread(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tin.TinService/MailUnread",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinServiceServer).MailUnread(ctx, req.(*MailUnreadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinService_MailAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MailAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinServiceServer).MailAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tin.TinService/MailAccounts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinServiceServer).MailAccounts(ctx, req.(*MailAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinService_UnreadMails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnreadMailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinServiceServer).UnreadMails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tin.TinService/UnreadMails",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinServiceServer).UnreadMails(ctx, req.(*UnreadMailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TinService_AvailableUpdates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AvailableUpdatesRequest)
 	if err := dec(in); err != nil {
@@ -474,6 +931,117 @@ func (x *tinServiceInstalledPackagesSubscribeServer) Send(m *InstalledPackagesRe
 	return x.ServerStream.SendMsg(m)
 }
 
+func _TinService_InstalledPackagesChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InstalledPackagesChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TinServiceServer).InstalledPackagesChanges(m, &tinServiceInstalledPackagesChangesServer{stream})
+}
+
+type TinService_InstalledPackagesChangesServer interface {
+	Send(*InstalledPackagesChangesResponse) error
+	grpc.ServerStream
+}
+
+type tinServiceInstalledPackagesChangesServer struct {
+	grpc.ServerStream
+}
+
+func (x *tinServiceInstalledPackagesChangesServer) Send(m *InstalledPackagesChangesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TinService_PackageHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PackageHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinServiceServer).PackageHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tin.TinService/PackageHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinServiceServer).PackageHistory(ctx, req.(*PackageHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinService_LastFullUpgrade_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LastFullUpgradeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinServiceServer).LastFullUpgrade(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tin.TinService/LastFullUpgrade",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinServiceServer).LastFullUpgrade(ctx, req.(*LastFullUpgradeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinService_RebootRequired_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebootRequiredRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinServiceServer).RebootRequired(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tin.TinService/RebootRequired",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinServiceServer).RebootRequired(ctx, req.(*RebootRequiredRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinService_Maintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinServiceServer).Maintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tin.TinService/Maintenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinServiceServer).Maintenance(ctx, req.(*MaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinService_PackageInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PackageInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinServiceServer).PackageInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tin.TinService/PackageInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinServiceServer).PackageInfo(ctx, req.(*PackageInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TinService_Temperature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(TemperatureRequest)
 	if err := dec(in); err != nil {
@@ -528,6 +1096,135 @@ func _TinService_IPAddress_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TinService_Interfaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InterfacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinServiceServer).Interfaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tin.TinService/Interfaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinServiceServer).Interfaces(ctx, req.(*InterfacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinService_Routes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoutesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinServiceServer).Routes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tin.TinService/Routes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinServiceServer).Routes(ctx, req.(*RoutesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinService_Throughput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThroughputRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinServiceServer).Throughput(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tin.TinService/Throughput",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinServiceServer).Throughput(ctx, req.(*ThroughputRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinService_ThroughputSubscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ThroughputRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TinServiceServer).ThroughputSubscribe(m, &tinServiceThroughputSubscribeServer{stream})
+}
+
+type TinService_ThroughputSubscribeServer interface {
+	Send(*ThroughputResponse) error
+	grpc.ServerStream
+}
+
+type tinServiceThroughputSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *tinServiceThroughputSubscribeServer) Send(m *ThroughputResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TinService_WirelessLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WirelessLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinServiceServer).WirelessLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tin.TinService/WirelessLink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinServiceServer).WirelessLink(ctx, req.(*WirelessLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinService_Connectivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinServiceServer).Connectivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tin.TinService/Connectivity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinServiceServer).Connectivity(ctx, req.(*ConnectivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TinService_VPN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VPNRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TinServiceServer).VPN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tin.TinService/VPN",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TinServiceServer).VPN(ctx, req.(*VPNRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TinService_Config_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ConfigRequest)
 	if err := dec(in); err != nil {
@@ -562,6 +1259,18 @@ var _TinService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GmailAuthCode",
 			Handler:    _TinService_GmailAuthCode_Handler,
 		},
+		{
+			MethodName: "MailUnread",
+			Handler:    _TinService_MailUnread_Handler,
+		},
+		{
+			MethodName: "MailAccounts",
+			Handler:    _TinService_MailAccounts_Handler,
+		},
+		{
+			MethodName: "UnreadMails",
+			Handler:    _TinService_UnreadMails_Handler,
+		},
 		{
 			MethodName: "AvailableUpdates",
 			Handler:    _TinService_AvailableUpdates_Handler,
@@ -570,6 +1279,26 @@ var _TinService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "InstalledPackages",
 			Handler:    _TinService_InstalledPackages_Handler,
 		},
+		{
+			MethodName: "PackageHistory",
+			Handler:    _TinService_PackageHistory_Handler,
+		},
+		{
+			MethodName: "LastFullUpgrade",
+			Handler:    _TinService_LastFullUpgrade_Handler,
+		},
+		{
+			MethodName: "RebootRequired",
+			Handler:    _TinService_RebootRequired_Handler,
+		},
+		{
+			MethodName: "Maintenance",
+			Handler:    _TinService_Maintenance_Handler,
+		},
+		{
+			MethodName: "PackageInfo",
+			Handler:    _TinService_PackageInfo_Handler,
+		},
 		{
 			MethodName: "Temperature",
 			Handler:    _TinService_Temperature_Handler,
@@ -582,6 +1311,30 @@ var _TinService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "IPAddress",
 			Handler:    _TinService_IPAddress_Handler,
 		},
+		{
+			MethodName: "Interfaces",
+			Handler:    _TinService_Interfaces_Handler,
+		},
+		{
+			MethodName: "Routes",
+			Handler:    _TinService_Routes_Handler,
+		},
+		{
+			MethodName: "Throughput",
+			Handler:    _TinService_Throughput_Handler,
+		},
+		{
+			MethodName: "WirelessLink",
+			Handler:    _TinService_WirelessLink_Handler,
+		},
+		{
+			MethodName: "Connectivity",
+			Handler:    _TinService_Connectivity_Handler,
+		},
+		{
+			MethodName: "VPN",
+			Handler:    _TinService_VPN_Handler,
+		},
 		{
 			MethodName: "Config",
 			Handler:    _TinService_Config_Handler,
@@ -593,6 +1346,16 @@ var _TinService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _TinService_InstalledPackagesSubscribe_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "InstalledPackagesChanges",
+			Handler:       _TinService_InstalledPackagesChanges_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ThroughputSubscribe",
+			Handler:       _TinService_ThroughputSubscribe_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "tin_service.proto",
 }
@@ -1,24 +1,188 @@
 package tin
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 )
 
 // Config represents the configuration.
 type Config struct {
 	GmailCredentials string
 	GmailToken       string
+
+	// GmailQuery and GmailQueries are the search queries of the Gmail account,
+	// see tin.MailAccount.
+	GmailQuery   string
+	GmailQueries map[string]string
+
+	// MailAccounts are the mail accounts of which the unread mails are counted.
+	// When it is empty the Gmail account of GmailCredentials and GmailToken is used.
+	MailAccounts []MailAccount
+
+	// ArchSecurityFeed is the URL or path of the Arch Linux security advisories.
+	ArchSecurityFeed string
+
+	// RebootCheckLibraries enables checking processes for deleted shared libraries.
+	RebootCheckLibraries bool
+
+	// AURHelper is the AUR helper: yay, paru, pikaur or none.
+	// When it is empty the first installed helper is used.
+	AURHelper string
+
+	// ESSIDBackends are the backends of the network name lookup in order of preference:
+	// iw, nmcli, iwctl and iwgetid. When it is empty all of them are tried.
+	ESSIDBackends []string
+
+	// ConnectivityTargets are the targets probed to tell whether the network is online,
+	// for example tcp://1.1.1.1:443, http://example.com/generate_204 or dns://example.com.
+	// When it is empty the default targets are probed.
+	ConnectivityTargets []string
+
+	// PublicIPSources are the sources of the public IP lookup, URLs that respond with
+	// the IP address or resolvers such as dns://resolver1.opendns.com/myip.opendns.com.
+	// When it is empty the default sources are used.
+	PublicIPSources []string
+
+	// PublicIPQuorum is the number of sources that have to agree on the public IP.
+	// When it is less than 2 the first answer is used.
+	PublicIPQuorum int
+
+	// GeoIPEndpoint is the JSON API of the location and ISP of the public IP,
+	// of which {ip} is replaced by the address, for example
+	// https://ipinfo.io/{ip}/json. It sends the public IP to a third party,
+	// so it is empty by default, which disables it.
+	GeoIPEndpoint string
+
+	// GeoIPDatabases are MaxMind DB files, for example GeoLite2-City.mmdb and
+	// GeoLite2-ASN.mmdb. When they are set they are used instead of the GeoIPEndpoint.
+	GeoIPDatabases []string
+
+	// PackageManagerTimeout is the maximum duration of a package manager command.
+	PackageManagerTimeout time.Duration
+}
+
+// Represents the providers of a tin.MailAccount.
+const (
+	MailProviderGmail = "gmail"
+	MailProviderIMAP  = "imap"
+	MailProviderLocal = "local"
+)
+
+// MailAccount represents the configuration of a mail account.
+type MailAccount struct {
+	// Name identifies the account, for example work. When it is empty the provider is used.
+	Name string
+
+	// Provider is the provider of the account: gmail, imap or local.
+	Provider string
+
+	// GmailCredentials and GmailToken are the paths of the OAuth 2.0 files of a Gmail account.
+	GmailCredentials string
+	GmailToken       string
+
+	// GmailQuery is the search query of the unread mails of a Gmail account.
+	// When it is empty is:unread is used.
+	GmailQuery string
+
+	// GmailQueries are named search queries of which the mails are counted, for
+	// example inbox: "is:unread in:inbox category:primary" or alerts: "label:alerts is:unread".
+	GmailQueries map[string]string
+
+	// IMAPAddress is the host and port of the IMAP server, for example imap.example.com:993.
+	IMAPAddress string
+
+	// IMAPSecurity is the security of the IMAP connection: tls, starttls or none.
+	// When it is empty tls is used.
+	IMAPSecurity string
+
+	// IMAPUsername and IMAPPassword are the credentials of the IMAP account.
+	IMAPUsername string
+	IMAPPassword string
+
+	// IMAPCredentials and IMAPToken are the paths of the OAuth 2.0 files of the
+	// IMAP account, the token file contains the refresh token. XOAUTH2 is used
+	// instead of the password when they are set.
+	IMAPCredentials string
+	IMAPToken       string
+
+	// IMAPMailboxes are the mailboxes of which the unseen messages are counted.
+	// When it is empty the INBOX is used.
+	IMAPMailboxes []string
+
+	// LocalMail are Maildir folders and mbox files, for example synced by mbsync.
+	LocalMail []string
+}
+
+// AccountName returns the name of the account, or the provider when it has no name.
+func (a MailAccount) AccountName() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.Provider
+}
+
+// configDir returns the directory of the configuration files.
+func configDir() string {
+	home, _ := os.UserHomeDir()
+	return fmt.Sprintf("/%v/.config/tin", strings.TrimLeft(home, "/"))
+}
+
+// DefaultConfigPath returns the path of the configuration file.
+func DefaultConfigPath() string {
+	return configDir() + "/config.json"
+}
+
+// LoadConfig returns the DefaultConfig of which the values are overridden
+// by the values of the JSON file.
+//
+// The keys are the names of the fields of tin.Config, the PackageManagerTimeout
+// is a duration like "2m". Unknown keys are an error, so typos don't go unnoticed.
+func LoadConfig(path string) (Config, error) {
+	c := DefaultConfig()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+
+	// The embedded config is decoded as usual, except for the durations.
+	type config Config
+	v := struct {
+		*config
+		PackageManagerTimeout string
+	}{config: (*config)(&c)}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&v); err != nil {
+		return c, fmt.Errorf("failed parsing config file %v: %w", path, err)
+	}
+
+	if v.PackageManagerTimeout != "" {
+		timeout, err := time.ParseDuration(v.PackageManagerTimeout)
+		if err != nil {
+			return c, fmt.Errorf("failed parsing config file %v: PackageManagerTimeout: %w", path, err)
+		}
+		c.PackageManagerTimeout = timeout
+	}
+	return c, nil
 }
 
 // DefaultConfig returns a tin.Config with default values.
 func DefaultConfig() Config {
-	home, _ := os.UserHomeDir()
-	dir := fmt.Sprintf("/%v/.config/tin", strings.TrimLeft(home, "/"))
+	dir := configDir()
 
 	return Config{
 		GmailCredentials: dir + "/gmail/credentials.json",
 		GmailToken:       dir + "/gmail/token.json",
+
+		ArchSecurityFeed:      "https://security.archlinux.org/all.json",
+		RebootCheckLibraries:  true,
+		PackageManagerTimeout: 5 * time.Minute,
 	}
 }
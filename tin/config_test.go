@@ -1,8 +1,11 @@
 package tin
 
 import (
+	"io/ioutil"
+	"os"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -12,4 +15,94 @@ func TestDefaultConfig(t *testing.T) {
 	if reflect.TypeOf(got) != reflect.TypeOf(want) {
 		t.Errorf("want %v, got %v", reflect.TypeOf(want), reflect.TypeOf(got))
 	}
+
+	// The public IP isn't sent to a third party unless it's configured.
+	if got.GeoIPEndpoint != "" {
+		t.Errorf("want %q, got %q", "", got.GeoIPEndpoint)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	f, err := ioutil.TempFile("", "config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	f.WriteString(`{
+		"ArchSecurityFeed": "/var/lib/tin/all.json",
+		"PackageManagerTimeout": "2m",
+		"AURHelper": "paru",
+		"ESSIDBackends": ["nmcli", "iw"],
+		"ConnectivityTargets": ["tcp://1.1.1.1:443"],
+		"PublicIPSources": ["https://ifconfig.me/ip", "dns://resolver1.opendns.com/myip.opendns.com"],
+		"PublicIPQuorum": 2,
+		"GeoIPEndpoint": "https://ipinfo.io/{ip}/json",
+		"GeoIPDatabases": ["/var/lib/GeoIP/GeoLite2-City.mmdb"],
+		"MailAccounts": [
+			{"Name": "personal", "Provider": "gmail", "GmailQueries": {"alerts": "label:alerts is:unread"}},
+			{
+				"Name": "work",
+				"Provider": "imap",
+				"IMAPAddress": "imap.example.com:993",
+				"IMAPSecurity": "tls",
+				"IMAPUsername": "user@example.com",
+				"IMAPPassword": "secret",
+				"IMAPMailboxes": ["INBOX", "Work"]
+			},
+			{"Provider": "local", "LocalMail": ["/home/user/Mail/INBOX"]}
+		]
+	}`)
+	f.Close()
+
+	got, err := LoadConfig(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := DefaultConfig()
+	want.ArchSecurityFeed = "/var/lib/tin/all.json"
+	want.PackageManagerTimeout = 2 * time.Minute
+	want.AURHelper = "paru"
+	want.ESSIDBackends = []string{"nmcli", "iw"}
+	want.ConnectivityTargets = []string{"tcp://1.1.1.1:443"}
+	want.PublicIPSources = []string{"https://ifconfig.me/ip", "dns://resolver1.opendns.com/myip.opendns.com"}
+	want.PublicIPQuorum = 2
+	want.GeoIPEndpoint = "https://ipinfo.io/{ip}/json"
+	want.GeoIPDatabases = []string{"/var/lib/GeoIP/GeoLite2-City.mmdb"}
+	want.MailAccounts = []MailAccount{
+		{Name: "personal", Provider: MailProviderGmail, GmailQueries: map[string]string{"alerts": "label:alerts is:unread"}},
+		{
+			Name:          "work",
+			Provider:      MailProviderIMAP,
+			IMAPAddress:   "imap.example.com:993",
+			IMAPSecurity:  "tls",
+			IMAPUsername:  "user@example.com",
+			IMAPPassword:  "secret",
+			IMAPMailboxes: []string{"INBOX", "Work"},
+		},
+		{Provider: MailProviderLocal, LocalMail: []string{"/home/user/Mail/INBOX"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestLoadConfigError(t *testing.T) {
+	f, err := ioutil.TempFile("", "config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	for _, data := range []string{`{"AURHelpr": "paru"}`, `{"PackageManagerTimeout": "2 minutes"}`, `{`} {
+		ioutil.WriteFile(f.Name(), []byte(data), 0600)
+		if _, err := LoadConfig(f.Name()); err == nil {
+			t.Errorf("%v: want error, got nil", data)
+		}
+	}
+
+	if _, err := LoadConfig("testdata/missing.json"); !os.IsNotExist(err) {
+		t.Errorf("want %v, got %v", os.ErrNotExist, err)
+	}
 }
@@ -0,0 +1,240 @@
+package tin
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ConnectivityChecker is the interface implemented by an object that can
+// probe the targets that tell whether the network is online.
+type ConnectivityChecker interface {
+	Check() ([]ProbeResult, error)
+}
+
+// ProbeResult represents the result of probing a target.
+type ProbeResult struct {
+	Target  string
+	Success bool
+	Latency time.Duration
+	Portal  bool // The probe was answered by a captive portal.
+}
+
+// ConnectivityStatus represents whether the network is online.
+type ConnectivityStatus string
+
+// Represents tin.ConnectivityStatus.
+const (
+	ConnectivityUnknown ConnectivityStatus = ""
+	ConnectivityOnline  ConnectivityStatus = "online"
+	ConnectivityLimited ConnectivityStatus = "limited" // Some of the targets are unreachable.
+	ConnectivityOffline ConnectivityStatus = "offline"
+	ConnectivityPortal  ConnectivityStatus = "portal" // A captive portal intercepts the traffic.
+)
+
+// ProbeStats represents the recent results of probing a target.
+type ProbeStats struct {
+	Target       string
+	Success      bool          // Result of the last probe.
+	Latency      time.Duration // Latency of the last successful probe, rounded to milliseconds.
+	SuccessRatio float64       // Ratio of the recent probes that succeeded.
+}
+
+// Connectivity represents the connectivity of the network.
+type Connectivity struct {
+	Status ConnectivityStatus
+	Probes []ProbeStats
+}
+
+// Equal implements tin.Comparable.
+func (a Connectivity) Equal(t interface{}) bool {
+	if b, ok := t.(Connectivity); ok {
+		return reflect.DeepEqual(a, b)
+	}
+	return false
+}
+
+// Offline reports whether the network is offline or behind a captive portal.
+func (a Connectivity) Offline() bool {
+	return a.Status == ConnectivityOffline || a.Status == ConnectivityPortal
+}
+
+// ConnectivityKey represents a StateKey.
+const ConnectivityKey StateKey = "Connectivity"
+
+// connectivityWindow is the number of recent probes of the success ratio.
+const connectivityWindow = 10
+
+// ConnectivityService provides the connectivity of the network.
+type ConnectivityService struct {
+	checker ConnectivityChecker
+	history *probeHistory
+	state   *State
+	worker  *Worker
+	logger  *log.Logger
+}
+
+// NewConnectivityService returns a tin.ConnectivityService.
+func NewConnectivityService(c ConnectivityChecker, l *log.Logger) *ConnectivityService {
+	s := &ConnectivityService{
+		checker: c,
+		history: newProbeHistory(connectivityWindow),
+		state:   NewState(),
+		logger:  l,
+	}
+
+	// Worker that probes the targets on intervals and updates the state.
+	if c == nil {
+		s.logger.Println(errors.New("failed initializing worker"))
+	} else {
+		s.worker = NewWorker(30*time.Second, func() {
+			results, err := s.checker.Check()
+			if err != nil {
+				s.logger.Println(fmt.Errorf("worker failed: %w", err))
+			} else {
+				s.SetConnectivity(s.history.record(results))
+			}
+		}, s.logger)
+	}
+
+	return s
+}
+
+// Subscribe returns a tin.StateSubscription.
+func (s *ConnectivityService) Subscribe() StateSubscription {
+	return s.state.Subscribe()
+}
+
+// Connectivity returns a tin.Connectivity.
+func (s *ConnectivityService) Connectivity() Connectivity {
+	v, err := s.state.Get(ConnectivityKey)
+	if err != nil {
+		return Connectivity{}
+	}
+
+	return v.(Connectivity)
+}
+
+// SetConnectivity updates the state.
+func (s *ConnectivityService) SetConnectivity(c Connectivity) {
+	s.state.Set(ConnectivityKey, c)
+}
+
+// Offline reports whether the network is offline or behind a captive portal.
+//
+// The network is assumed to be online until the targets are probed.
+func (s *ConnectivityService) Offline() bool {
+	return s.Connectivity().Offline()
+}
+
+// pause pauses the worker while the network is offline and executes the
+// task as soon as it's online again.
+func (s *ConnectivityService) pause(w *Worker) {
+	w.PauseWhile(s.Offline)
+
+	subscription := s.Subscribe()
+	go func() {
+		offline := s.Offline()
+		for v := range subscription.Channel {
+			c, ok := v.(Connectivity)
+			if !ok {
+				continue
+			}
+
+			if offline && !c.Offline() {
+				w.Trigger()
+			}
+			offline = c.Offline()
+		}
+	}()
+}
+
+// Watch probes the targets immediately when the links, addresses or routes
+// change, in addition to the intervals.
+//
+// Bursts of events are debounced. The worker keeps polling when the events are unavailable.
+func (s *ConnectivityService) Watch(e NetworkEventSource) {
+	if e == nil || s.worker == nil {
+		return
+	}
+
+	events, err := e.Events()
+	if err != nil {
+		s.logger.Println(fmt.Errorf("failed watching network events, polling: %w", err))
+		return
+	}
+
+	go debounce(events, networkEventDelay, s.worker.Trigger)
+}
+
+// probeHistory keeps the recent results of probing the targets.
+type probeHistory struct {
+	mutex   sync.Mutex
+	size    int
+	results map[string][]ProbeResult
+}
+
+// newProbeHistory returns a probeHistory that keeps size results per target.
+func newProbeHistory(size int) *probeHistory {
+	return &probeHistory{size: size, results: map[string][]ProbeResult{}}
+}
+
+// record adds the results and returns the connectivity.
+//
+// The status is determined by the latest results, targets that aren't
+// probed anymore are forgotten.
+func (h *probeHistory) record(results []ProbeResult) Connectivity {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	c := Connectivity{Status: connectivityStatus(results), Probes: []ProbeStats{}}
+	history := map[string][]ProbeResult{}
+	for _, r := range results {
+		recent := append(h.results[r.Target], r)
+		if len(recent) > h.size {
+			recent = recent[len(recent)-h.size:]
+		}
+		history[r.Target] = recent
+
+		stats := ProbeStats{Target: r.Target, Success: r.Success}
+		succeeded := 0
+		for _, v := range recent {
+			if v.Success {
+				succeeded++
+				stats.Latency = v.Latency.Round(time.Millisecond)
+			}
+		}
+		stats.SuccessRatio = float64(succeeded) / float64(len(recent))
+		c.Probes = append(c.Probes, stats)
+	}
+
+	h.results = history
+	return c
+}
+
+// connectivityStatus returns the status of the results.
+func connectivityStatus(results []ProbeResult) ConnectivityStatus {
+	succeeded := 0
+	for _, r := range results {
+		if r.Portal {
+			return ConnectivityPortal
+		}
+		if r.Success {
+			succeeded++
+		}
+	}
+
+	switch {
+	case len(results) == 0:
+		return ConnectivityUnknown
+	case succeeded == len(results):
+		return ConnectivityOnline
+	case succeeded > 0:
+		return ConnectivityLimited
+	default:
+		return ConnectivityOffline
+	}
+}
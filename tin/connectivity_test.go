@@ -0,0 +1,150 @@
+package tin
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type connectivityCheckerMock struct{ returnError bool }
+
+func (c connectivityCheckerMock) Check() ([]ProbeResult, error) {
+	if c.returnError {
+		return nil, errors.New("error")
+	}
+
+	return []ProbeResult{{Target: "tcp://1.1.1.1:443", Success: true, Latency: 12 * time.Millisecond}}, nil
+}
+
+func TestNewConnectivityService(t *testing.T) {
+	tt := []struct {
+		want *ConnectivityService
+		got  *ConnectivityService
+	}{
+		{
+			want: &ConnectivityService{},
+			got:  NewConnectivityService(connectivityCheckerMock{}, log.New(ioutil.Discard, "", log.Flags())),
+		},
+		{
+			want: &ConnectivityService{},
+			got:  NewConnectivityService(connectivityCheckerMock{returnError: true}, log.New(ioutil.Discard, "", log.Flags())),
+		},
+		{
+			want: &ConnectivityService{},
+			got:  NewConnectivityService(nil, log.New(ioutil.Discard, "", log.Flags())),
+		},
+	}
+
+	for _, tc := range tt {
+		if reflect.TypeOf(tc.got) != reflect.TypeOf(tc.want) {
+			t.Errorf("want %v, got %v", reflect.TypeOf(tc.want), reflect.TypeOf(tc.got))
+		}
+	}
+}
+
+func TestConnectivityOffline(t *testing.T) {
+	s := NewConnectivityService(nil, log.New(ioutil.Discard, "", log.Flags()))
+
+	tests := []struct {
+		status ConnectivityStatus
+		want   bool
+	}{
+		{status: ConnectivityUnknown, want: false},
+		{status: ConnectivityOnline, want: false},
+		{status: ConnectivityLimited, want: false},
+		{status: ConnectivityOffline, want: true},
+		{status: ConnectivityPortal, want: true},
+	}
+
+	for _, tt := range tests {
+		s.SetConnectivity(Connectivity{Status: tt.status})
+		if got := s.Offline(); got != tt.want {
+			t.Errorf("%v: want %v, got %v", tt.status, tt.want, got)
+		}
+	}
+}
+
+func TestConnectivityStatus(t *testing.T) {
+	ok := ProbeResult{Success: true}
+	failed := ProbeResult{}
+	portal := ProbeResult{Portal: true}
+
+	tests := []struct {
+		results []ProbeResult
+		want    ConnectivityStatus
+	}{
+		{results: []ProbeResult{}, want: ConnectivityUnknown},
+		{results: []ProbeResult{ok, ok}, want: ConnectivityOnline},
+		{results: []ProbeResult{ok, failed}, want: ConnectivityLimited},
+		{results: []ProbeResult{failed, failed}, want: ConnectivityOffline},
+		{results: []ProbeResult{ok, portal}, want: ConnectivityPortal},
+	}
+
+	for _, tt := range tests {
+		if got := connectivityStatus(tt.results); got != tt.want {
+			t.Errorf("want %v, got %v", tt.want, got)
+		}
+	}
+}
+
+func TestProbeHistory(t *testing.T) {
+	h := newProbeHistory(2)
+
+	h.record([]ProbeResult{{Target: "a", Success: true, Latency: 10400 * time.Microsecond}})
+	h.record([]ProbeResult{{Target: "a"}, {Target: "b", Success: true, Latency: time.Millisecond}})
+	got := h.record([]ProbeResult{{Target: "a"}, {Target: "b", Success: true, Latency: time.Millisecond}})
+
+	want := Connectivity{
+		Status: ConnectivityLimited,
+		Probes: []ProbeStats{
+			{Target: "a", Success: false, Latency: 0, SuccessRatio: 0},
+			{Target: "b", Success: true, Latency: time.Millisecond, SuccessRatio: 1},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestProbeHistoryLatency(t *testing.T) {
+	h := newProbeHistory(3)
+
+	h.record([]ProbeResult{{Target: "a", Success: true, Latency: 10400 * time.Microsecond}})
+	got := h.record([]ProbeResult{{Target: "a"}})
+
+	want := Connectivity{
+		Status: ConnectivityOffline,
+		Probes: []ProbeStats{{Target: "a", Success: false, Latency: 10 * time.Millisecond, SuccessRatio: 0.5}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestConnectivityPause(t *testing.T) {
+	s := NewConnectivityService(nil, log.New(ioutil.Discard, "", log.Flags()))
+	s.SetConnectivity(Connectivity{Status: ConnectivityOffline})
+
+	runs := make(chan struct{}, 10)
+	w := NewWorker(time.Hour, func() { runs <- struct{}{} }, log.New(ioutil.Discard, "", log.Flags()))
+	defer w.Stop()
+	<-runs
+
+	s.pause(w)
+	w.Trigger()
+	time.Sleep(10 * time.Millisecond)
+	if got := len(runs); got != 0 {
+		t.Errorf("want %v, got %v", 0, got)
+	}
+
+	// The task is executed when the network is online again.
+	s.SetConnectivity(Connectivity{Status: ConnectivityOnline})
+	select {
+	case <-runs:
+	case <-time.After(3 * time.Second):
+		t.Errorf("want %v, got %v", "task executed", "timeout")
+	}
+}
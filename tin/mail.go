@@ -4,18 +4,56 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"reflect"
+	"sort"
+	"sync"
 	"time"
 )
 
+// ErrUnknownMailAccount means there is no mail account with the given name.
+var ErrUnknownMailAccount = errors.New("unknown mail account")
+
+// ErrUnknownMailQuery means there is no mail query with the given name.
+var ErrUnknownMailQuery = errors.New("unknown mail query")
+
 // MailProvider is the interface implemented by an object that can
 // return unread mails.
 type MailProvider interface {
 	UnreadMails() ([]Mail, error)
 }
 
+// MailWatcher is the interface implemented by a tin.MailProvider that can
+// notify about changes of the mailboxes, for example with IMAP IDLE.
+type MailWatcher interface {
+	Watch() (<-chan struct{}, error)
+}
+
+// MailQueryCounter is the interface implemented by a tin.MailProvider that
+// can count the mails of named queries, for example Gmail searches.
+type MailQueryCounter interface {
+	QueryCounts() (map[string]MailCount, error)
+}
+
+// LocalMailProvider is the interface implemented by a tin.MailProvider that
+// reads local mailboxes, so it doesn't need the network.
+type LocalMailProvider interface {
+	Local() bool
+}
+
+// mailEventDelay is the delay of fetching the unread mails after a change,
+// bursts of changes are merged.
+const mailEventDelay = time.Second
+
 // Mail represents a mail message.
 type Mail struct {
-	Snippet string
+	Account  string // Name of the mail account, set by the tin.MailService.
+	ID       string
+	ThreadID string
+	From     string
+	Subject  string
+	Date     time.Time
+	Labels   []string
+	Snippet  string
 }
 
 // MailCount represents a mail count.
@@ -29,48 +67,207 @@ func (a MailCount) Equal(t interface{}) bool {
 	return false
 }
 
-// UnreadMailCount represents a tin.StateKey.
-const UnreadMailCount StateKey = "UnreadMailCount"
+// MailAccountStatus represents the unread mail count of a mail account.
+type MailAccountStatus struct {
+	Name    string
+	Unread  MailCount            // Count of the last successful fetch.
+	Queries map[string]MailCount // Counts of the named queries of the last successful fetch.
+	Err     string               // Error of the last fetch, empty when it succeeded.
+}
+
+// MailAccounts represents the mail accounts sorted by name.
+type MailAccounts []MailAccountStatus
+
+// Equal implements tin.Comparable.
+func (a MailAccounts) Equal(t interface{}) bool {
+	if b, ok := t.(MailAccounts); ok {
+		return reflect.DeepEqual(a, b)
+	}
+	return false
+}
+
+// Total returns the sum of the unread mail counts.
+func (a MailAccounts) Total() MailCount {
+	total := MailCount(0)
+	for _, v := range a {
+		total += v.Unread
+	}
+	return total
+}
+
+// Represents a tin.StateKey.
+const (
+	UnreadMailCount StateKey = "UnreadMailCount"
+	MailAccountsKey StateKey = "MailAccounts"
+)
+
+// MailQueryKey returns the tin.StateKey of the count of the named query of the account.
+func MailQueryKey(account, query string) StateKey {
+	return StateKey(fmt.Sprintf("MailQuery/%v/%v", account, query))
+}
 
 // MailService provides access to data from mail providers.
 type MailService struct {
-	provider MailProvider
-	state    *State
-	worker   *Worker
-	logger   *log.Logger
+	mutex        sync.Mutex
+	publishMutex sync.Mutex
+	accounts     map[string]MailAccountStatus
+	mails        map[string][]Mail
+	workers      []*Worker
+	remote       []*Worker // Workers of the providers that need the network.
+	state        *State
+	logger       *log.Logger
 }
 
-// NewMailService returns a tin.MailService.
-func NewMailService(p MailProvider, l *log.Logger) *MailService {
+// NewMailService returns a tin.MailService of the mail providers by account name.
+//
+// The unread mails of every account are fetched by a worker of its own, so
+// a failing account doesn't affect the counts of the others.
+func NewMailService(providers map[string]MailProvider, l *log.Logger) *MailService {
 	s := &MailService{
-		provider: p,
+		accounts: map[string]MailAccountStatus{},
+		mails:    map[string][]Mail{},
 		state:    NewState(),
 		logger:   l,
 	}
 
-	// Worker that fetches unread mails on intervals and updates the state.
-	if p == nil {
-		s.logger.Println(errors.New("failed initializing worker"))
-	} else {
-		s.worker = NewWorker(time.Minute, func() {
-			mails, err := s.provider.UnreadMails()
+	names := []string{}
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// The accounts are added before the workers start updating them.
+	for _, name := range names {
+		s.accounts[name] = MailAccountStatus{Name: name}
+	}
+
+	for _, name := range names {
+		p := providers[name]
+
+		// Worker that fetches unread mails on intervals and updates the state.
+		if p == nil {
+			s.logger.Println(fmt.Errorf("failed initializing worker of %v", name))
+			continue
+		}
+
+		name := name
+		w := NewWorker(time.Minute, func() {
+			mails, err := p.UnreadMails()
 			if err != nil {
-				s.logger.Println(fmt.Errorf("worker failed: %w", err))
-			} else {
-				s.state.Set(UnreadMailCount, MailCount(len(mails)))
+				s.logger.Println(fmt.Errorf("worker of %v failed: %w", name, err))
 			}
-		})
+			s.update(name, mails, err)
+			s.updateQueries(name, p)
+		}, s.logger)
+		s.workers = append(s.workers, w)
+		if l, ok := p.(LocalMailProvider); !ok || !l.Local() {
+			s.remote = append(s.remote, w)
+		}
+		s.watch(name, p, w)
 	}
 
+	if len(s.workers) == 0 {
+		s.logger.Println(errors.New("failed initializing worker"))
+	}
+	s.publish()
+
 	return s
 }
 
+// update updates the account and the state, the mails are kept when the fetch failed.
+func (s *MailService) update(name string, mails []Mail, err error) {
+	s.mutex.Lock()
+	a := s.accounts[name]
+	if err != nil {
+		a.Err = err.Error()
+	} else {
+		a.Unread = MailCount(len(mails))
+		a.Err = ""
+
+		for i := range mails {
+			mails[i].Account = name
+		}
+		s.mails[name] = mails
+	}
+	s.accounts[name] = a
+	s.mutex.Unlock()
+
+	s.publish()
+}
+
+// updateQueries updates the account and the state with the query counts of
+// the provider, the counts are kept when counting failed.
+func (s *MailService) updateQueries(name string, p MailProvider) {
+	counter, ok := p.(MailQueryCounter)
+	if !ok {
+		return
+	}
+
+	counts, err := counter.QueryCounts()
+	if err != nil {
+		s.logger.Println(fmt.Errorf("worker of %v failed counting queries: %w", name, err))
+		return
+	}
+
+	s.mutex.Lock()
+	a := s.accounts[name]
+	a.Queries = counts
+	s.accounts[name] = a
+	s.mutex.Unlock()
+
+	for query, count := range counts {
+		s.state.Set(MailQueryKey(name, query), count)
+	}
+	s.publish()
+}
+
+// publish updates the state with the accounts and the total count.
+//
+// The accounts are read and set under one lock, so the snapshot of one
+// worker can't overwrite the newer snapshot of another.
+func (s *MailService) publish() {
+	s.publishMutex.Lock()
+	defer s.publishMutex.Unlock()
+
+	accounts := s.MailAccounts()
+	s.state.Set(MailAccountsKey, accounts)
+	s.state.Set(UnreadMailCount, accounts.Total())
+}
+
+// watch fetches the unread mails immediately when the provider notifies
+// about a change, in addition to the intervals.
+//
+// The worker keeps polling when the provider can't notify.
+func (s *MailService) watch(name string, p MailProvider, w *Worker) {
+	watcher, ok := p.(MailWatcher)
+	if !ok {
+		return
+	}
+
+	events, err := watcher.Watch()
+	if err != nil {
+		s.logger.Println(fmt.Errorf("failed watching mailboxes of %v, polling: %w", name, err))
+		return
+	}
+
+	go debounce(events, mailEventDelay, w.Trigger)
+}
+
+// PauseWhileOffline pauses fetching the unread mails while the network is offline.
+//
+// Local mailboxes keep being fetched, mail is still delivered to them while offline.
+func (s *MailService) PauseWhileOffline(c *ConnectivityService) {
+	for _, w := range s.remote {
+		c.pause(w)
+	}
+}
+
 // Subscribe returns a tin.StateSubscription.
 func (s *MailService) Subscribe() StateSubscription {
 	return s.state.Subscribe()
 }
 
-// UnreadMailCount returns a tin.MailCount.
+// UnreadMailCount returns the tin.MailCount of all accounts.
 func (s *MailService) UnreadMailCount() MailCount {
 	v, err := s.state.Get(UnreadMailCount)
 	if err != nil {
@@ -84,3 +281,97 @@ func (s *MailService) UnreadMailCount() MailCount {
 func (s *MailService) SetUnreadMailCount(m MailCount) {
 	s.state.Set(UnreadMailCount, m)
 }
+
+// MailAccounts returns the tin.MailAccounts.
+func (s *MailService) MailAccounts() MailAccounts {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	accounts := MailAccounts{}
+	for _, a := range s.accounts {
+		accounts = append(accounts, a)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Name < accounts[j].Name })
+	return accounts
+}
+
+// QueryCount returns the tin.MailCount of the named query of the account,
+// or the sum of the accounts that have the query when the account is empty.
+//
+// An error will be returned if there is no account with the given name, or
+// if none of the accounts has the query.
+func (s *MailService) QueryCount(account, query string) (MailCount, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if account != "" {
+		a, ok := s.accounts[account]
+		if !ok {
+			return 0, ErrUnknownMailAccount
+		}
+		count, ok := a.Queries[query]
+		if !ok {
+			return 0, ErrUnknownMailQuery
+		}
+		return count, nil
+	}
+
+	total, found := MailCount(0), false
+	for _, a := range s.accounts {
+		if count, ok := a.Queries[query]; ok {
+			total += count
+			found = true
+		}
+	}
+	if !found {
+		return 0, ErrUnknownMailQuery
+	}
+	return total, nil
+}
+
+// MailAccount returns the tin.MailAccountStatus of the account.
+//
+// An error will be returned if there is no account with the given name.
+func (s *MailService) MailAccount(name string) (MailAccountStatus, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	a, ok := s.accounts[name]
+	if !ok {
+		return MailAccountStatus{}, ErrUnknownMailAccount
+	}
+	return a, nil
+}
+
+// UnreadMails returns the unread mails of the account, or of all accounts
+// when the name is empty, newest first.
+//
+// When the limit is greater than 0 at most limit mails are returned.
+// An error will be returned if there is no account with the given name.
+func (s *MailService) UnreadMails(name string, limit int) ([]Mail, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	mails := []Mail{}
+	if name == "" {
+		for _, v := range s.mails {
+			mails = append(mails, v...)
+		}
+	} else if _, ok := s.accounts[name]; !ok {
+		return nil, ErrUnknownMailAccount
+	} else {
+		mails = append(mails, s.mails[name]...)
+	}
+
+	sort.SliceStable(mails, func(i, j int) bool {
+		if mails[i].Date.Equal(mails[j].Date) {
+			return mails[i].Account < mails[j].Account
+		}
+		return mails[i].Date.After(mails[j].Date)
+	})
+
+	if limit > 0 && len(mails) > limit {
+		mails = mails[:limit]
+	}
+	return mails, nil
+}
@@ -2,15 +2,19 @@ package tin
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
 	"reflect"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type mailProviderMock struct {
 	returnError bool
+	unread      int
 }
 
 func (m mailProviderMock) UnreadMails() ([]Mail, error) {
@@ -18,7 +22,34 @@ func (m mailProviderMock) UnreadMails() ([]Mail, error) {
 		return nil, errors.New("error")
 	}
 
-	return make([]Mail, 0), nil
+	return make([]Mail, m.unread), nil
+}
+
+// failingMailProviderMock fails after the first fetch.
+type failingMailProviderMock struct {
+	fetches *int32
+}
+
+func (m failingMailProviderMock) UnreadMails() ([]Mail, error) {
+	if atomic.AddInt32(m.fetches, 1) > 1 {
+		return nil, errors.New("error")
+	}
+	return make([]Mail, 2), nil
+}
+
+type mailWatcherMock struct {
+	fetches *int32
+	events  chan struct{}
+	err     error
+}
+
+func (m mailWatcherMock) UnreadMails() ([]Mail, error) {
+	atomic.AddInt32(m.fetches, 1)
+	return []Mail{{Snippet: "a"}, {Snippet: "b"}}, nil
+}
+
+func (m mailWatcherMock) Watch() (<-chan struct{}, error) {
+	return m.events, m.err
 }
 
 func TestNewMailService(t *testing.T) {
@@ -28,11 +59,11 @@ func TestNewMailService(t *testing.T) {
 	}{
 		{
 			want: &MailService{},
-			got:  NewMailService(mailProviderMock{returnError: false}, log.New(os.Stdout, "", log.Flags())),
+			got:  NewMailService(map[string]MailProvider{"a": mailProviderMock{returnError: false}}, log.New(os.Stdout, "", log.Flags())),
 		},
 		{
 			want: &MailService{},
-			got:  NewMailService(mailProviderMock{returnError: true}, log.New(os.Stdout, "", log.Flags())),
+			got:  NewMailService(map[string]MailProvider{"a": mailProviderMock{returnError: true}}, log.New(os.Stdout, "", log.Flags())),
 		},
 		{
 			want: &MailService{},
@@ -47,8 +78,283 @@ func TestNewMailService(t *testing.T) {
 	}
 }
 
+func TestMailServiceWatch(t *testing.T) {
+	var fetches int32
+	events := make(chan struct{})
+	s := NewMailService(map[string]MailProvider{"a": mailWatcherMock{fetches: &fetches, events: events}}, log.New(ioutil.Discard, "", log.Flags()))
+	time.Sleep(10 * time.Millisecond)
+
+	events <- struct{}{}
+	events <- struct{}{}
+	time.Sleep(mailEventDelay + 100*time.Millisecond)
+	close(events)
+
+	if got := atomic.LoadInt32(&fetches); got != 2 {
+		t.Errorf("want %v, got %v", 2, got)
+	}
+	if got := s.UnreadMailCount(); got != MailCount(2) {
+		t.Errorf("want %v, got %v", MailCount(2), got)
+	}
+
+	// The worker keeps polling when the provider can't notify.
+	NewMailService(map[string]MailProvider{"a": mailWatcherMock{fetches: &fetches, err: errors.New("error")}}, log.New(ioutil.Discard, "", log.Flags()))
+}
+
+func TestMailServiceAccounts(t *testing.T) {
+	var fetches int32
+	s := NewMailService(map[string]MailProvider{
+		"work":     mailProviderMock{returnError: true},
+		"personal": mailProviderMock{unread: 3},
+		"shared":   failingMailProviderMock{fetches: &fetches},
+		"broken":   nil,
+	}, log.New(ioutil.Discard, "", log.Flags()))
+	time.Sleep(10 * time.Millisecond)
+
+	// The count of the last successful fetch is kept when it fails.
+	s.workers[1].Trigger()
+	time.Sleep(10 * time.Millisecond)
+
+	want := MailAccounts{
+		{Name: "broken"},
+		{Name: "personal", Unread: 3},
+		{Name: "shared", Unread: 2, Err: "error"},
+		{Name: "work", Err: "error"},
+	}
+	if got := s.MailAccounts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if got := s.UnreadMailCount(); got != MailCount(5) {
+		t.Errorf("want %v, got %v", MailCount(5), got)
+	}
+
+	got, err := s.MailAccount("personal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want[1]) {
+		t.Errorf("want %v, got %v", want[1], got)
+	}
+
+	if _, err := s.MailAccount("missing"); err != ErrUnknownMailAccount {
+		t.Errorf("want %v, got %v", ErrUnknownMailAccount, err)
+	}
+}
+
+func TestMailServiceAccountsFailFast(t *testing.T) {
+	// The workers of the first accounts run while the others are added.
+	providers := map[string]MailProvider{}
+	for i := 0; i < 500; i++ {
+		providers[fmt.Sprintf("account%03d", i)] = mailProviderMock{returnError: true}
+	}
+
+	s := NewMailService(providers, log.New(ioutil.Discard, "", log.Flags()))
+	defer func() {
+		for _, w := range s.workers {
+			w.Stop()
+		}
+	}()
+	if got := len(s.MailAccounts()); got != len(providers) {
+		t.Fatalf("want %v, got %v", len(providers), got)
+	}
+
+	// Every account reports the error of its first fetch.
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		failed := 0
+		for _, a := range s.MailAccounts() {
+			if a.Err == "error" {
+				failed++
+			}
+		}
+		if failed == len(providers) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("want %v, got %v", len(providers), failed)
+		}
+	}
+}
+
+// localMailProviderMock counts its fetches and reads local mailboxes.
+type localMailProviderMock struct {
+	fetches *int32
+}
+
+func (m localMailProviderMock) UnreadMails() ([]Mail, error) {
+	atomic.AddInt32(m.fetches, 1)
+	return []Mail{}, nil
+}
+
+func (m localMailProviderMock) Local() bool {
+	return true
+}
+
+func TestMailServicePauseWhileOffline(t *testing.T) {
+	c := NewConnectivityService(nil, log.New(ioutil.Discard, "", log.Flags()))
+	c.SetConnectivity(Connectivity{Status: ConnectivityOffline})
+
+	var local, remote int32
+	s := NewMailService(map[string]MailProvider{
+		"local":  localMailProviderMock{fetches: &local},
+		"remote": mailWatcherMock{fetches: &remote, err: errors.New("error")},
+	}, log.New(ioutil.Discard, "", log.Flags()))
+	waitFetches := func(fetches *int32, want int32) {
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+			if atomic.LoadInt32(fetches) >= want {
+				return
+			}
+		}
+		t.Fatalf("want %v, got %v", want, atomic.LoadInt32(fetches))
+	}
+	waitFetches(&local, 1)
+	waitFetches(&remote, 1)
+	s.PauseWhileOffline(c)
+
+	// Local mail is still delivered while offline.
+	for _, w := range s.workers {
+		w.Trigger()
+	}
+	waitFetches(&local, 2)
+	time.Sleep(10 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&remote); got != 1 {
+		t.Errorf("want %v, got %v", 1, got)
+	}
+}
+
+type datedMailProviderMock struct {
+	mails []Mail
+}
+
+func (m datedMailProviderMock) UnreadMails() ([]Mail, error) {
+	return append([]Mail{}, m.mails...), nil
+}
+
+func TestMailServiceUnreadMails(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2020, 1, d, 0, 0, 0, 0, time.UTC) }
+	s := NewMailService(map[string]MailProvider{
+		"personal": datedMailProviderMock{mails: []Mail{{ID: "p1", Date: day(1)}, {ID: "p3", Date: day(3)}}},
+		"work":     datedMailProviderMock{mails: []Mail{{ID: "w2", Date: day(2)}}},
+	}, log.New(ioutil.Discard, "", log.Flags()))
+	time.Sleep(10 * time.Millisecond)
+
+	tt := []struct {
+		account string
+		limit   int
+		want    []Mail
+	}{
+		{
+			want: []Mail{
+				{Account: "personal", ID: "p3", Date: day(3)},
+				{Account: "work", ID: "w2", Date: day(2)},
+				{Account: "personal", ID: "p1", Date: day(1)},
+			},
+		},
+		{
+			limit: 1,
+			want:  []Mail{{Account: "personal", ID: "p3", Date: day(3)}},
+		},
+		{
+			account: "work",
+			want:    []Mail{{Account: "work", ID: "w2", Date: day(2)}},
+		},
+	}
+
+	for _, tc := range tt {
+		got, err := s.UnreadMails(tc.account, tc.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("want %v, got %v", tc.want, got)
+		}
+	}
+
+	if _, err := s.UnreadMails("missing", 0); err != ErrUnknownMailAccount {
+		t.Errorf("want %v, got %v", ErrUnknownMailAccount, err)
+	}
+}
+
+type mailQueryCounterMock struct {
+	mailProviderMock
+	counts map[string]MailCount
+}
+
+func (m mailQueryCounterMock) QueryCounts() (map[string]MailCount, error) {
+	return m.counts, nil
+}
+
+func TestMailServiceQueryCount(t *testing.T) {
+	s := NewMailService(map[string]MailProvider{
+		"personal": mailQueryCounterMock{counts: map[string]MailCount{"inbox": 2, "alerts": 1}},
+		"work":     mailQueryCounterMock{counts: map[string]MailCount{"inbox": 3}},
+		"local":    mailProviderMock{unread: 4},
+	}, log.New(ioutil.Discard, "", log.Flags()))
+
+	// The queries of both accounts are counted and published.
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		inbox, _ := s.QueryCount("", "inbox")
+		_, err := s.state.Get(MailQueryKey("personal", "alerts"))
+		if inbox == 5 && err == nil {
+			break
+		}
+	}
+
+	tt := []struct {
+		account string
+		query   string
+		want    MailCount
+		err     error
+	}{
+		{query: "inbox", want: 5},
+		{query: "alerts", want: 1},
+		{account: "work", query: "inbox", want: 3},
+		{query: "missing", err: ErrUnknownMailQuery},
+		{account: "work", query: "alerts", err: ErrUnknownMailQuery},
+		{account: "missing", query: "inbox", err: ErrUnknownMailAccount},
+	}
+
+	for _, tc := range tt {
+		got, err := s.QueryCount(tc.account, tc.query)
+		if err != tc.err {
+			t.Errorf("want %v, got %v", tc.err, err)
+		}
+		if got != tc.want {
+			t.Errorf("want %v, got %v", tc.want, got)
+		}
+	}
+
+	// Every query is published as a state key of its own.
+	v, err := s.state.Get(MailQueryKey("personal", "alerts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != MailCount(1) {
+		t.Errorf("want %v, got %v", MailCount(1), v)
+	}
+}
+
+func TestMailAccountsEqual(t *testing.T) {
+	a := MailAccounts{{Name: "a", Unread: 1}}
+
+	tt := []struct {
+		b    interface{}
+		want bool
+	}{
+		{b: MailAccounts{{Name: "a", Unread: 1}}, want: true},
+		{b: MailAccounts{{Name: "a", Unread: 2}}, want: false},
+		{b: MailCount(1), want: false},
+	}
+
+	for _, tc := range tt {
+		if got := a.Equal(tc.b); got != tc.want {
+			t.Errorf("want %v, got %v", tc.want, got)
+		}
+	}
+}
+
 func TestMailSubscribe(t *testing.T) {
-	s := NewMailService(mailProviderMock{}, log.New(os.Stdout, "", log.Flags()))
+	s := NewMailService(map[string]MailProvider{"a": mailProviderMock{}}, log.New(os.Stdout, "", log.Flags()))
 	want := StateSubscription{}
 	got := s.Subscribe()
 
@@ -4,7 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net"
+	"reflect"
+	"sync"
 	"time"
 )
 
@@ -15,11 +18,46 @@ type ESSIDLookup interface {
 }
 
 // PublicIPLookup is the interface implemented by an object that can
-// lookup the IP, City and Country.
+// lookup the public IP addresses.
 type PublicIPLookup interface {
 	Lookup() (PublicIP, error)
 }
 
+// IPEnricher is the interface implemented by an object that can
+// lookup the location and network of an IP address.
+type IPEnricher interface {
+	Enrich(ip net.IP) (IPDetails, error)
+}
+
+// LocalNetworkLookup is the interface implemented by an object that can
+// lookup the network interfaces, routes and DNS servers.
+type LocalNetworkLookup interface {
+	Lookup() (LocalNetwork, error)
+}
+
+// WirelessLinkLookup is the interface implemented by an object that can
+// lookup the link of the connected wireless interface.
+type WirelessLinkLookup interface {
+	Lookup() (WirelessLink, error)
+}
+
+// VPNLookup is the interface implemented by an object that can
+// lookup the active VPN.
+type VPNLookup interface {
+	Lookup() (VPN, error)
+}
+
+// NetworkEventSource is the interface implemented by an object that can
+// notify about changes of the links, addresses and routes.
+//
+// The channel is closed when the events are no longer available.
+type NetworkEventSource interface {
+	Events() (<-chan struct{}, error)
+}
+
+// networkEventDelay is the duration without events after which the workers are executed.
+const networkEventDelay = time.Second
+
 // ESSID represents the network name.
 type ESSID string
 
@@ -31,42 +69,197 @@ func (a ESSID) Equal(t interface{}) bool {
 	return false
 }
 
-// PublicIP represents the IP address.
+// PublicIP represents the public IPv4 and IPv6 addresses.
+//
+// An address is nil when the network doesn't have one of the address family.
 type PublicIP struct {
-	net.IP
+	IPv4        net.IP
+	IPv6        net.IP
+	IPv4Sources []string // Sources that answered the IPv4 address.
+	IPv6Sources []string // Sources that answered the IPv6 address.
+	Details     IPDetails
+}
+
+// IPDetails represents the location and network of an IP address.
+type IPDetails struct {
+	Country     string
+	CountryCode string // ISO 3166-1 alpha-2 code.
+	City        string
+	ASN         uint // Autonomous system number.
+	ISP         string
+}
+
+// String returns the IPv4 address, or the IPv6 address when there is no IPv4 address.
+func (a PublicIP) String() string {
+	if a.IPv4 == nil && a.IPv6 != nil {
+		return a.IPv6.String()
+	}
+	return a.IPv4.String()
 }
 
 // Equal implements tin.Comparable.
+//
+// The sources aren't compared, they depend on which sources answered first.
 func (a PublicIP) Equal(t interface{}) bool {
 	if b, ok := t.(PublicIP); ok {
-		return a.String() == b.String()
+		return a.IPv4.Equal(b.IPv4) && a.IPv6.Equal(b.IPv6) && a.Details == b.Details
+	}
+	return false
+}
+
+// NetworkInterface represents a network interface.
+type NetworkInterface struct {
+	Name      string
+	State     string // Operational state, for example up or down.
+	MAC       string
+	MTU       int
+	Addresses []string // Addresses in CIDR notation.
+}
+
+// Route represents a route of the routing table.
+type Route struct {
+	Destination string // Destination in CIDR notation.
+	Gateway     string // Empty when the destination is directly connected.
+	Interface   string
+	Metric      int
+}
+
+// Default reports whether it's a default route.
+func (r Route) Default() bool {
+	return r.Destination == "0.0.0.0/0" || r.Destination == "::/0"
+}
+
+// LocalNetwork represents the network interfaces, routes and DNS servers.
+type LocalNetwork struct {
+	Interfaces []NetworkInterface
+	Routes     []Route
+	DNSServers []string
+}
+
+// DefaultRoutes returns the default routes.
+func (a LocalNetwork) DefaultRoutes() []Route {
+	rr := []Route{}
+	for _, r := range a.Routes {
+		if r.Default() {
+			rr = append(rr, r)
+		}
+	}
+	return rr
+}
+
+// Equal implements tin.Comparable.
+func (a LocalNetwork) Equal(t interface{}) bool {
+	if b, ok := t.(LocalNetwork); ok {
+		return reflect.DeepEqual(a, b)
+	}
+	return false
+}
+
+// WirelessLink represents the link of a wireless interface.
+//
+// The zero value means there is no connected wireless interface.
+type WirelessLink struct {
+	Interface      string
+	SSID           string
+	BSSID          string
+	Frequency      int     // MHz.
+	Signal         int     // dBm.
+	Quality        int     // Percentage.
+	TxBitrate      float64 // Mbit/s.
+	ConnectedSince time.Time
+}
+
+// Connected reports whether the interface is connected.
+func (a WirelessLink) Connected() bool {
+	return a.BSSID != ""
+}
+
+// Channel returns the channel of the frequency, or 0 when it's unknown.
+func (a WirelessLink) Channel() int {
+	switch {
+	case a.Frequency == 2484:
+		return 14
+	case a.Frequency >= 2412 && a.Frequency < 2484:
+		return (a.Frequency - 2407) / 5
+	case a.Frequency >= 5160 && a.Frequency <= 5885:
+		return (a.Frequency - 5000) / 5
+	case a.Frequency >= 5955 && a.Frequency <= 7115:
+		return (a.Frequency - 5950) / 5
+	default:
+		return 0
+	}
+}
+
+// Equal implements tin.Comparable.
+func (a WirelessLink) Equal(t interface{}) bool {
+	if b, ok := t.(WirelessLink); ok {
+		return a == b
+	}
+	return false
+}
+
+// VPN represents an active VPN or tunnel.
+//
+// The zero value means there is no active VPN.
+type VPN struct {
+	Name            string // Kind of the VPN, for example WireGuard.
+	Interface       string
+	Endpoint        string    // Address of the peer, empty when it's unknown.
+	Since           time.Time // Time the VPN was first seen active.
+	LatestHandshake time.Time // Latest handshake with the peer of a WireGuard VPN.
+	DefaultRoute    bool      // The VPN owns the default route.
+}
+
+// Active reports whether there is an active VPN.
+func (a VPN) Active() bool {
+	return a.Interface != ""
+}
+
+// Equal implements tin.Comparable.
+func (a VPN) Equal(t interface{}) bool {
+	if b, ok := t.(VPN); ok {
+		return a == b
 	}
 	return false
 }
 
 // Represents tin.StateKey.
 const (
-	NetworkName StateKey = "NetworkName"
-	IP                   = "IP"
+	NetworkName         StateKey = "NetworkName"
+	IP                           = "IP"
+	NetworkLocal                 = "LocalNetwork"
+	NetworkWirelessLink          = "WirelessLink"
+	NetworkVPN                   = "VPN"
 )
 
 // NetworkService provides network information.
 type NetworkService struct {
-	nameLookup     ESSIDLookup
-	publicIPLookup PublicIPLookup
-	state          *State
-	nameWorker     *Worker
-	publicIPWorker *Worker
-	logger         *log.Logger
+	nameLookup         ESSIDLookup
+	publicIPLookup     PublicIPLookup
+	localNetworkLookup LocalNetworkLookup
+	wirelessLookup     WirelessLinkLookup
+	vpnLookup          VPNLookup
+	wirelessSmoother   *wirelessLinkSmoother
+	state              *State
+	nameWorker         *Worker
+	publicIPWorker     *Worker
+	localNetworkWorker *Worker
+	wirelessWorker     *Worker
+	vpnWorker          *Worker
+	logger             *log.Logger
 }
 
 // NewNetworkService returns tin.NetworkService.
-func NewNetworkService(n ESSIDLookup, p PublicIPLookup, l *log.Logger) *NetworkService {
+func NewNetworkService(n ESSIDLookup, p PublicIPLookup, ln LocalNetworkLookup, w WirelessLinkLookup, v VPNLookup, l *log.Logger) *NetworkService {
 	s := &NetworkService{
-		nameLookup:     n,
-		publicIPLookup: p,
-		state:          NewState(),
-		logger:         l,
+		nameLookup:         n,
+		publicIPLookup:     p,
+		localNetworkLookup: ln,
+		wirelessLookup:     w,
+		vpnLookup:          v,
+		wirelessSmoother:   &wirelessLinkSmoother{},
+		state:              NewState(),
+		logger:             l,
 	}
 
 	// Worker that lookup the network name on intervals and updates the state.
@@ -80,26 +273,99 @@ func NewNetworkService(n ESSIDLookup, p PublicIPLookup, l *log.Logger) *NetworkS
 			} else {
 				s.SetName(name)
 			}
-		})
+		}, s.logger)
 	}
 
-	// Worker that lookup the public IP, city and country on intervals and updates the state.
+	// Worker that lookup the public IP addresses on intervals and updates the state.
 	if p == nil {
 		s.logger.Println(errors.New("failed initializing public IP lookup worker"))
 	} else {
 		s.publicIPWorker = NewWorker(time.Minute, func() {
 			publicIP, err := s.publicIPLookup.Lookup()
+			if usable(err, s.logger) {
+				s.SetIP(publicIP)
+			}
+		}, s.logger)
+	}
+
+	// Worker that lookup the interfaces, routes and DNS servers on intervals and updates the state.
+	if ln == nil {
+		s.logger.Println(errors.New("failed initializing local network worker"))
+	} else {
+		s.localNetworkWorker = NewWorker(10*time.Second, func() {
+			localNetwork, err := s.localNetworkLookup.Lookup()
 			if err != nil {
 				s.logger.Println(fmt.Errorf("worker failed: %w", err))
 			} else {
-				s.SetIP(publicIP)
+				s.SetLocalNetwork(localNetwork)
 			}
-		})
+		}, s.logger)
+	}
+
+	// Worker that lookup the wireless link on intervals and updates the state.
+	if w == nil {
+		s.logger.Println(errors.New("failed initializing wireless link worker"))
+	} else {
+		s.wirelessWorker = NewWorker(5*time.Second, func() {
+			link, err := s.wirelessLookup.Lookup()
+			if err != nil {
+				s.logger.Println(fmt.Errorf("worker failed: %w", err))
+			} else {
+				s.SetWirelessLink(s.wirelessSmoother.smooth(link))
+			}
+		}, s.logger)
+	}
+
+	// Worker that lookup the VPN on intervals and updates the state.
+	if v == nil {
+		s.logger.Println(errors.New("failed initializing vpn worker"))
+	} else {
+		s.vpnWorker = NewWorker(5*time.Second, func() {
+			vpn, err := s.vpnLookup.Lookup()
+			if err != nil {
+				s.logger.Println(fmt.Errorf("worker failed: %w", err))
+			} else {
+				s.updateVPN(vpn)
+			}
+		}, s.logger)
 	}
 
 	return s
 }
 
+// PauseWhileOffline pauses the public IP lookup while the network is offline.
+func (s *NetworkService) PauseWhileOffline(c *ConnectivityService) {
+	if s.publicIPWorker != nil {
+		c.pause(s.publicIPWorker)
+	}
+}
+
+// Watch executes the workers immediately when the links, addresses or routes
+// change, in addition to the intervals.
+//
+// Bursts of events, for example while reconnecting, are debounced. The workers
+// keep polling when the events are unavailable.
+func (s *NetworkService) Watch(e NetworkEventSource) {
+	if e == nil {
+		s.logger.Println(errors.New("failed watching network events, polling"))
+		return
+	}
+
+	events, err := e.Events()
+	if err != nil {
+		s.logger.Println(fmt.Errorf("failed watching network events, polling: %w", err))
+		return
+	}
+
+	go debounce(events, networkEventDelay, func() {
+		for _, w := range []*Worker{s.nameWorker, s.wirelessWorker, s.localNetworkWorker, s.vpnWorker, s.publicIPWorker} {
+			if w != nil {
+				w.Trigger()
+			}
+		}
+	})
+}
+
 // Subscribe returns a tin.StateSubscription.
 func (s *NetworkService) Subscribe() StateSubscription {
 	return s.state.Subscribe()
@@ -130,7 +396,215 @@ func (s *NetworkService) IP() string {
 	return v.(PublicIP).String()
 }
 
+// PublicIP returns a tin.PublicIP.
+func (s *NetworkService) PublicIP() PublicIP {
+	v, err := s.state.Get(IP)
+	if err != nil {
+		return PublicIP{}
+	}
+
+	return v.(PublicIP)
+}
+
 // SetIP updates the state.
 func (s *NetworkService) SetIP(ip PublicIP) {
 	s.state.Set(IP, ip)
 }
+
+// LocalNetwork returns a tin.LocalNetwork.
+func (s *NetworkService) LocalNetwork() LocalNetwork {
+	v, err := s.state.Get(NetworkLocal)
+	if err != nil {
+		return LocalNetwork{Interfaces: []NetworkInterface{}, Routes: []Route{}, DNSServers: []string{}}
+	}
+
+	return v.(LocalNetwork)
+}
+
+// SetLocalNetwork updates the state.
+//
+// Any change publishes the tin.LocalNetwork, besides the addresses and routes
+// also the state and MTU of an interface and the DNS servers.
+func (s *NetworkService) SetLocalNetwork(n LocalNetwork) {
+	s.state.Set(NetworkLocal, n)
+}
+
+// WirelessLink returns a tin.WirelessLink.
+func (s *NetworkService) WirelessLink() WirelessLink {
+	v, err := s.state.Get(NetworkWirelessLink)
+	if err != nil {
+		return WirelessLink{}
+	}
+
+	return v.(WirelessLink)
+}
+
+// SetWirelessLink updates the state.
+func (s *NetworkService) SetWirelessLink(w WirelessLink) {
+	s.state.Set(NetworkWirelessLink, w)
+}
+
+// wirelessLinkSmoother smooths the fluctuating values of successive wireless links.
+//
+// The signal is an exponential moving average, the signal, quality and bitrate
+// only change when they differ enough from the previous link.
+type wirelessLinkSmoother struct {
+	mutex    sync.Mutex
+	previous WirelessLink
+	average  float64
+}
+
+// Represents the thresholds of the wirelessLinkSmoother.
+const (
+	wirelessSignalWeight    = 0.3 // Weight of the latest signal in the average.
+	wirelessSignalThreshold = 3   // dBm.
+	wirelessBitrateChange   = 0.2 // Relative change.
+)
+
+// smooth returns the link with the smoothed values.
+//
+// A link to another access point isn't smoothed, the connected time is kept
+// from the previous link when it's the same access point.
+func (w *wirelessLinkSmoother) smooth(link WirelessLink) WirelessLink {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	p := w.previous
+	if !link.Connected() || link.BSSID != p.BSSID || link.Interface != p.Interface {
+		w.previous = link
+		w.average = float64(link.Signal)
+		return link
+	}
+
+	w.average = wirelessSignalWeight*float64(link.Signal) + (1-wirelessSignalWeight)*w.average
+	signal := int(math.Round(w.average))
+	if abs(signal-p.Signal) < wirelessSignalThreshold {
+		link.Signal = p.Signal
+		link.Quality = p.Quality
+	} else {
+		link.Signal = signal
+	}
+
+	if p.TxBitrate > 0 && math.Abs(link.TxBitrate-p.TxBitrate)/p.TxBitrate < wirelessBitrateChange {
+		link.TxBitrate = p.TxBitrate
+	}
+
+	if !p.ConnectedSince.IsZero() {
+		link.ConnectedSince = p.ConnectedSince
+	}
+
+	w.previous = link
+	return link
+}
+
+// abs returns the absolute value of the integer.
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// VPN returns a tin.VPN.
+func (s *NetworkService) VPN() VPN {
+	v, err := s.state.Get(NetworkVPN)
+	if err != nil {
+		return VPN{}
+	}
+
+	return v.(VPN)
+}
+
+// SetVPN updates the state.
+func (s *NetworkService) SetVPN(v VPN) {
+	s.state.Set(NetworkVPN, v)
+}
+
+// updateVPN updates the state with the looked up VPN.
+//
+// The time the VPN was first seen is kept while the interface stays the same.
+// The public IP is looked up again when the VPN or its default route changes,
+// because the traffic leaves the network through another address.
+func (s *NetworkService) updateVPN(v VPN) {
+	previous, err := s.state.Get(NetworkVPN)
+	p, _ := previous.(VPN)
+	switch {
+	case !v.Active():
+		v = VPN{}
+	case p.Interface == v.Interface && !p.Since.IsZero():
+		v.Since = p.Since
+	default:
+		v.Since = time.Now()
+	}
+	s.SetVPN(v)
+
+	changed := p.Interface != v.Interface || p.DefaultRoute != v.DefaultRoute
+	if err == nil && changed && s.publicIPWorker != nil {
+		s.publicIPWorker.Trigger()
+	}
+}
+
+// ipDetailsCacheSize is the maximum number of IP addresses of which the details are cached.
+const ipDetailsCacheSize = 16
+
+// NewEnrichedIPLookup returns a tin.PublicIPLookup that adds the details of
+// the IP address to the looked up public IP.
+//
+// The details are cached per IP address, the enricher is only used when the
+// address changes. Failing to enrich is a warning, the public IP is still usable.
+func NewEnrichedIPLookup(p PublicIPLookup, e IPEnricher) PublicIPLookup {
+	if p == nil || e == nil {
+		return p
+	}
+
+	return &enrichedIPLookup{
+		lookup:   p,
+		enricher: e,
+		cache:    map[string]IPDetails{},
+	}
+}
+
+// enrichedIPLookup implements tin.PublicIPLookup.
+type enrichedIPLookup struct {
+	lookup   PublicIPLookup
+	enricher IPEnricher
+	mutex    sync.Mutex
+	cache    map[string]IPDetails
+}
+
+// Lookup returns a tin.PublicIP with the details of the IPv4 address, or
+// of the IPv6 address when there is no IPv4 address.
+func (l *enrichedIPLookup) Lookup() (PublicIP, error) {
+	ip, err := l.lookup.Lookup()
+	if err != nil {
+		return ip, err
+	}
+
+	address := ip.IPv4
+	if address == nil {
+		address = ip.IPv6
+	}
+	if address == nil {
+		return ip, nil
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if details, ok := l.cache[address.String()]; ok {
+		ip.Details = details
+		return ip, nil
+	}
+
+	details, err := l.enricher.Enrich(address)
+	if err != nil {
+		return ip, Warnings{}.Append(fmt.Errorf("failed enriching %v: %w", address, err))
+	}
+
+	if len(l.cache) >= ipDetailsCacheSize {
+		l.cache = map[string]IPDetails{}
+	}
+	l.cache[address.String()] = details
+	ip.Details = details
+	return ip, nil
+}
@@ -6,7 +6,9 @@ import (
 	"log"
 	"net"
 	"reflect"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type essidLookupMock struct {
@@ -40,19 +42,19 @@ func TestNewNetworkService(t *testing.T) {
 	}{
 		{
 			want: &NetworkService{},
-			got:  NewNetworkService(essidLookupMock{}, publicIPLookupMock{}, log.New(ioutil.Discard, "", log.Flags())),
+			got:  NewNetworkService(essidLookupMock{}, publicIPLookupMock{}, nil, nil, nil, log.New(ioutil.Discard, "", log.Flags())),
 		},
 		{
 			want: &NetworkService{},
-			got:  NewNetworkService(essidLookupMock{}, publicIPLookupMock{}, log.New(ioutil.Discard, "", log.Flags())),
+			got:  NewNetworkService(essidLookupMock{}, publicIPLookupMock{}, nil, nil, nil, log.New(ioutil.Discard, "", log.Flags())),
 		},
 		{
 			want: &NetworkService{},
-			got:  NewNetworkService(essidLookupMock{returnError: true}, publicIPLookupMock{returnError: true}, log.New(ioutil.Discard, "", log.Flags())),
+			got:  NewNetworkService(essidLookupMock{returnError: true}, publicIPLookupMock{returnError: true}, nil, nil, nil, log.New(ioutil.Discard, "", log.Flags())),
 		},
 		{
 			want: &NetworkService{},
-			got:  NewNetworkService(nil, nil, log.New(ioutil.Discard, "", log.Flags())),
+			got:  NewNetworkService(nil, nil, nil, nil, nil, log.New(ioutil.Discard, "", log.Flags())),
 		},
 	}
 
@@ -64,7 +66,7 @@ func TestNewNetworkService(t *testing.T) {
 }
 
 func TestNetworkSubscribe(t *testing.T) {
-	s := NewNetworkService(essidLookupMock{}, publicIPLookupMock{}, log.New(ioutil.Discard, "", log.Flags()))
+	s := NewNetworkService(essidLookupMock{}, publicIPLookupMock{}, nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
 	want := StateSubscription{}
 	got := s.Subscribe()
 
@@ -74,7 +76,7 @@ func TestNetworkSubscribe(t *testing.T) {
 }
 
 func TestNetworkName(t *testing.T) {
-	withState := NewNetworkService(nil, publicIPLookupMock{}, log.New(ioutil.Discard, "", log.Flags()))
+	withState := NewNetworkService(nil, publicIPLookupMock{}, nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
 	withState.SetName("Network name")
 
 	tt := []struct {
@@ -86,7 +88,7 @@ func TestNetworkName(t *testing.T) {
 			want:    "Network name",
 		},
 		{
-			service: NewNetworkService(nil, publicIPLookupMock{}, log.New(ioutil.Discard, "", log.Flags())),
+			service: NewNetworkService(nil, publicIPLookupMock{}, nil, nil, nil, log.New(ioutil.Discard, "", log.Flags())),
 			want:    "",
 		},
 	}
@@ -101,7 +103,7 @@ func TestNetworkName(t *testing.T) {
 }
 
 func TestNetworkSetName(t *testing.T) {
-	s := NewNetworkService(nil, publicIPLookupMock{}, log.New(ioutil.Discard, "", log.Flags()))
+	s := NewNetworkService(nil, publicIPLookupMock{}, nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
 	s.SetName("name")
 
 	want := ESSID("name")
@@ -113,8 +115,8 @@ func TestNetworkSetName(t *testing.T) {
 }
 
 func TestNetworkIP(t *testing.T) {
-	withState := NewNetworkService(nil, nil, log.New(ioutil.Discard, "", log.Flags()))
-	withState.SetIP(PublicIP{net.IPv4(0, 0, 0, 0)})
+	withState := NewNetworkService(nil, nil, nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
+	withState.SetIP(PublicIP{IPv4: net.IPv4(0, 0, 0, 0)})
 
 	tt := []struct {
 		service *NetworkService
@@ -125,7 +127,7 @@ func TestNetworkIP(t *testing.T) {
 			want:    "0.0.0.0",
 		},
 		{
-			service: NewNetworkService(nil, nil, log.New(ioutil.Discard, "", log.Flags())),
+			service: NewNetworkService(nil, nil, nil, nil, nil, log.New(ioutil.Discard, "", log.Flags())),
 			want:    "Unknown",
 		},
 	}
@@ -139,6 +141,23 @@ func TestNetworkIP(t *testing.T) {
 	}
 }
 
+func TestPublicIPString(t *testing.T) {
+	tests := []struct {
+		ip   PublicIP
+		want string
+	}{
+		{ip: PublicIP{IPv4: net.ParseIP("127.0.0.1"), IPv6: net.ParseIP("2001:db8::1")}, want: "127.0.0.1"},
+		{ip: PublicIP{IPv6: net.ParseIP("2001:db8::1")}, want: "2001:db8::1"},
+		{ip: PublicIP{}, want: "<nil>"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.ip.String(); got != tt.want {
+			t.Errorf("want %v, got %v", tt.want, got)
+		}
+	}
+}
+
 func TestESSIDEqualTrue(t *testing.T) {
 	a := ESSID("WIFI_NAME")
 	b := ESSID("WIFI_NAME")
@@ -180,14 +199,21 @@ func TestESSIDEqualFalse(t *testing.T) {
 }
 
 func TestPublicIPEqualTrue(t *testing.T) {
-	a := PublicIP{IP: net.ParseIP("127.0.0.1")}
-	b := PublicIP{IP: net.ParseIP("127.0.0.1")}
+	a := PublicIP{IPv4: net.ParseIP("127.0.0.1")}
+	b := PublicIP{IPv4: net.ParseIP("127.0.0.1")}
 
 	want := true
 	got := a.Equal(b)
 	if got != want {
 		t.Errorf("want %v, got %v", want, got)
 	}
+
+	// The sources depend on which sources answered first.
+	a = PublicIP{IPv4: net.ParseIP("127.0.0.1"), IPv4Sources: []string{"https://ifconfig.me/ip"}}
+	b = PublicIP{IPv4: net.ParseIP("127.0.0.1"), IPv4Sources: []string{"https://ifconfig.co/ip"}}
+	if got := a.Equal(b); got != want {
+		t.Errorf("want %v, got %v", want, got)
+	}
 }
 
 func TestPublicIPEqualFalse(t *testing.T) {
@@ -196,15 +222,19 @@ func TestPublicIPEqualFalse(t *testing.T) {
 		b interface{}
 	}{
 		{
-			a: PublicIP{IP: net.ParseIP("127.0.0.1")},
-			b: PublicIP{IP: net.ParseIP("0.0.0.0")},
+			a: PublicIP{IPv4: net.ParseIP("127.0.0.1")},
+			b: PublicIP{IPv4: net.ParseIP("0.0.0.0")},
 		},
 		{
-			a: PublicIP{IP: net.ParseIP("127.0.0.1")},
+			a: PublicIP{IPv4: net.ParseIP("127.0.0.1"), IPv6: net.ParseIP("2001:db8::1")},
+			b: PublicIP{IPv4: net.ParseIP("127.0.0.1"), IPv6: net.ParseIP("2001:db8::2")},
+		},
+		{
+			a: PublicIP{IPv4: net.ParseIP("127.0.0.1")},
 			b: "127.0.0.1",
 		},
 		{
-			a: PublicIP{IP: net.ParseIP("127.0.0.1")},
+			a: PublicIP{IPv4: net.ParseIP("127.0.0.1")},
 			b: "0.0.0.0",
 		},
 	}
@@ -218,3 +248,247 @@ func TestPublicIPEqualFalse(t *testing.T) {
 		}
 	}
 }
+
+func TestNetworkLocalNetwork(t *testing.T) {
+	s := NewNetworkService(nil, nil, nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
+	if got := s.LocalNetwork(); len(got.Interfaces) != 0 || len(got.Routes) != 0 || len(got.DNSServers) != 0 {
+		t.Errorf("want %v, got %v", LocalNetwork{}, got)
+	}
+
+	want := LocalNetwork{
+		Interfaces: []NetworkInterface{{Name: "wlan0", State: "up", Addresses: []string{"192.168.1.10/24"}}},
+		Routes: []Route{
+			{Destination: "0.0.0.0/0", Gateway: "192.168.1.1", Interface: "wlan0"},
+			{Destination: "192.168.1.0/24", Interface: "wlan0"},
+		},
+		DNSServers: []string{"192.168.1.1"},
+	}
+	s.SetLocalNetwork(want)
+
+	got := s.LocalNetwork()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	if r := got.DefaultRoutes(); len(r) != 1 || r[0].Gateway != "192.168.1.1" {
+		t.Errorf("want %v, got %v", want.Routes[:1], r)
+	}
+}
+
+func TestLocalNetworkEqual(t *testing.T) {
+	a := LocalNetwork{Interfaces: []NetworkInterface{{Name: "wlan0", Addresses: []string{"192.168.1.10/24"}}}}
+	b := LocalNetwork{Interfaces: []NetworkInterface{{Name: "wlan0", Addresses: []string{"192.168.1.11/24"}}}}
+
+	if !a.Equal(a) || a.Equal(b) || a.Equal("wlan0") {
+		t.Errorf("want %v, got %v", "only equal to itself", a)
+	}
+}
+
+func TestNetworkWirelessLink(t *testing.T) {
+	s := NewNetworkService(nil, nil, nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
+	if got := s.WirelessLink(); got.Connected() {
+		t.Errorf("want %v, got %v", WirelessLink{}, got)
+	}
+
+	want := WirelessLink{Interface: "wlan0", SSID: "network-name", BSSID: "3c:22:fb:12:34:56", Signal: -50}
+	s.SetWirelessLink(want)
+
+	if got := s.WirelessLink(); got != want {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestWirelessLinkChannel(t *testing.T) {
+	tests := []struct {
+		frequency int
+		want      int
+	}{
+		{frequency: 2412, want: 1},
+		{frequency: 2484, want: 14},
+		{frequency: 5180, want: 36},
+		{frequency: 5955, want: 1},
+		{frequency: 900, want: 0},
+	}
+
+	for _, tt := range tests {
+		got := WirelessLink{Frequency: tt.frequency}.Channel()
+		if got != tt.want {
+			t.Errorf("want %v, got %v", tt.want, got)
+		}
+	}
+}
+
+func TestWirelessLinkEqual(t *testing.T) {
+	a := WirelessLink{BSSID: "3c:22:fb:12:34:56", Signal: -50}
+	b := WirelessLink{BSSID: "3c:22:fb:12:34:56", Signal: -60}
+
+	if !a.Equal(a) || a.Equal(b) || a.Equal("3c:22:fb:12:34:56") {
+		t.Errorf("want %v, got %v", "only equal to itself", a)
+	}
+}
+
+func TestWirelessLinkSmoother(t *testing.T) {
+	w := &wirelessLinkSmoother{}
+	since := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	link := WirelessLink{Interface: "wlan0", BSSID: "3c:22:fb:12:34:56", Signal: -50, Quality: 85, TxBitrate: 866.7, ConnectedSince: since}
+
+	if got := w.smooth(link); got != link {
+		t.Errorf("want %v, got %v", link, got)
+	}
+
+	// Small fluctuations are ignored.
+	next := link
+	next.Signal, next.Quality, next.TxBitrate, next.ConnectedSince = -52, 80, 780, since.Add(time.Second)
+	if got := w.smooth(next); got != link {
+		t.Errorf("want %v, got %v", link, got)
+	}
+
+	// A large drop moves the average.
+	next.Signal, next.Quality, next.TxBitrate = -70, 40, 400
+	want := link
+	want.Signal, want.Quality, want.TxBitrate = -56, 40, 400
+	if got := w.smooth(next); got != want {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// Another access point resets the smoothing.
+	roam := WirelessLink{Interface: "wlan0", BSSID: "3c:22:fb:65:43:21", Signal: -70, Quality: 40, TxBitrate: 400}
+	if got := w.smooth(roam); got != roam {
+		t.Errorf("want %v, got %v", roam, got)
+	}
+}
+
+// countingIPLookupMock counts the lookups.
+type countingIPLookupMock struct{ lookups *int32 }
+
+func (p countingIPLookupMock) Lookup() (PublicIP, error) {
+	atomic.AddInt32(p.lookups, 1)
+	return PublicIP{}, nil
+}
+
+func TestNetworkUpdateVPN(t *testing.T) {
+	var lookups int32
+	s := NewNetworkService(nil, countingIPLookupMock{&lookups}, nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
+	time.Sleep(10 * time.Millisecond)
+
+	// The first lookup doesn't trigger a public IP lookup.
+	s.updateVPN(VPN{})
+	time.Sleep(10 * time.Millisecond)
+	if got := atomic.LoadInt32(&lookups); got != 1 {
+		t.Errorf("want %v, got %v", 1, got)
+	}
+
+	s.updateVPN(VPN{Name: "WireGuard", Interface: "wg0", DefaultRoute: true})
+	time.Sleep(10 * time.Millisecond)
+	if got := atomic.LoadInt32(&lookups); got != 2 {
+		t.Errorf("want %v, got %v", 2, got)
+	}
+
+	since := s.VPN().Since
+	if since.IsZero() {
+		t.Errorf("want %v, got %v", "the time it was first seen", since)
+	}
+
+	// The same VPN keeps the time it was first seen.
+	s.updateVPN(VPN{Name: "WireGuard", Interface: "wg0", DefaultRoute: true, Endpoint: "198.51.100.1:51820"})
+	time.Sleep(10 * time.Millisecond)
+	if got := s.VPN(); got.Since != since || got.Endpoint != "198.51.100.1:51820" {
+		t.Errorf("want %v, got %v", since, got.Since)
+	}
+	if got := atomic.LoadInt32(&lookups); got != 2 {
+		t.Errorf("want %v, got %v", 2, got)
+	}
+
+	s.updateVPN(VPN{})
+	time.Sleep(10 * time.Millisecond)
+	if got := s.VPN(); got.Active() || atomic.LoadInt32(&lookups) != 3 {
+		t.Errorf("want %v, got %v", VPN{}, got)
+	}
+}
+
+type networkEventSourceMock struct {
+	events chan struct{}
+	err    error
+}
+
+func (e networkEventSourceMock) Events() (<-chan struct{}, error) {
+	return e.events, e.err
+}
+
+func TestNetworkWatch(t *testing.T) {
+	var lookups int32
+	s := NewNetworkService(nil, countingIPLookupMock{&lookups}, nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
+	time.Sleep(10 * time.Millisecond)
+
+	events := make(chan struct{})
+	s.Watch(networkEventSourceMock{events: events})
+	events <- struct{}{}
+	events <- struct{}{}
+	time.Sleep(networkEventDelay + 100*time.Millisecond)
+	close(events)
+
+	if got := atomic.LoadInt32(&lookups); got != 2 {
+		t.Errorf("want %v, got %v", 2, got)
+	}
+
+	// The workers keep polling without events.
+	s.Watch(nil)
+	s.Watch(networkEventSourceMock{err: errors.New("error")})
+}
+
+type ipEnricherMock struct {
+	enriched *int32
+	err      error
+}
+
+func (e ipEnricherMock) Enrich(ip net.IP) (IPDetails, error) {
+	atomic.AddInt32(e.enriched, 1)
+	return IPDetails{CountryCode: "NL", ASN: 64500}, e.err
+}
+
+type staticIPLookupMock struct{ ip *PublicIP }
+
+func (p staticIPLookupMock) Lookup() (PublicIP, error) {
+	return *p.ip, nil
+}
+
+func TestEnrichedIPLookup(t *testing.T) {
+	var enriched int32
+	ip := &PublicIP{IPv4: net.ParseIP("203.0.113.7")}
+	l := NewEnrichedIPLookup(staticIPLookupMock{ip}, ipEnricherMock{enriched: &enriched})
+
+	want := IPDetails{CountryCode: "NL", ASN: 64500}
+	for i := 0; i < 2; i++ {
+		got, err := l.Lookup()
+		if err != nil || got.Details != want {
+			t.Errorf("want %v, got %v, %v", want, got.Details, err)
+		}
+	}
+
+	// The details are cached until the IP address changes.
+	if got := atomic.LoadInt32(&enriched); got != 1 {
+		t.Errorf("want %v, got %v", 1, got)
+	}
+
+	ip.IPv4 = nil
+	ip.IPv6 = net.ParseIP("2001:db8::7")
+	if _, err := l.Lookup(); err != nil || atomic.LoadInt32(&enriched) != 2 {
+		t.Errorf("want %v, got %v, %v", 2, atomic.LoadInt32(&enriched), err)
+	}
+}
+
+func TestEnrichedIPLookupError(t *testing.T) {
+	var enriched int32
+	ip := &PublicIP{IPv4: net.ParseIP("203.0.113.7")}
+	l := NewEnrichedIPLookup(staticIPLookupMock{ip}, ipEnricherMock{enriched: &enriched, err: errors.New("error")})
+
+	got, err := l.Lookup()
+	if !IsPartial(err) || got.IPv4 == nil {
+		t.Errorf("want %v, got %v, %v", "partial result", got, err)
+	}
+
+	p := staticIPLookupMock{ip}
+	if got := NewEnrichedIPLookup(p, nil); got != p {
+		t.Errorf("want %v, got %v", p, got)
+	}
+}
@@ -4,10 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"sync"
 	"time"
 )
 
+// ErrLocked is returned by a tin.PackageManager when the package database is
+// locked by another process, for example during an upgrade.
+var ErrLocked = errors.New("package database is locked")
+
 // PackageManager is the interface implemented by an object that can
 // fetch info about system packages.
 //
@@ -18,6 +23,52 @@ type PackageManager interface {
 	Installed() ([]Package, error)
 }
 
+// UpdateClassifier is the interface implemented by a tin.PackageManager that can
+// classify the available updates.
+//
+// ClassifiedUpdates returns the updateable packages with their tin.UpdateKind.
+type UpdateClassifier interface {
+	ClassifiedUpdates() ([]Update, error)
+}
+
+// OrphanLister is the interface implemented by a tin.PackageManager that can
+// list the packages that were installed as a dependency but are no longer required.
+type OrphanLister interface {
+	Orphans() ([]Package, error)
+}
+
+// ForeignLister is the interface implemented by a tin.PackageManager that can
+// list the packages that are not available in the sync repositories.
+type ForeignLister interface {
+	Foreign() ([]Package, error)
+}
+
+// CacheSizer is the interface implemented by a tin.PackageManager that can
+// return the size of the package cache in bytes.
+type CacheSizer interface {
+	CacheSize() (int64, error)
+}
+
+// PackageDetailer is the interface implemented by a tin.PackageManager that can
+// return the details of installed packages.
+//
+// Details returns the details of the given installed packages.
+type PackageDetailer interface {
+	Details(names ...string) ([]PackageDetails, error)
+}
+
+// PackageHistoryReader is the interface implemented by an object that can
+// read the package history from the logs of a package manager.
+type PackageHistoryReader interface {
+	History() (PackageHistory, error)
+}
+
+// RebootChecker is the interface implemented by an object that can
+// check whether the system has to be rebooted after upgrades.
+type RebootChecker interface {
+	Check() (RebootRequired, error)
+}
+
 // PackageManagerServiceState represents the state.
 type PackageManagerServiceState struct {
 	sync.RWMutex
@@ -46,8 +97,12 @@ func (a Packages) Equal(t interface{}) bool {
 type Package struct {
 	Name    string
 	Version string
+	Source  string // Source of a package that isn't from the official repositories.
 }
 
+// PackageSourceAUR is the tin.Package source of packages from the AUR.
+const PackageSourceAUR = "aur"
+
 // Equal implements tin.Comparable.
 func (a Package) Equal(t interface{}) bool {
 	if b, ok := t.(Package); ok {
@@ -56,6 +111,253 @@ func (a Package) Equal(t interface{}) bool {
 	return false
 }
 
+// Diff returns the tin.PackageChanges needed to go from a to b.
+//
+// Packages are matched by name, a package that exists in both with a
+// different version is reported as upgraded. The results are sorted by name.
+func (a Packages) Diff(b Packages) PackageChanges {
+	old := make(map[string]Package, len(a))
+	for _, p := range a {
+		old[p.Name] = p
+	}
+
+	c := PackageChanges{}
+	for _, p := range b {
+		o, exists := old[p.Name]
+		if !exists {
+			c.Added = append(c.Added, p)
+			continue
+		}
+
+		if o.Version != p.Version {
+			c.Upgraded = append(c.Upgraded, PackageUpgrade{Name: p.Name, From: o.Version, To: p.Version})
+		}
+		delete(old, p.Name)
+	}
+
+	for _, p := range old {
+		c.Removed = append(c.Removed, p)
+	}
+
+	sort.Slice(c.Added, func(i, j int) bool { return c.Added[i].Name < c.Added[j].Name })
+	sort.Slice(c.Removed, func(i, j int) bool { return c.Removed[i].Name < c.Removed[j].Name })
+	sort.Slice(c.Upgraded, func(i, j int) bool { return c.Upgraded[i].Name < c.Upgraded[j].Name })
+
+	return c
+}
+
+// PackageUpgrade represents a package of which the version has changed.
+type PackageUpgrade struct {
+	Name string
+	From string
+	To   string
+}
+
+// PackageChanges represents the difference between two snapshots of installed packages.
+type PackageChanges struct {
+	Time     time.Time
+	Added    Packages
+	Removed  Packages
+	Upgraded []PackageUpgrade
+}
+
+// Empty returns true when no package has been added, removed or upgraded.
+func (a PackageChanges) Empty() bool {
+	return len(a.Added) == 0 && len(a.Removed) == 0 && len(a.Upgraded) == 0
+}
+
+// Equal implements tin.Comparable.
+func (a PackageChanges) Equal(t interface{}) bool {
+	b, ok := t.(PackageChanges)
+	if !ok || !a.Time.Equal(b.Time) || !a.Added.Equal(b.Added) || !a.Removed.Equal(b.Removed) {
+		return false
+	}
+
+	if len(a.Upgraded) != len(b.Upgraded) {
+		return false
+	}
+
+	for i, v := range a.Upgraded {
+		if v != b.Upgraded[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// PackageAction represents an action of a package manager on a package.
+type PackageAction string
+
+// Represents a tin.PackageAction.
+const (
+	PackageInstalled  PackageAction = "installed"
+	PackageUpgraded   PackageAction = "upgraded"
+	PackageDowngraded PackageAction = "downgraded"
+	PackageRemoved    PackageAction = "removed"
+)
+
+// PackageEvent represents an action on a package at a point in time.
+//
+// From is empty for installed packages and To is empty for removed packages.
+type PackageEvent struct {
+	Time   time.Time
+	Action PackageAction
+	Name   string
+	From   string
+	To     string
+}
+
+// PackageHistory represents the package events and the moments
+// a full system upgrade has been started, both in chronological order.
+type PackageHistory struct {
+	Events       []PackageEvent
+	FullUpgrades []time.Time
+}
+
+// Filter returns the events between from and to for the given package name.
+//
+// A zero from or to means the range is unbounded on that side and an empty
+// name matches every package.
+func (h PackageHistory) Filter(from time.Time, to time.Time, name string) []PackageEvent {
+	ee := []PackageEvent{}
+	for _, e := range h.Events {
+		if !from.IsZero() && e.Time.Before(from) {
+			continue
+		}
+		if !to.IsZero() && e.Time.After(to) {
+			continue
+		}
+		if name != "" && e.Name != name {
+			continue
+		}
+		ee = append(ee, e)
+	}
+	return ee
+}
+
+// LastFullUpgrade returns the moment of the last full system upgrade.
+//
+// It returns a zero tin.FullUpgrade if there is none.
+func (h PackageHistory) LastFullUpgrade() FullUpgrade {
+	if len(h.FullUpgrades) == 0 {
+		return FullUpgrade{}
+	}
+	return FullUpgrade{Time: h.FullUpgrades[len(h.FullUpgrades)-1]}
+}
+
+// FullUpgrade represents the moment a full system upgrade has been started.
+type FullUpgrade struct {
+	Time time.Time
+}
+
+// Equal implements tin.Comparable.
+func (a FullUpgrade) Equal(t interface{}) bool {
+	if b, ok := t.(FullUpgrade); ok {
+		return a.Time.Equal(b.Time)
+	}
+	return false
+}
+
+// RebootRequired represents whether a reboot is required and why.
+type RebootRequired struct {
+	Required bool
+	Reasons  []string
+}
+
+// Equal implements tin.Comparable.
+func (a RebootRequired) Equal(t interface{}) bool {
+	b, ok := t.(RebootRequired)
+	if !ok || a.Required != b.Required || len(a.Reasons) != len(b.Reasons) {
+		return false
+	}
+
+	for i, v := range a.Reasons {
+		if v != b.Reasons[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// UpdateKind represents the kind of an update.
+type UpdateKind string
+
+// Represents a tin.UpdateKind.
+const (
+	UpdateSecurity     UpdateKind = "security"
+	UpdateBugfix       UpdateKind = "bugfix"
+	UpdateEnhancement  UpdateKind = "enhancement"
+	UpdateUnclassified UpdateKind = "unclassified"
+)
+
+// Update represents an available update of a package.
+//
+// From is empty when the package manager doesn't report the installed version.
+type Update struct {
+	Name string
+	From string
+	To   string
+	Kind UpdateKind
+}
+
+// Updates represents a slice of tin.Update.
+type Updates []Update
+
+// Equal implements tin.Comparable.
+func (a Updates) Equal(t interface{}) bool {
+	b, ok := t.(Updates)
+	if !ok || len(a) != len(b) {
+		return false
+	}
+
+	for i, v := range a {
+		if v != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns the amount of updates of the given kind.
+func (a Updates) Count(k UpdateKind) PackageCount {
+	c := 0
+	for _, u := range a {
+		if u.Kind == k {
+			c++
+		}
+	}
+	return PackageCount(c)
+}
+
+// Maintenance represents the maintenance summary of a package manager.
+//
+// The fields of capabilities the package manager doesn't support are empty.
+type Maintenance struct {
+	Orphans   Packages
+	Foreign   Packages
+	CacheSize int64
+}
+
+// Equal implements tin.Comparable.
+func (a Maintenance) Equal(t interface{}) bool {
+	b, ok := t.(Maintenance)
+	return ok && a.Orphans.Equal(b.Orphans) && a.Foreign.Equal(b.Foreign) && a.CacheSize == b.CacheSize
+}
+
+// PackageDetails represents the details of an installed package.
+//
+// Explicit is false when the package has been installed as a dependency.
+// Dependencies and RequiredBy contain the direct (reverse) dependencies.
+type PackageDetails struct {
+	Package
+	Description   string
+	InstalledSize int64
+	InstallDate   time.Time
+	Explicit      bool
+	Dependencies  []string
+	RequiredBy    []string
+}
+
 // PackageCount represents the amount of packages.
 type PackageCount int
 
@@ -69,22 +371,36 @@ func (a PackageCount) Equal(t interface{}) bool {
 
 // Represents a tin.StateKey.
 const (
-	AvailableUpdates StateKey = "AvailableUpdates"
-	Installed                 = "Installed"
+	AvailableUpdates   StateKey = "AvailableUpdates"
+	SecurityUpdates             = "SecurityUpdates"
+	ClassifiedUpdates           = "ClassifiedUpdates"
+	Installed                   = "Installed"
+	InstalledChanges            = "InstalledChanges"
+	LastFullUpgrade             = "LastFullUpgrade"
+	Reboot                      = "RebootRequired"
+	PackageMaintenance          = "Maintenance"
 )
 
 // PackageManagerService provides access to data from package managers.
 type PackageManagerService struct {
-	manager PackageManager
-	state   *State
-	worker  *Worker
-	logger  *log.Logger
+	manager           PackageManager
+	history           PackageHistoryReader
+	reboot            RebootChecker
+	state             *State
+	worker            *Worker
+	installedWorker   *Worker
+	historyWorker     *Worker
+	rebootWorker      *Worker
+	maintenanceWorker *Worker
+	logger            *log.Logger
 }
 
 // NewPackageManagerService returns a tin.PackageManagerService.
-func NewPackageManagerService(m PackageManager, l *log.Logger) *PackageManagerService {
+func NewPackageManagerService(m PackageManager, h PackageHistoryReader, r RebootChecker, l *log.Logger) *PackageManagerService {
 	s := &PackageManagerService{
 		manager: m,
+		history: h,
+		reboot:  r,
 		state:   NewState(),
 		logger:  l,
 	}
@@ -94,29 +410,69 @@ func NewPackageManagerService(m PackageManager, l *log.Logger) *PackageManagerSe
 		s.logger.Println(errors.New("failed initializing worker"))
 	} else {
 		s.worker = NewWorker(time.Minute, func() {
+			if c, ok := s.manager.(UpdateClassifier); ok {
+				updates, err := c.ClassifiedUpdates()
+				if usable(err, s.logger) {
+					s.SetClassifiedUpdates(Updates(updates))
+				}
+				return
+			}
+
 			packages, err := s.manager.AvailableUpdates()
-			if err != nil {
-				s.logger.Println(fmt.Errorf("worker failed: %w", err))
-			} else {
+			if usable(err, s.logger) {
 				s.SetAvailableUpdates(PackageCount(len(packages)))
 			}
 
-		})
+		}, s.logger)
 	}
 
-	// Worker that fetches available package updates on intervals and updates the state.
+	// Worker that fetches the installed packages on intervals and updates the state.
 	if m == nil {
-		s.logger.Println(errors.New("failed initializing worker"))
+		s.logger.Println(errors.New("failed initializing installed worker"))
 	} else {
-		s.worker = NewWorker(time.Minute, func() {
+		s.installedWorker = NewWorker(time.Minute, func() {
 			packages, err := s.manager.Installed()
-			if err != nil {
-				s.logger.Println(fmt.Errorf("worker failed: %w", err))
-			} else {
+			if usable(err, s.logger) {
 				s.SetInstalled(Packages(packages))
 			}
 
-		})
+		}, s.logger)
+	}
+
+	// Worker that reads the package history on intervals and updates the state.
+	if h == nil {
+		s.logger.Println(errors.New("failed initializing history worker"))
+	} else {
+		s.historyWorker = NewWorker(5*time.Minute, func() {
+			history, err := s.history.History()
+			if usable(err, s.logger) {
+				s.SetLastFullUpgrade(history.LastFullUpgrade())
+			}
+		}, s.logger)
+	}
+
+	// Worker that checks whether a reboot is required on intervals and updates the state.
+	if r == nil {
+		s.logger.Println(errors.New("failed initializing reboot worker"))
+	} else {
+		s.rebootWorker = NewWorker(5*time.Minute, func() {
+			reboot, err := s.reboot.Check()
+			if usable(err, s.logger) {
+				s.SetRebootRequired(reboot)
+			}
+		}, s.logger)
+	}
+
+	// Worker that fetches the maintenance summary on intervals and updates the state.
+	if m == nil {
+		s.logger.Println(errors.New("failed initializing maintenance worker"))
+	} else {
+		s.maintenanceWorker = NewWorker(15*time.Minute, func() {
+			maintenance, err := s.maintenance()
+			if usable(err, s.logger) {
+				s.SetMaintenance(maintenance)
+			}
+		}, s.logger)
 	}
 
 	return s
@@ -142,8 +498,49 @@ func (s *PackageManagerService) AvailableUpdatesCount() PackageCount {
 	return v.(PackageCount)
 }
 
+// SetClassifiedUpdates updates the state.
+//
+// The available and security update counts are derived from the updates.
+func (s *PackageManagerService) SetClassifiedUpdates(u Updates) {
+	s.state.Set(ClassifiedUpdates, u)
+	s.state.Set(SecurityUpdates, u.Count(UpdateSecurity))
+	s.SetAvailableUpdates(PackageCount(len(u)))
+}
+
+// ClassifiedUpdates returns tin.Updates.
+//
+// It is empty when the package manager doesn't implement tin.UpdateClassifier.
+func (s *PackageManagerService) ClassifiedUpdates() Updates {
+	v, err := s.state.Get(ClassifiedUpdates)
+	if err != nil {
+		return Updates{}
+	}
+
+	return v.(Updates)
+}
+
+// SecurityUpdatesCount returns a tin.PackageCount.
+func (s *PackageManagerService) SecurityUpdatesCount() PackageCount {
+	v, err := s.state.Get(SecurityUpdates)
+	if err != nil {
+		return PackageCount(0)
+	}
+
+	return v.(PackageCount)
+}
+
 // SetInstalled updates the state.
+//
+// The changes compared to the previous installed packages are stored as well,
+// the first call only sets the installed packages.
 func (s *PackageManagerService) SetInstalled(p Packages) {
+	if v, err := s.state.Get(Installed); err == nil {
+		if c := v.(Packages).Diff(p); !c.Empty() {
+			c.Time = time.Now()
+			s.state.Set(InstalledChanges, c)
+		}
+	}
+
 	s.state.Set(Installed, p)
 }
 
@@ -156,3 +553,156 @@ func (s *PackageManagerService) Installed() Packages {
 
 	return v.(Packages)
 }
+
+// InstalledChanges returns the last tin.PackageChanges.
+func (s *PackageManagerService) InstalledChanges() PackageChanges {
+	v, err := s.state.Get(InstalledChanges)
+	if err != nil {
+		return PackageChanges{}
+	}
+
+	return v.(PackageChanges)
+}
+
+// History returns the package events between from and to for the given package name.
+//
+// The logs are read on every call to include the latest events.
+func (s *PackageManagerService) History(from time.Time, to time.Time, name string) ([]PackageEvent, error) {
+	if s.history == nil {
+		return []PackageEvent{}, errors.New("package history is not supported")
+	}
+
+	history, err := s.history.History()
+	if err != nil {
+		return []PackageEvent{}, err
+	}
+
+	return history.Filter(from, to, name), nil
+}
+
+// SetLastFullUpgrade updates the state.
+func (s *PackageManagerService) SetLastFullUpgrade(u FullUpgrade) {
+	s.state.Set(LastFullUpgrade, u)
+}
+
+// LastFullUpgrade returns a tin.FullUpgrade.
+func (s *PackageManagerService) LastFullUpgrade() FullUpgrade {
+	v, err := s.state.Get(LastFullUpgrade)
+	if err != nil {
+		return FullUpgrade{}
+	}
+
+	return v.(FullUpgrade)
+}
+
+// SetRebootRequired updates the state.
+func (s *PackageManagerService) SetRebootRequired(r RebootRequired) {
+	s.state.Set(Reboot, r)
+}
+
+// RebootRequired returns a tin.RebootRequired.
+func (s *PackageManagerService) RebootRequired() RebootRequired {
+	v, err := s.state.Get(Reboot)
+	if err != nil {
+		return RebootRequired{}
+	}
+
+	return v.(RebootRequired)
+}
+
+// SetMaintenance updates the state.
+func (s *PackageManagerService) SetMaintenance(m Maintenance) {
+	s.state.Set(PackageMaintenance, m)
+}
+
+// Maintenance returns a tin.Maintenance.
+func (s *PackageManagerService) Maintenance() Maintenance {
+	v, err := s.state.Get(PackageMaintenance)
+	if err != nil {
+		return Maintenance{Orphans: Packages{}, Foreign: Packages{}}
+	}
+
+	return v.(Maintenance)
+}
+
+// maintenance returns a tin.Maintenance using the capabilities of the package manager.
+//
+// A capability that fails keeps its previous value and is returned as a
+// tin.Warnings, so the others are still published.
+func (s *PackageManagerService) maintenance() (Maintenance, error) {
+	m := s.Maintenance()
+	warnings := Warnings{}
+
+	if l, ok := s.manager.(OrphanLister); ok {
+		if orphans, err := l.Orphans(); err != nil {
+			warnings = warnings.Append(fmt.Errorf("failed listing orphans: %w", err))
+		} else {
+			m.Orphans = orphans
+		}
+	}
+
+	if l, ok := s.manager.(ForeignLister); ok {
+		if foreign, err := l.Foreign(); err != nil {
+			warnings = warnings.Append(fmt.Errorf("failed listing foreign packages: %w", err))
+		} else {
+			m.Foreign = foreign
+		}
+	}
+
+	if c, ok := s.manager.(CacheSizer); ok {
+		if size, err := c.CacheSize(); err != nil {
+			warnings = warnings.Append(fmt.Errorf("failed getting the cache size: %w", err))
+		} else {
+			m.CacheSize = size
+		}
+	}
+
+	return m, warnings.Err()
+}
+
+// PackageDetails returns the tin.PackageDetails of an installed package.
+func (s *PackageManagerService) PackageDetails(name string) (PackageDetails, error) {
+	d, ok := s.manager.(PackageDetailer)
+	if !ok {
+		return PackageDetails{}, errors.New("package details are not supported")
+	}
+
+	details, err := d.Details(name)
+	if err != nil {
+		return PackageDetails{}, err
+	}
+
+	if len(details) == 0 {
+		return PackageDetails{}, fmt.Errorf("package %v is not installed", name)
+	}
+
+	return details[0], nil
+}
+
+// InstalledBySize returns the details of the installed packages sorted by
+// installed size, largest first.
+func (s *PackageManagerService) InstalledBySize() ([]PackageDetails, error) {
+	d, ok := s.manager.(PackageDetailer)
+	if !ok {
+		return []PackageDetails{}, errors.New("package details are not supported")
+	}
+
+	installed := s.Installed()
+	if len(installed) == 0 {
+		return []PackageDetails{}, nil
+	}
+
+	names := []string{}
+	for _, p := range installed {
+		names = append(names, p.Name)
+	}
+
+	details, err := d.Details(names...)
+	if err != nil {
+		return []PackageDetails{}, err
+	}
+
+	sort.SliceStable(details, func(i, j int) bool { return details[i].InstalledSize > details[j].InstalledSize })
+
+	return details, nil
+}
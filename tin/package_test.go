@@ -7,6 +7,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 )
 
 type packageManagerMock struct {
@@ -29,6 +30,37 @@ func (p packageManagerMock) Installed() ([]Package, error) {
 	return make([]Package, 1), nil
 }
 
+type packageHistoryReaderMock struct {
+	returnError bool
+}
+
+func (p packageHistoryReaderMock) History() (PackageHistory, error) {
+	if p.returnError {
+		return PackageHistory{}, errors.New("error")
+	}
+
+	return PackageHistory{
+		Events: []PackageEvent{
+			{Time: time.Unix(100, 0), Action: PackageInstalled, Name: "package", To: "1.0.0"},
+			{Time: time.Unix(200, 0), Action: PackageUpgraded, Name: "package", From: "1.0.0", To: "1.0.1"},
+			{Time: time.Unix(300, 0), Action: PackageInstalled, Name: "other", To: "2.0.0"},
+		},
+		FullUpgrades: []time.Time{time.Unix(150, 0), time.Unix(250, 0)},
+	}, nil
+}
+
+type rebootCheckerMock struct {
+	returnError bool
+}
+
+func (r rebootCheckerMock) Check() (RebootRequired, error) {
+	if r.returnError {
+		return RebootRequired{}, errors.New("error")
+	}
+
+	return RebootRequired{Required: true, Reasons: []string{"reason"}}, nil
+}
+
 func TestNewPackageManagerService(t *testing.T) {
 	tt := []struct {
 		want *PackageManagerService
@@ -36,15 +68,23 @@ func TestNewPackageManagerService(t *testing.T) {
 	}{
 		{
 			want: &PackageManagerService{},
-			got:  NewPackageManagerService(packageManagerMock{returnError: false}, log.New(os.Stdout, "", log.Flags())),
+			got:  NewPackageManagerService(packageManagerMock{returnError: false}, nil, nil, log.New(os.Stdout, "", log.Flags())),
+		},
+		{
+			want: &PackageManagerService{},
+			got:  NewPackageManagerService(packageManagerMock{returnError: true}, nil, nil, log.New(os.Stdout, "", log.Flags())),
 		},
 		{
 			want: &PackageManagerService{},
-			got:  NewPackageManagerService(packageManagerMock{returnError: true}, log.New(os.Stdout, "", log.Flags())),
+			got:  NewPackageManagerService(nil, nil, nil, log.New(os.Stdout, "", log.Flags())),
 		},
 		{
 			want: &PackageManagerService{},
-			got:  NewPackageManagerService(nil, log.New(os.Stdout, "", log.Flags())),
+			got:  NewPackageManagerService(nil, packageHistoryReaderMock{returnError: true}, nil, log.New(os.Stdout, "", log.Flags())),
+		},
+		{
+			want: &PackageManagerService{},
+			got:  NewPackageManagerService(nil, nil, rebootCheckerMock{returnError: true}, log.New(os.Stdout, "", log.Flags())),
 		},
 	}
 
@@ -56,7 +96,7 @@ func TestNewPackageManagerService(t *testing.T) {
 }
 
 func TestPackageSubscribe(t *testing.T) {
-	s := NewPackageManagerService(packageManagerMock{}, log.New(os.Stdout, "", log.Flags()))
+	s := NewPackageManagerService(packageManagerMock{}, nil, nil, log.New(os.Stdout, "", log.Flags()))
 	want := StateSubscription{}
 	got := s.Subscribe()
 
@@ -66,7 +106,7 @@ func TestPackageSubscribe(t *testing.T) {
 }
 
 func TestPackageAvailableUpdatesCount(t *testing.T) {
-	withState := NewPackageManagerService(nil, log.New(ioutil.Discard, "", log.Flags()))
+	withState := NewPackageManagerService(nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
 	withState.SetAvailableUpdates(PackageCount(7))
 
 	tt := []struct {
@@ -78,7 +118,7 @@ func TestPackageAvailableUpdatesCount(t *testing.T) {
 			want:    PackageCount(7),
 		},
 		{
-			service: NewPackageManagerService(nil, log.New(ioutil.Discard, "", log.Flags())),
+			service: NewPackageManagerService(nil, nil, nil, log.New(ioutil.Discard, "", log.Flags())),
 			want:    PackageCount(0),
 		},
 	}
@@ -222,7 +262,7 @@ func TestPackageCountEqualFalse(t *testing.T) {
 }
 
 func TestPackageInstalled(t *testing.T) {
-	withState := NewPackageManagerService(nil, log.New(ioutil.Discard, "", log.Flags()))
+	withState := NewPackageManagerService(nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
 	withState.SetInstalled([]Package{{Name: "package", Version: "1.0.0"}})
 
 	tt := []struct {
@@ -234,7 +274,7 @@ func TestPackageInstalled(t *testing.T) {
 			want:    1,
 		},
 		{
-			service: NewPackageManagerService(nil, log.New(ioutil.Discard, "", log.Flags())),
+			service: NewPackageManagerService(nil, nil, nil, log.New(ioutil.Discard, "", log.Flags())),
 			want:    0,
 		},
 	}
@@ -247,3 +287,468 @@ func TestPackageInstalled(t *testing.T) {
 		}
 	}
 }
+
+func TestPackagesDiff(t *testing.T) {
+	a := Packages{
+		Package{Name: "kept", Version: "1.0.0"},
+		Package{Name: "removed", Version: "1.0.0"},
+		Package{Name: "upgraded", Version: "1.0.0"},
+	}
+	b := Packages{
+		Package{Name: "upgraded", Version: "1.0.1"},
+		Package{Name: "kept", Version: "1.0.0"},
+		Package{Name: "added", Version: "2.0.0"},
+	}
+
+	want := PackageChanges{
+		Added:    Packages{Package{Name: "added", Version: "2.0.0"}},
+		Removed:  Packages{Package{Name: "removed", Version: "1.0.0"}},
+		Upgraded: []PackageUpgrade{{Name: "upgraded", From: "1.0.0", To: "1.0.1"}},
+	}
+	got := a.Diff(b)
+	if !got.Equal(want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestPackagesDiffEmpty(t *testing.T) {
+	a := Packages{Package{Name: "Name", Version: "1.0.0"}}
+
+	want := true
+	got := a.Diff(a).Empty()
+	if got != want {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestPackageChangesEqualFalse(t *testing.T) {
+	tt := []struct {
+		a PackageChanges
+		b interface{}
+	}{
+		{
+			a: PackageChanges{Added: Packages{Package{Name: "Name", Version: "1.0.0"}}},
+			b: PackageChanges{Removed: Packages{Package{Name: "Name", Version: "1.0.0"}}},
+		},
+		{
+			a: PackageChanges{Upgraded: []PackageUpgrade{{Name: "Name", From: "1.0.0", To: "1.0.1"}}},
+			b: PackageChanges{Upgraded: []PackageUpgrade{{Name: "Name", From: "1.0.0", To: "1.0.2"}}},
+		},
+		{
+			a: PackageChanges{Time: time.Unix(1, 0)},
+			b: PackageChanges{Time: time.Unix(2, 0)},
+		},
+		{
+			a: PackageChanges{},
+			b: "changes",
+		},
+	}
+
+	for _, tc := range tt {
+		want := false
+		got := tc.a.Equal(tc.b)
+
+		if got != want {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	}
+}
+
+func TestPackageInstalledChanges(t *testing.T) {
+	s := NewPackageManagerService(nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
+	s.SetInstalled(Packages{Package{Name: "package", Version: "1.0.0"}})
+
+	want := true
+	got := s.InstalledChanges().Empty()
+	if got != want {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	s.SetInstalled(Packages{Package{Name: "package", Version: "1.0.1"}})
+
+	wantUpgraded := []PackageUpgrade{{Name: "package", From: "1.0.0", To: "1.0.1"}}
+	gotUpgraded := s.InstalledChanges().Upgraded
+	if !reflect.DeepEqual(gotUpgraded, wantUpgraded) {
+		t.Errorf("want %v, got %v", wantUpgraded, gotUpgraded)
+	}
+}
+
+func TestPackageHistory(t *testing.T) {
+	tt := []struct {
+		from time.Time
+		to   time.Time
+		name string
+		want int
+	}{
+		{want: 3},
+		{from: time.Unix(200, 0), want: 2},
+		{to: time.Unix(200, 0), want: 2},
+		{from: time.Unix(150, 0), to: time.Unix(250, 0), want: 1},
+		{name: "package", want: 2},
+		{name: "missing", want: 0},
+	}
+
+	s := NewPackageManagerService(nil, packageHistoryReaderMock{}, nil, log.New(ioutil.Discard, "", log.Flags()))
+	for _, tc := range tt {
+		events, err := s.History(tc.from, tc.to, tc.name)
+		if err != nil {
+			t.Errorf("want %v, got %v", nil, err)
+		}
+
+		if got := len(events); got != tc.want {
+			t.Errorf("want %v, got %v", tc.want, got)
+		}
+	}
+}
+
+func TestPackageHistoryError(t *testing.T) {
+	tt := []*PackageManagerService{
+		NewPackageManagerService(nil, nil, nil, log.New(ioutil.Discard, "", log.Flags())),
+		NewPackageManagerService(nil, packageHistoryReaderMock{returnError: true}, nil, log.New(ioutil.Discard, "", log.Flags())),
+	}
+
+	for _, s := range tt {
+		_, got := s.History(time.Time{}, time.Time{}, "")
+		if got == nil {
+			t.Errorf("want %v, got %v", "error", got)
+		}
+	}
+}
+
+func TestPackageLastFullUpgrade(t *testing.T) {
+	withState := NewPackageManagerService(nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
+	withState.SetLastFullUpgrade(FullUpgrade{Time: time.Unix(250, 0)})
+
+	tt := []struct {
+		service *PackageManagerService
+		want    FullUpgrade
+	}{
+		{
+			service: withState,
+			want:    FullUpgrade{Time: time.Unix(250, 0)},
+		},
+		{
+			service: NewPackageManagerService(nil, nil, nil, log.New(ioutil.Discard, "", log.Flags())),
+			want:    FullUpgrade{},
+		},
+	}
+
+	for _, tc := range tt {
+		got := tc.service.LastFullUpgrade()
+
+		if !got.Equal(tc.want) {
+			t.Errorf("want %v, got %v", tc.want, got)
+		}
+	}
+}
+
+func TestPackageHistoryLastFullUpgrade(t *testing.T) {
+	h, _ := packageHistoryReaderMock{}.History()
+
+	want := FullUpgrade{Time: time.Unix(250, 0)}
+	got := h.LastFullUpgrade()
+	if !got.Equal(want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	want = FullUpgrade{}
+	got = PackageHistory{}.LastFullUpgrade()
+	if !got.Equal(want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestFullUpgradeEqualFalse(t *testing.T) {
+	tt := []struct {
+		a FullUpgrade
+		b interface{}
+	}{
+		{
+			a: FullUpgrade{Time: time.Unix(1, 0)},
+			b: FullUpgrade{Time: time.Unix(2, 0)},
+		},
+		{
+			a: FullUpgrade{Time: time.Unix(1, 0)},
+			b: time.Unix(1, 0),
+		},
+	}
+
+	for _, tc := range tt {
+		want := false
+		got := tc.a.Equal(tc.b)
+
+		if got != want {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	}
+}
+
+func TestPackageRebootRequired(t *testing.T) {
+	withState := NewPackageManagerService(nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
+	withState.SetRebootRequired(RebootRequired{Required: true, Reasons: []string{"reason"}})
+
+	tt := []struct {
+		service *PackageManagerService
+		want    RebootRequired
+	}{
+		{
+			service: withState,
+			want:    RebootRequired{Required: true, Reasons: []string{"reason"}},
+		},
+		{
+			service: NewPackageManagerService(nil, nil, nil, log.New(ioutil.Discard, "", log.Flags())),
+			want:    RebootRequired{},
+		},
+	}
+
+	for _, tc := range tt {
+		got := tc.service.RebootRequired()
+
+		if !got.Equal(tc.want) {
+			t.Errorf("want %v, got %v", tc.want, got)
+		}
+	}
+}
+
+func TestRebootRequiredEqualFalse(t *testing.T) {
+	tt := []struct {
+		a RebootRequired
+		b interface{}
+	}{
+		{
+			a: RebootRequired{Required: true},
+			b: RebootRequired{Required: false},
+		},
+		{
+			a: RebootRequired{Required: true, Reasons: []string{"a"}},
+			b: RebootRequired{Required: true, Reasons: []string{"b"}},
+		},
+		{
+			a: RebootRequired{Required: true, Reasons: []string{"a"}},
+			b: RebootRequired{Required: true, Reasons: []string{"a", "b"}},
+		},
+		{
+			a: RebootRequired{},
+			b: false,
+		},
+	}
+
+	for _, tc := range tt {
+		want := false
+		got := tc.a.Equal(tc.b)
+
+		if got != want {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	}
+}
+
+func TestPackageSetClassifiedUpdates(t *testing.T) {
+	s := NewPackageManagerService(nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
+	s.SetClassifiedUpdates(Updates{
+		{Name: "openssl", Kind: UpdateSecurity},
+		{Name: "kernel", Kind: UpdateBugfix},
+		{Name: "htop", Kind: UpdateUnclassified},
+	})
+
+	tt := []struct {
+		want PackageCount
+		got  PackageCount
+	}{
+		{want: PackageCount(3), got: s.AvailableUpdatesCount()},
+		{want: PackageCount(1), got: s.SecurityUpdatesCount()},
+		{want: PackageCount(1), got: s.ClassifiedUpdates().Count(UpdateBugfix)},
+		{want: PackageCount(0), got: s.ClassifiedUpdates().Count(UpdateEnhancement)},
+	}
+
+	for _, tc := range tt {
+		if tc.got != tc.want {
+			t.Errorf("want %v, got %v", tc.want, tc.got)
+		}
+	}
+}
+
+func TestPackageSecurityUpdatesCount(t *testing.T) {
+	s := NewPackageManagerService(nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
+
+	want := PackageCount(0)
+	got := s.SecurityUpdatesCount()
+	if got != want {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestUpdatesEqualFalse(t *testing.T) {
+	tt := []struct {
+		a Updates
+		b interface{}
+	}{
+		{
+			a: Updates{{Name: "Name", Kind: UpdateSecurity}},
+			b: Updates{{Name: "Name", Kind: UpdateBugfix}},
+		},
+		{
+			a: Updates{{Name: "Name"}},
+			b: Updates{{Name: "Name"}, {Name: "Other"}},
+		},
+		{
+			a: Updates{},
+			b: Packages{},
+		},
+	}
+
+	for _, tc := range tt {
+		want := false
+		got := tc.a.Equal(tc.b)
+
+		if got != want {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	}
+}
+
+type maintenanceManagerMock struct {
+	packageManagerMock
+}
+
+func (m maintenanceManagerMock) Orphans() ([]Package, error) {
+	if m.returnError {
+		return []Package{}, errors.New("error")
+	}
+
+	return []Package{{Name: "orphan", Version: "1.0.0"}}, nil
+}
+
+func (m maintenanceManagerMock) CacheSize() (int64, error) {
+	return 1024, nil
+}
+
+func TestPackageMaintenance(t *testing.T) {
+	s := NewPackageManagerService(nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
+
+	want := Maintenance{Orphans: Packages{}, Foreign: Packages{}}
+	got := s.Maintenance()
+	if !got.Equal(want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	s.manager = maintenanceManagerMock{}
+	m, err := s.maintenance()
+	if err != nil {
+		t.Errorf("want %v, got %v", nil, err)
+	}
+	s.SetMaintenance(m)
+
+	want = Maintenance{Orphans: Packages{{Name: "orphan", Version: "1.0.0"}}, Foreign: Packages{}, CacheSize: 1024}
+	got = s.Maintenance()
+	if !got.Equal(want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// The orphans of the previous summary are kept, the cache size is still updated.
+	s.manager = maintenanceManagerMock{packageManagerMock{returnError: true}}
+	m, err = s.maintenance()
+	if !IsPartial(err) {
+		t.Errorf("want %v, got %v", "warnings", err)
+	}
+	if !m.Equal(want) {
+		t.Errorf("want %v, got %v", want, m)
+	}
+}
+
+func TestMaintenanceEqualFalse(t *testing.T) {
+	tt := []struct {
+		a Maintenance
+		b interface{}
+	}{
+		{
+			a: Maintenance{CacheSize: 1},
+			b: Maintenance{CacheSize: 2},
+		},
+		{
+			a: Maintenance{Orphans: Packages{{Name: "a"}}},
+			b: Maintenance{Orphans: Packages{{Name: "b"}}},
+		},
+		{
+			a: Maintenance{Foreign: Packages{{Name: "a"}}},
+			b: Maintenance{},
+		},
+		{
+			a: Maintenance{},
+			b: 0,
+		},
+	}
+
+	for _, tc := range tt {
+		want := false
+		got := tc.a.Equal(tc.b)
+
+		if got != want {
+			t.Errorf("want %v, got %v", want, got)
+		}
+	}
+}
+
+type detailsManagerMock struct {
+	packageManagerMock
+}
+
+func (m detailsManagerMock) Details(names ...string) ([]PackageDetails, error) {
+	if m.returnError {
+		return []PackageDetails{}, errors.New("error")
+	}
+
+	sizes := map[string]int64{"small": 1, "large": 100, "medium": 10}
+	dd := []PackageDetails{}
+	for _, n := range names {
+		if size, ok := sizes[n]; ok {
+			dd = append(dd, PackageDetails{Package: Package{Name: n}, InstalledSize: size})
+		}
+	}
+	return dd, nil
+}
+
+func TestPackageDetails(t *testing.T) {
+	s := NewPackageManagerService(nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
+	if _, err := s.PackageDetails("small"); err == nil {
+		t.Errorf("want %v, got %v", "error", err)
+	}
+
+	s.manager = detailsManagerMock{}
+	got, err := s.PackageDetails("small")
+	if err != nil || got.InstalledSize != 1 {
+		t.Errorf("want %v, got %v, %v", 1, got.InstalledSize, err)
+	}
+
+	if _, err := s.PackageDetails("missing"); err == nil {
+		t.Errorf("want %v, got %v", "error", err)
+	}
+
+	s.manager = detailsManagerMock{packageManagerMock{returnError: true}}
+	if _, err := s.PackageDetails("small"); err == nil {
+		t.Errorf("want %v, got %v", "error", err)
+	}
+}
+
+func TestPackageInstalledBySize(t *testing.T) {
+	s := NewPackageManagerService(nil, nil, nil, log.New(ioutil.Discard, "", log.Flags()))
+	s.SetInstalled(Packages{{Name: "small"}, {Name: "large"}, {Name: "medium"}})
+	if _, err := s.InstalledBySize(); err == nil {
+		t.Errorf("want %v, got %v", "error", err)
+	}
+
+	s.manager = detailsManagerMock{}
+	details, err := s.InstalledBySize()
+	if err != nil {
+		t.Errorf("want %v, got %v", nil, err)
+	}
+
+	want := []string{"large", "medium", "small"}
+	got := []string{}
+	for _, d := range details {
+		got = append(got, d.Name)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
@@ -66,7 +66,7 @@ func NewTemperatureService(r TemperatureReader, l *log.Logger) *TemperatureServi
 			} else {
 				s.SetTemperature(t)
 			}
-		})
+		}, s.logger)
 	}
 
 	return s
@@ -0,0 +1,200 @@
+package tin
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TrafficCounterReader is the interface implemented by an object that can
+// read the traffic counters of the network interfaces.
+type TrafficCounterReader interface {
+	Read() ([]TrafficCounter, error)
+}
+
+// TrafficCounter represents the bytes a network interface has received
+// and transmitted since boot.
+type TrafficCounter struct {
+	Interface string
+	RxBytes   uint64
+	TxBytes   uint64
+}
+
+// InterfaceThroughput represents the traffic of a network interface.
+type InterfaceThroughput struct {
+	Interface string
+	RxRate    float64 // Bytes per second.
+	TxRate    float64 // Bytes per second.
+	RxTotal   uint64  // Bytes since boot.
+	TxTotal   uint64  // Bytes since boot.
+	RxSession uint64  // Bytes since tin started.
+	TxSession uint64  // Bytes since tin started.
+}
+
+// Throughput represents the traffic of the network interfaces, sorted by interface.
+type Throughput []InterfaceThroughput
+
+// Equal implements tin.Comparable.
+func (a Throughput) Equal(t interface{}) bool {
+	if b, ok := t.(Throughput); ok {
+		return reflect.DeepEqual(a, b)
+	}
+	return false
+}
+
+// ThroughputKey represents a StateKey.
+const ThroughputKey StateKey = "Throughput"
+
+// ThroughputService provides the throughput of the network interfaces.
+type ThroughputService struct {
+	reader TrafficCounterReader
+	meter  *throughputMeter
+	state  *State
+	worker *Worker
+	logger *log.Logger
+}
+
+// NewThroughputService returns a tin.ThroughputService.
+func NewThroughputService(r TrafficCounterReader, l *log.Logger) *ThroughputService {
+	s := &ThroughputService{
+		reader: r,
+		meter:  newThroughputMeter(),
+		state:  NewState(),
+		logger: l,
+	}
+
+	// Worker that samples the traffic counters on intervals and updates the state.
+	if r == nil {
+		s.logger.Println(errors.New("failed initializing worker"))
+	} else {
+		s.worker = NewWorker(2*time.Second, func() {
+			counters, err := s.reader.Read()
+			if err != nil {
+				s.logger.Println(fmt.Errorf("worker failed: %w", err))
+			} else {
+				s.SetThroughput(s.meter.sample(counters, time.Now()))
+			}
+		}, s.logger)
+	}
+
+	return s
+}
+
+// Subscribe returns a tin.StateSubscription.
+func (s *ThroughputService) Subscribe() StateSubscription {
+	return s.state.Subscribe()
+}
+
+// Throughput returns a tin.Throughput.
+func (s *ThroughputService) Throughput() Throughput {
+	v, err := s.state.Get(ThroughputKey)
+	if err != nil {
+		return Throughput{}
+	}
+
+	return v.(Throughput)
+}
+
+// SetThroughput updates the state.
+func (s *ThroughputService) SetThroughput(t Throughput) {
+	s.state.Set(ThroughputKey, t)
+}
+
+// throughputMeter computes the throughput from successive samples of the traffic counters.
+type throughputMeter struct {
+	mutex      sync.Mutex
+	previous   map[string]TrafficCounter
+	sessions   map[string]InterfaceThroughput
+	lastSeen   map[string]time.Time
+	sampleTime time.Time
+}
+
+// sessionRetention is how long the session totals of an interface that
+// disappeared are kept, ephemeral interfaces don't accumulate forever.
+const sessionRetention = 10 * time.Minute
+
+// newThroughputMeter returns a throughputMeter.
+func newThroughputMeter() *throughputMeter {
+	return &throughputMeter{
+		previous: map[string]TrafficCounter{},
+		sessions: map[string]InterfaceThroughput{},
+		lastSeen: map[string]time.Time{},
+	}
+}
+
+// sample returns the throughput since the previous sample.
+//
+// An interface that appears has no rate until the next sample. The session
+// totals of an interface that disappears are kept by name for the retention,
+// so they continue when it comes back, for example when a VPN reconnects.
+func (m *throughputMeter) sample(counters []TrafficCounter, now time.Time) Throughput {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	elapsed := now.Sub(m.sampleTime).Seconds()
+	current := map[string]TrafficCounter{}
+	t := Throughput{}
+	for _, c := range counters {
+		current[c.Interface] = c
+		it := InterfaceThroughput{
+			Interface: c.Interface,
+			RxTotal:   c.RxBytes,
+			TxTotal:   c.TxBytes,
+			RxSession: m.sessions[c.Interface].RxSession,
+			TxSession: m.sessions[c.Interface].TxSession,
+		}
+
+		if p, ok := m.previous[c.Interface]; ok {
+			rx := counterDelta(p.RxBytes, c.RxBytes)
+			tx := counterDelta(p.TxBytes, c.TxBytes)
+			it.RxSession += rx
+			it.TxSession += tx
+			if elapsed > 0 {
+				it.RxRate = float64(rx) / elapsed
+				it.TxRate = float64(tx) / elapsed
+			}
+		}
+
+		m.sessions[c.Interface] = it
+		m.lastSeen[c.Interface] = now
+		t = append(t, it)
+	}
+
+	for name, seen := range m.lastSeen {
+		if _, ok := current[name]; !ok && now.Sub(seen) > sessionRetention {
+			delete(m.sessions, name)
+			delete(m.lastSeen, name)
+		}
+	}
+
+	m.previous = current
+	m.sampleTime = now
+
+	sort.Slice(t, func(i, j int) bool { return t[i].Interface < t[j].Interface })
+	return t
+}
+
+// counterWrapMargin is the distance to 2^32 within which a decreasing counter
+// is assumed to have wrapped, it exceeds the traffic of a sample interval.
+const counterWrapMargin = 1 << 30
+
+// counterDelta returns the difference between two samples of a counter.
+//
+// A decreasing counter that was close to 2^32 and is small again is assumed
+// to have wrapped at 32 bits. Otherwise it's assumed to have been reset, for
+// example by recreating the interface, and the current value is the delta.
+func counterDelta(previous, current uint64) uint64 {
+	switch {
+	case current >= previous:
+		return current - previous
+	case previous <= math.MaxUint32 && previous > math.MaxUint32-counterWrapMargin && current < counterWrapMargin:
+		return math.MaxUint32 - previous + current + 1
+	default:
+		return current
+	}
+}
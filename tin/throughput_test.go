@@ -0,0 +1,164 @@
+package tin
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type trafficCounterReaderMock struct{ returnError bool }
+
+func (r trafficCounterReaderMock) Read() ([]TrafficCounter, error) {
+	if r.returnError {
+		return nil, errors.New("error")
+	}
+
+	return []TrafficCounter{{Interface: "wlan0", RxBytes: 1024, TxBytes: 512}}, nil
+}
+
+func TestNewThroughputService(t *testing.T) {
+	tt := []struct {
+		want *ThroughputService
+		got  *ThroughputService
+	}{
+		{
+			want: &ThroughputService{},
+			got:  NewThroughputService(trafficCounterReaderMock{}, log.New(ioutil.Discard, "", log.Flags())),
+		},
+		{
+			want: &ThroughputService{},
+			got:  NewThroughputService(trafficCounterReaderMock{returnError: true}, log.New(ioutil.Discard, "", log.Flags())),
+		},
+		{
+			want: &ThroughputService{},
+			got:  NewThroughputService(nil, log.New(ioutil.Discard, "", log.Flags())),
+		},
+	}
+
+	for _, tc := range tt {
+		if reflect.TypeOf(tc.got) != reflect.TypeOf(tc.want) {
+			t.Errorf("want %v, got %v", reflect.TypeOf(tc.want), reflect.TypeOf(tc.got))
+		}
+	}
+}
+
+func TestThroughputThroughput(t *testing.T) {
+	s := NewThroughputService(nil, log.New(ioutil.Discard, "", log.Flags()))
+	if got := s.Throughput(); len(got) != 0 {
+		t.Errorf("want %v, got %v", Throughput{}, got)
+	}
+
+	want := Throughput{{Interface: "wlan0", RxRate: 10, TxRate: 5}}
+	s.SetThroughput(want)
+
+	got := s.Throughput()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestThroughputMeter(t *testing.T) {
+	m := newThroughputMeter()
+	start := time.Now()
+
+	got := m.sample([]TrafficCounter{
+		{Interface: "wlan0", RxBytes: 1000, TxBytes: 500},
+		{Interface: "lo", RxBytes: 100, TxBytes: 100},
+	}, start)
+	want := Throughput{
+		{Interface: "lo", RxTotal: 100, TxTotal: 100},
+		{Interface: "wlan0", RxTotal: 1000, TxTotal: 500},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// The loopback disappears, a tunnel appears.
+	got = m.sample([]TrafficCounter{
+		{Interface: "wlan0", RxBytes: 3000, TxBytes: 1500},
+		{Interface: "wg0", RxBytes: 50, TxBytes: 50},
+	}, start.Add(2*time.Second))
+	want = Throughput{
+		{Interface: "wg0", RxTotal: 50, TxTotal: 50},
+		{Interface: "wlan0", RxRate: 1000, TxRate: 500, RxTotal: 3000, TxTotal: 1500, RxSession: 2000, TxSession: 1000},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// The counters of wlan0 are reset.
+	got = m.sample([]TrafficCounter{
+		{Interface: "wlan0", RxBytes: 5 << 32, TxBytes: 1500},
+	}, start.Add(4*time.Second))
+	got = m.sample([]TrafficCounter{
+		{Interface: "wlan0", RxBytes: 1000, TxBytes: 1500},
+	}, start.Add(5*time.Second))
+	want = Throughput{
+		{Interface: "wlan0", RxRate: 1000, RxTotal: 1000, TxTotal: 1500, RxSession: 2000 + 5<<32 - 3000 + 1000, TxSession: 1000},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// The tunnel disappears and comes back with new counters, its session continues.
+	m.sample([]TrafficCounter{{Interface: "wg0", RxBytes: 150, TxBytes: 50}}, start.Add(6*time.Second))
+	m.sample([]TrafficCounter{{Interface: "wg0", RxBytes: 250, TxBytes: 50}}, start.Add(7*time.Second))
+	m.sample([]TrafficCounter{}, start.Add(8*time.Second))
+	m.sample([]TrafficCounter{{Interface: "wg0", RxBytes: 10, TxBytes: 10}}, start.Add(9*time.Second))
+	got = m.sample([]TrafficCounter{{Interface: "wg0", RxBytes: 20, TxBytes: 10}}, start.Add(10*time.Second))
+	want = Throughput{
+		{Interface: "wg0", RxRate: 10, RxTotal: 20, TxTotal: 10, RxSession: 110, TxSession: 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// The sessions of interfaces that are gone longer than the retention are dropped.
+	gone := start.Add(10*time.Second + sessionRetention + time.Second)
+	m.sample([]TrafficCounter{}, gone)
+	if len(m.sessions) != 0 || len(m.lastSeen) != 0 {
+		t.Errorf("want no sessions, got %v", m.sessions)
+	}
+	got = m.sample([]TrafficCounter{{Interface: "wg0", RxBytes: 30, TxBytes: 10}}, gone.Add(time.Second))
+	want = Throughput{
+		{Interface: "wg0", RxTotal: 30, TxTotal: 10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestCounterDelta(t *testing.T) {
+	tests := []struct {
+		previous uint64
+		current  uint64
+		want     uint64
+	}{
+		{previous: 100, current: 300, want: 200},
+		{previous: math.MaxUint32 - 99, current: 100, want: 200},
+		{previous: 1 << 40, current: 100, want: 100},
+		// A recreated interface starts with small counters again.
+		{previous: 5000, current: 100, want: 100},
+		{previous: math.MaxUint32 - 99, current: 1 << 31, want: 1 << 31},
+	}
+
+	for _, tt := range tests {
+		got := counterDelta(tt.previous, tt.current)
+		if got != tt.want {
+			t.Errorf("want %v, got %v", tt.want, got)
+		}
+	}
+}
+
+func TestThroughputEqual(t *testing.T) {
+	a := Throughput{{Interface: "wlan0", RxRate: 10}}
+	b := Throughput{{Interface: "wlan0", RxRate: 20}}
+
+	if !a.Equal(a) || a.Equal(b) || a.Equal("wlan0") {
+		t.Errorf("want %v, got %v", "only equal to itself", a)
+	}
+}
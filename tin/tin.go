@@ -1,5 +1,10 @@
 package tin
 
+import (
+	"errors"
+	"strings"
+)
+
 // Comparable is the interface implemented by an object that can
 // compare itself with another instance of the same type.
 //
@@ -7,3 +12,46 @@ package tin
 type Comparable interface {
 	Equal(t interface{}) bool
 }
+
+// Warnings is the error returned together with a usable result when parts of
+// the result couldn't be retrieved, for example lines of output that couldn't
+// be parsed.
+type Warnings []error
+
+// Error implements error.
+func (w Warnings) Error() string {
+	ss := []string{}
+	for _, e := range w {
+		ss = append(ss, e.Error())
+	}
+	return strings.Join(ss, "; ")
+}
+
+// Append returns the warnings with the error appended, the warnings of
+// a tin.Warnings error are appended individually.
+func (w Warnings) Append(err error) Warnings {
+	var ww Warnings
+	switch {
+	case err == nil:
+		return w
+	case errors.As(err, &ww):
+		return append(w, ww...)
+	default:
+		return append(w, err)
+	}
+}
+
+// Err returns the warnings as error, or nil when there aren't any.
+func (w Warnings) Err() error {
+	if len(w) == 0 {
+		return nil
+	}
+	return w
+}
+
+// IsPartial reports whether the error only consists of tin.Warnings, which
+// means the result it has been returned with can be used.
+func IsPartial(err error) bool {
+	var w Warnings
+	return errors.As(err, &w)
+}
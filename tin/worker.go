@@ -1,14 +1,24 @@
 package tin
 
 import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
 	"time"
 )
 
 // Worker executes the task on intervals.
+//
+// The runs of the task don't overlap, they are executed one after another.
 type Worker struct {
-	task   func()
-	ticker *time.Ticker
-	stop   chan struct{}
+	task    func()
+	ticker  *time.Ticker
+	trigger chan struct{}
+	stop    chan struct{}
+	mutex   sync.Mutex
+	paused  func() bool
 }
 
 // Stop stops the ticker and closes the channel.
@@ -17,21 +27,56 @@ func (s *Worker) Stop() {
 	close(s.stop)
 }
 
-// NewWorker returns a tin.Worker.
-func NewWorker(interval time.Duration, task func()) *Worker {
-	go task() // Executes the task immediately.
+// PauseWhile skips the task while the condition is true.
+func (s *Worker) PauseWhile(condition func() bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.paused = condition
+}
+
+// Trigger executes the task immediately, unless the worker is paused.
+//
+// The task is executed once the current run is done, triggers that arrive
+// in the meantime are merged.
+func (s *Worker) Trigger() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// run executes the task unless the worker is paused.
+func (s *Worker) run() {
+	s.mutex.Lock()
+	paused := s.paused
+	s.mutex.Unlock()
+
+	if paused != nil && paused() {
+		return
+	}
+	s.task()
+}
 
+// NewWorker returns a tin.Worker.
+//
+// A panicking task is recovered and reported to the logger, the worker keeps
+// executing the task on the next interval.
+func NewWorker(interval time.Duration, task func(), l *log.Logger) *Worker {
 	w := &Worker{
-		task:   task,
-		ticker: time.NewTicker(interval),
-		stop:   make(chan struct{}, 1),
+		task:    recoverTask(task, l),
+		ticker:  time.NewTicker(interval),
+		trigger: make(chan struct{}, 1),
+		stop:    make(chan struct{}, 1),
 	}
 
 	go func() {
+		w.run() // Executes the task immediately.
 		for {
 			select {
 			case <-w.ticker.C:
-				w.task()
+				w.run()
+			case <-w.trigger:
+				w.run()
 			case <-w.stop:
 				return
 			}
@@ -40,3 +85,60 @@ func NewWorker(interval time.Duration, task func()) *Worker {
 
 	return w
 }
+
+// debounce executes f once the events have stopped for the duration.
+//
+// It returns when the channel is closed.
+func debounce(events <-chan struct{}, d time.Duration, f func()) {
+	timer := time.NewTimer(d)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+			timer.Stop()
+			select {
+			case <-timer.C:
+			default:
+			}
+			timer.Reset(d)
+		case <-timer.C:
+			f()
+		}
+	}
+}
+
+// recoverTask returns the task wrapped with a recovery that reports panics to the logger.
+func recoverTask(task func(), l *log.Logger) func() {
+	return func() {
+		defer func() {
+			if r := recover(); r != nil {
+				l.Println(fmt.Errorf("worker panicked: %v\n%s", r, debug.Stack()))
+			}
+		}()
+
+		task()
+	}
+}
+
+// usable reports the error to the logger and returns whether the result
+// it was returned with can be used.
+func usable(err error, l *log.Logger) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, ErrLocked):
+		l.Println(fmt.Errorf("worker skipped: %w", err))
+		return false
+	case IsPartial(err):
+		l.Println(fmt.Errorf("worker warning: %w", err))
+		return true
+	default:
+		l.Println(fmt.Errorf("worker failed: %w", err))
+		return false
+	}
+}
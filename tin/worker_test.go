@@ -1,14 +1,20 @@
 package tin
 
 import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
 	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
 
 func TestNewWorker(t *testing.T) {
 	want := &Worker{}
-	got := NewWorker(time.Second, func() {})
+	got := NewWorker(time.Second, func() {}, log.New(ioutil.Discard, "", 0))
 
 	if reflect.TypeOf(got) != reflect.TypeOf(want) {
 		t.Errorf("want %v, got %v", reflect.TypeOf(want), reflect.TypeOf(got))
@@ -19,7 +25,7 @@ func TestWorkerProcess(t *testing.T) {
 	want := 7
 	var got int
 
-	worker := NewWorker(time.Millisecond, func() { got = 7 })
+	worker := NewWorker(time.Millisecond, func() { got = 7 }, log.New(ioutil.Discard, "", 0))
 	time.Sleep(2 * time.Millisecond)
 
 	if got != want {
@@ -36,3 +42,126 @@ func TestWorkerProcess(t *testing.T) {
 		t.Errorf("want %v, got %v", want, got)
 	}
 }
+
+// writerFunc implements io.Writer.
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+func TestWorkerRecover(t *testing.T) {
+	logged := make(chan string, 1)
+	l := log.New(writerFunc(func(p []byte) (int, error) {
+		logged <- string(p)
+		return len(p), nil
+	}), "", 0)
+
+	worker := NewWorker(time.Hour, func() { panic("boom") }, l)
+	defer worker.Stop()
+
+	select {
+	case got := <-logged:
+		if !strings.Contains(got, "worker panicked: boom") {
+			t.Errorf("want %v, got %v", "worker panicked: boom", got)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("want %v, got %v", "worker panicked: boom", nil)
+	}
+}
+
+func TestUsable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: nil, want: true},
+		{err: Warnings{errors.New("warning")}, want: true},
+		{err: fmt.Errorf("wrapped: %w", Warnings{errors.New("warning")}), want: true},
+		{err: errors.New("error"), want: false},
+		{err: fmt.Errorf("db.lck: %w", ErrLocked), want: false},
+	}
+
+	for _, tt := range tests {
+		got := usable(tt.err, log.New(ioutil.Discard, "", 0))
+		if got != tt.want {
+			t.Errorf("want %v, got %v", tt.want, got)
+		}
+	}
+}
+
+func TestWorkerPauseWhile(t *testing.T) {
+	var paused, runs int32
+	worker := NewWorker(time.Millisecond, func() { atomic.AddInt32(&runs, 1) }, log.New(ioutil.Discard, "", 0))
+	defer worker.Stop()
+
+	worker.PauseWhile(func() bool { return atomic.LoadInt32(&paused) == 1 })
+	atomic.StoreInt32(&paused, 1)
+	time.Sleep(5 * time.Millisecond)
+
+	before := atomic.LoadInt32(&runs)
+	time.Sleep(5 * time.Millisecond)
+	if got := atomic.LoadInt32(&runs); got != before {
+		t.Errorf("want %v, got %v", before, got)
+	}
+
+	atomic.StoreInt32(&paused, 0)
+	time.Sleep(5 * time.Millisecond)
+	if got := atomic.LoadInt32(&runs); got == before {
+		t.Errorf("want more than %v, got %v", before, got)
+	}
+}
+
+func TestWorkerTrigger(t *testing.T) {
+	var running, overlaps, runs int32
+	release := make(chan struct{})
+	worker := NewWorker(time.Hour, func() {
+		if atomic.AddInt32(&running, 1) > 1 {
+			atomic.AddInt32(&overlaps, 1)
+		}
+		<-release
+		atomic.AddInt32(&running, -1)
+		atomic.AddInt32(&runs, 1)
+	}, log.New(ioutil.Discard, "", 0))
+	defer worker.Stop()
+
+	// The triggers during the first run are merged into a single run.
+	for i := 0; i < 5; i++ {
+		worker.Trigger()
+	}
+	release <- struct{}{}
+	release <- struct{}{}
+
+	select {
+	case release <- struct{}{}:
+		t.Errorf("want %v, got %v", 2, "another run")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if got := atomic.LoadInt32(&runs); got != 2 {
+		t.Errorf("want %v, got %v", 2, got)
+	}
+	if got := atomic.LoadInt32(&overlaps); got != 0 {
+		t.Errorf("want %v, got %v", 0, got)
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	events := make(chan struct{})
+	calls := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		debounce(events, 20*time.Millisecond, func() { calls <- struct{}{} })
+		close(done)
+	}()
+
+	for i := 0; i < 5; i++ {
+		events <- struct{}{}
+		time.Sleep(2 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	close(events)
+	<-done
+	if got := len(calls); got != 1 {
+		t.Errorf("want %v, got %v", 1, got)
+	}
+}